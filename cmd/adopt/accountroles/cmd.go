@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	prefix   string
+	hostedCP bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "Adopt account roles created outside of rosa",
+	Long: "Add the tags that rosa uses to find the account roles to roles created with other tools, " +
+		"like Terraform or CloudFormation. The trust and permission policies of the roles are verified " +
+		"first, and the roles are only tagged if all of them match the policies expected by ROSA.",
+	Example: `  # Adopt the account roles with prefix 'myprefix'
+  rosa adopt account-roles --prefix myprefix
+
+  # Print the AWS CLI commands that tag the hosted control plane account roles
+  rosa adopt account-roles --prefix myprefix --hosted-cp --mode manual`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.prefix,
+		"prefix",
+		"p",
+		aws.DefaultPrefix,
+		"User-defined prefix of the account roles",
+	)
+
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"Adopt the account roles of hosted control planes (HyperShift)",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if mode == "" {
+		mode = aws.ModeAuto
+	}
+
+	env, err := ocm.GetEnv()
+	if err != nil {
+		r.Reporter.Errorf("Failed to determine OCM environment: %v", err)
+		os.Exit(1)
+	}
+
+	policies, err := r.OCMClient.GetPolicies("AccountRole")
+	if err != nil {
+		r.Reporter.Errorf("Failed to fetch the account role policies: %v", err)
+		os.Exit(1)
+	}
+
+	// The policies are compared with the latest ones, so the roles are compatible with the latest
+	// version:
+	policyVersion, err := r.OCMClient.GetPolicyVersion("", ocm.DefaultChannelGroup)
+	if err != nil {
+		r.Reporter.Errorf("Error getting version: %s", err)
+		os.Exit(1)
+	}
+
+	accountRoles := aws.AccountRoles
+	if args.hostedCP {
+		accountRoles = aws.HCPAccountRoles
+	}
+	files := make([]string, 0, len(accountRoles))
+	for file := range accountRoles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	adoptedRoles := []roles.AdoptedRole{}
+	refused := false
+	for _, file := range files {
+		roleName := aws.GetRoleName(args.prefix, accountRoles[file].Name)
+		roleTags, err := r.AWSClient.GetRoleTags(roleName)
+		if err != nil {
+			if errors.GetType(err) == errors.NotFound {
+				r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+				refused = true
+				continue
+			}
+			r.Reporter.Errorf("Failed to get role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		if roleTags[tags.RoleType] != "" {
+			r.Reporter.Infof("Role '%s' is already managed by ROSA", roleName)
+			continue
+		}
+
+		verification, err := roles.VerifyAccountRole(r, env, file, roleName, policies)
+		if err != nil {
+			r.Reporter.Errorf("Failed to verify role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		if verification.Drift {
+			r.Reporter.Warnf("Role '%s' can't be adopted because its policies don't match the ones "+
+				"expected by ROSA", roleName)
+			refused = true
+			continue
+		}
+
+		tagList := map[string]string{
+			tags.OpenShiftVersion: policyVersion,
+			tags.RolePrefix:       args.prefix,
+			tags.RoleType:         file,
+			tags.RedHatManaged:    tags.True,
+		}
+		policyTags := map[string]string{}
+		for key, value := range tagList {
+			policyTags[key] = value
+		}
+		if verification.ManagedPolicies {
+			tagList[tags.ManagedPolicies] = tags.True
+		}
+		if args.hostedCP {
+			tagList[tags.HypershiftPolicies] = tags.True
+		}
+		adoptedRoles = append(adoptedRoles, roles.AdoptedRole{
+			Name:       roleName,
+			Tags:       tagList,
+			PolicyARN:  verification.PolicyARN,
+			PolicyTags: policyTags,
+		})
+	}
+
+	if refused {
+		r.OCMClient.LogEvent("ROSAAdoptAccountRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("The account roles with prefix '%s' weren't adopted. Revert the changes "+
+			"reported above, or create the account roles with 'rosa create account-roles'", args.prefix)
+		os.Exit(1)
+	}
+	if len(adoptedRoles) == 0 {
+		r.Reporter.Infof("The account roles with prefix '%s' are already managed by ROSA", args.prefix)
+		return
+	}
+
+	err = roles.AdoptRoles(r, mode, adoptedRoles)
+	if err != nil {
+		r.OCMClient.LogEvent("ROSAAdoptAccountRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSAAdoptAccountRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+}
@@ -0,0 +1,298 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+const (
+	PrefixFlag       = "prefix"
+	OidcConfigIdFlag = "oidc-config-id"
+	HostedCpFlag     = "hosted-cp"
+)
+
+var args struct {
+	prefix       string
+	oidcConfigId string
+	hostedCp     bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "Adopt operator roles created outside of rosa",
+	Long: "Add the tags that rosa uses to find the operator roles to roles created with other tools, " +
+		"like Terraform or CloudFormation. The trust and permission policies of the roles are verified " +
+		"first, and the roles are only tagged if all of them match the policies expected by ROSA.",
+	Example: `  # Adopt the operator roles of a cluster
+  rosa adopt operator-roles --cluster=mycluster
+
+  # Adopt the operator roles with prefix 'myprefix' that trust an OIDC configuration
+  rosa adopt operator-roles --prefix=myprefix --oidc-config-id=<oidc-config-id>`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	ocm.AddOptionalClusterFlag(Cmd)
+
+	flags.StringVar(
+		&args.prefix,
+		PrefixFlag,
+		"",
+		"User-defined prefix of the operator roles. Not to be used alongside --cluster flag.",
+	)
+
+	flags.StringVar(
+		&args.oidcConfigId,
+		OidcConfigIdFlag,
+		"",
+		"Registered OIDC configuration ID that the operator roles trust. Required with the --prefix flag.",
+	)
+
+	flags.BoolVar(
+		&args.hostedCp,
+		HostedCpFlag,
+		false,
+		"Indicates whether to adopt the hosted control planes operator roles when using --prefix option.",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if mode == "" {
+		mode = aws.ModeAuto
+	}
+
+	clusterFlag := cmd.Flags().Changed("cluster")
+	if clusterFlag == (args.prefix != "") {
+		r.Reporter.Errorf("Either a cluster key or an operator roles prefix is required")
+		os.Exit(1)
+	}
+	if clusterFlag && (args.oidcConfigId != "" || cmd.Flags().Changed(HostedCpFlag)) {
+		r.Reporter.Errorf("Flags '%s' and '%s' can't be used alongside the '--cluster' flag",
+			OidcConfigIdFlag, HostedCpFlag)
+		os.Exit(1)
+	}
+	// The trust policies can only be verified when the OIDC endpoint is known:
+	if !clusterFlag && args.oidcConfigId == "" {
+		r.Reporter.Errorf("Flag '%s' is required alongside the '--%s' flag", OidcConfigIdFlag, PrefixFlag)
+		os.Exit(1)
+	}
+
+	hostedCP := args.hostedCp
+	prefix := args.prefix
+	// The operator policies are named after the prefix of the account roles, which create
+	// operator-roles takes from the installer role of the cluster:
+	var policyPrefix string
+	var oidcEndpointURL string
+	var cluster *cmv1.Cluster
+	if clusterFlag {
+		r.GetClusterKey()
+		cluster = r.FetchCluster()
+		if cluster.AWS().STS().RoleARN() == "" {
+			r.Reporter.Errorf("Cluster '%s' is not an STS cluster", r.ClusterKey)
+			os.Exit(1)
+		}
+		hostedCP = cluster.Hypershift().Enabled()
+		prefix = cluster.AWS().STS().OperatorRolePrefix()
+		oidcEndpointURL = cluster.AWS().STS().OIDCEndpointURL()
+		policyPrefix, err = aws.GetOperatorRolePolicyPrefixFromCluster(cluster, r.AWSClient)
+		if err != nil {
+			r.Reporter.Errorf("Failed to get the prefix of the operator policies: %v", err)
+			os.Exit(1)
+		}
+	} else {
+		oidcConfig, err := r.OCMClient.GetOidcConfig(args.oidcConfigId)
+		if err != nil {
+			r.Reporter.Errorf("There was a problem retrieving OIDC Config '%s': %v", args.oidcConfigId, err)
+			os.Exit(1)
+		}
+		oidcEndpointURL = oidcConfig.IssuerUrl()
+	}
+
+	credRequests, err := r.OCMClient.GetCredRequests(hostedCP)
+	if err != nil {
+		r.Reporter.Errorf("Error getting operator credential request from OCM %v", err)
+		os.Exit(1)
+	}
+	policies, err := r.OCMClient.GetPolicies("OperatorRole")
+	if err != nil {
+		r.Reporter.Errorf("Failed to fetch the operator role policies: %v", err)
+		os.Exit(1)
+	}
+	policyVersion, err := r.OCMClient.GetPolicyVersion("", ocm.DefaultChannelGroup)
+	if err != nil {
+		r.Reporter.Errorf("Error getting version: %s", err)
+		os.Exit(1)
+	}
+
+	keys := make([]string, 0, len(credRequests))
+	for key := range credRequests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	adoptedRoles := []roles.AdoptedRole{}
+	refused := false
+	for _, key := range keys {
+		operator := credRequests[key]
+		var roleName string
+		if cluster != nil {
+			name, found := aws.FindOperatorRoleNameBySTSOperator(cluster, operator)
+			if !found {
+				continue
+			}
+			roleName = name
+		} else {
+			roleName, err = aws.GetResourceIdFromARN(aws.ComputeOperatorRoleArn(prefix, operator,
+				r.Creator, ""))
+			if err != nil {
+				r.Reporter.Errorf("%v", err)
+				os.Exit(1)
+			}
+		}
+
+		roleTags, err := r.AWSClient.GetRoleTags(roleName)
+		if err != nil {
+			if errors.GetType(err) == errors.NotFound {
+				r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+				refused = true
+				continue
+			}
+			r.Reporter.Errorf("Failed to get role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		if roleTags[tags.OperatorName] != "" {
+			r.Reporter.Infof("Role '%s' is already managed by ROSA", roleName)
+			continue
+		}
+
+		verification, err := roles.VerifyOperatorRole(r, key, roleName, operator, oidcEndpointURL, policies)
+		if err != nil {
+			r.Reporter.Errorf("Failed to verify role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		if verification.Drift {
+			r.Reporter.Warnf("Role '%s' can't be adopted because its policies don't match the ones "+
+				"expected by ROSA", roleName)
+			refused = true
+			continue
+		}
+
+		tagList := map[string]string{
+			tags.OperatorNamespace: operator.Namespace(),
+			tags.OperatorName:      operator.Name(),
+			tags.RedHatManaged:     tags.True,
+		}
+		if cluster != nil && !ocm.IsOidcConfigReusable(cluster) {
+			tagList[tags.ClusterID] = cluster.ID()
+		}
+		if verification.ManagedPolicies {
+			tagList[tags.ManagedPolicies] = tags.True
+		}
+		if hostedCP {
+			tagList[tags.HypershiftPolicies] = tags.True
+		}
+		policyTags := map[string]string{
+			tags.OpenShiftVersion:  policyVersion,
+			tags.RedHatManaged:     tags.True,
+			tags.OperatorNamespace: operator.Namespace(),
+			tags.OperatorName:      operator.Name(),
+		}
+		rolePrefix := policyPrefix
+		if cluster == nil {
+			rolePrefix = operatorPolicyPrefix(verification.PolicyARN, operator)
+		}
+		if rolePrefix != "" {
+			policyTags[tags.RolePrefix] = rolePrefix
+		}
+		adoptedRoles = append(adoptedRoles, roles.AdoptedRole{
+			Name:       roleName,
+			Tags:       tagList,
+			PolicyARN:  verification.PolicyARN,
+			PolicyTags: policyTags,
+		})
+	}
+
+	if refused {
+		r.OCMClient.LogEvent("ROSAAdoptOperatorRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("The operator roles with prefix '%s' weren't adopted. Revert the changes "+
+			"reported above, or create the operator roles with 'rosa create operator-roles'", prefix)
+		os.Exit(1)
+	}
+	if len(adoptedRoles) == 0 {
+		r.Reporter.Infof("The operator roles with prefix '%s' are already managed by ROSA", prefix)
+		return
+	}
+
+	err = roles.AdoptRoles(r, mode, adoptedRoles)
+	if err != nil {
+		r.OCMClient.LogEvent("ROSAAdoptOperatorRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSAAdoptOperatorRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+}
+
+// operatorPolicyPrefix returns the account roles prefix that the name of the given operator policy
+// starts with, or an empty string if the policy isn't named like the ones created by ROSA, for
+// example because the name was truncated.
+func operatorPolicyPrefix(policyARN string, operator *cmv1.STSOperator) string {
+	if policyARN == "" {
+		return ""
+	}
+	policyName, err := aws.GetResourceIdFromARN(policyARN)
+	if err != nil {
+		return ""
+	}
+	suffix := fmt.Sprintf("-%s-%s", operator.Namespace(), operator.Name())
+	if !strings.HasSuffix(policyName, suffix) {
+		return ""
+	}
+	return strings.TrimSuffix(policyName, suffix)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	filename string
+	prune    bool
+	dryRun   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a cluster spec file to an existing cluster",
+	Long: "Reconcile the machine pools, identity providers, ingresses, tuning configs and add-ons of an " +
+		"existing cluster with the ones described in a cluster spec file. The changes are shown before " +
+		"they are applied.",
+	Example: `  # Show the changes needed to make the cluster match the file
+  rosa apply -f mycluster.yaml --dry-run
+
+  # Apply the file, deleting resources of the cluster that aren't in it
+  rosa apply -f mycluster.yaml --prune
+
+  # Apply the file to a cluster with a different name
+  rosa apply -f mycluster.yaml --cluster=mycluster-staging`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.filename,
+		"filename",
+		"f",
+		"",
+		"Path of the YAML or JSON cluster spec file to apply.",
+	)
+	Cmd.MarkFlagRequired("filename")
+	ocm.AddOptionalClusterFlag(Cmd)
+	flags.BoolVar(
+		&args.prune,
+		"prune",
+		false,
+		"Delete resources of the cluster that aren't described in the file. Only the kinds of resources "+
+			"present in the file are pruned.",
+	)
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Show the changes that would be applied without applying them.",
+	)
+	confirm.AddFlag(flags)
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime()
+
+	spec, err := clusterspec.Load(args.filename)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	// The cluster is the one named in the file, unless given explicitly:
+	if !cmd.Flags().Changed("cluster") {
+		if spec.Name == "" {
+			r.Reporter.Errorf("File '%s' doesn't contain a cluster name, use '--cluster' to select one",
+				args.filename)
+			os.Exit(1)
+		}
+		ocm.SetClusterKey(spec.Name)
+	}
+
+	r = r.WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+	if cluster.State() != cmv1.ClusterStateReady {
+		r.Reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	r.Reporter.Debugf("Loading resources of cluster '%s'", clusterKey)
+	resources, err := clusterspec.FetchResources(r.OCMClient, cluster)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	plan, err := clusterspec.BuildPlan(cluster, resources, spec, args.prune)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if plan.Empty() {
+		r.Reporter.Infof("Cluster '%s' is up to date", clusterKey)
+		return
+	}
+
+	fmt.Printf("Changes to cluster '%s':\n%s", clusterKey, plan)
+	if args.dryRun {
+		return
+	}
+	if !confirm.Confirm("apply these changes to cluster '%s'", clusterKey) {
+		os.Exit(0)
+	}
+
+	err = plan.Apply(r.OCMClient, cluster.ID(), func(change *clusterspec.Change) {
+		r.Reporter.Infof("Applying %s", change)
+	})
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	r.Reporter.Infof("Cluster '%s' is up to date", clusterKey)
+}
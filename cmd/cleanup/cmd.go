@@ -0,0 +1,238 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/orphans"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	dryRun    bool
+	olderThan time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete AWS resources left behind by deleted clusters",
+	Long: "Delete the operator roles, operator policies and OIDC providers that no longer belong to any " +
+		"cluster, together with the S3 buckets and the private key secrets of unmanaged OIDC " +
+		"configurations. Resources are deleted in dependency order and each deletion is confirmed " +
+		"unless '--yes' is used. Use 'rosa list orphaned-resources' to see what would be considered.",
+	Example: `  # Show what would be deleted
+  rosa cleanup --dry-run
+
+  # Delete the resources created more than 30 days ago, asking for confirmation
+  rosa cleanup --older-than 720h
+
+  # Print the AWS CLI commands that delete the resources instead of running them
+  rosa cleanup --mode manual`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Show the resources that would be deleted without deleting them.",
+	)
+	flags.DurationVar(
+		&args.olderThan,
+		"older-than",
+		0,
+		"Only delete resources created more than this time ago, for example '720h'. Resources whose "+
+			"creation date is unknown are kept when this is set.",
+	)
+	aws.AddModeFlag(Cmd)
+	confirm.AddFlag(flags)
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if mode == "" {
+		mode = aws.ModeAuto
+	}
+
+	var spin *spinner.Spinner
+	if r.Reporter.IsTerminal() {
+		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	}
+	if spin != nil {
+		r.Reporter.Infof("Looking for orphaned resources in AWS account '%s'", r.Creator.AccountID)
+		spin.Start()
+	}
+
+	resources, err := orphans.Find(r.AWSClient, r.OCMClient, r.Creator.AccountID)
+
+	if spin != nil {
+		spin.Stop()
+	}
+
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	deletions := orphans.Plan(resources, args.olderThan, time.Now())
+	if len(deletions) == 0 {
+		r.Reporter.Infof("There are no orphaned resources to delete")
+		return
+	}
+
+	if args.dryRun {
+		for _, deletion := range deletions {
+			r.Reporter.Infof("Would delete %s: %s", deletion, deletion.Resource.Reason)
+		}
+		return
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		r.OCMClient.LogEvent("ROSACleanupModeAuto", nil)
+		region := r.AWSClient.GetRegion()
+		failed := 0
+		for _, deletion := range deletions {
+			// Buckets and secrets are regional, so they can only be deleted with a client for
+			// their region:
+			if deletion.Region != "" && deletion.Region != region {
+				r.Reporter.Warnf("Skipping %s because it is in region '%s', use '--region %s' to delete it",
+					deletion, deletion.Region, deletion.Region)
+				continue
+			}
+			if !confirm.Confirm("delete %s", deletion) {
+				continue
+			}
+			r.Reporter.Infof("Deleting %s", deletion)
+			err = orphans.Delete(r.AWSClient, deletion)
+			if err != nil {
+				r.Reporter.Warnf("Failed to delete %s: %v", deletion, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			r.Reporter.Errorf("Failed to delete %d resources", failed)
+			os.Exit(1)
+		}
+		r.Reporter.Infof("Successfully cleaned up the orphaned resources")
+	case aws.ModeManual:
+		r.OCMClient.LogEvent("ROSACleanupModeManual", nil)
+		commands, err := buildCommands(r, deletions)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Run the following commands to delete the orphaned resources:\n")
+		}
+		fmt.Println(awscb.JoinCommands(commands))
+	default:
+		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+		os.Exit(1)
+	}
+}
+
+func buildCommands(r *rosa.Runtime, deletions []*orphans.Deletion) ([]string, error) {
+	var roles []string
+	for _, deletion := range deletions {
+		if deletion.Kind == orphans.OperatorRole {
+			roles = append(roles, deletion.Name)
+		}
+	}
+	policies, err := r.AWSClient.GetPolicies(roles)
+	if err != nil {
+		return nil, fmt.Errorf("There was an error getting the policies of the operator roles: %v", err)
+	}
+	var commands []string
+	for _, deletion := range deletions {
+		switch deletion.Kind {
+		case orphans.OperatorRole:
+			for _, policyARN := range policies[deletion.Name] {
+				commands = append(commands, awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.DetachRolePolicy).
+					AddParam(awscb.RoleName, deletion.Name).
+					AddParam(awscb.PolicyArn, policyARN).
+					Build())
+			}
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeleteRole).
+				AddParam(awscb.RoleName, deletion.Name).
+				Build())
+			if deletion.Resource.ManagedPolicies {
+				continue
+			}
+			for _, policyARN := range policies[deletion.Name] {
+				commands = append(commands, awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.DeletePolicy).
+					AddParam(awscb.PolicyArn, policyARN).
+					Build())
+			}
+		case orphans.OperatorPolicy:
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeletePolicy).
+				AddParam(awscb.PolicyArn, deletion.Name).
+				Build())
+		case orphans.OIDCProvider:
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeleteOpenIdConnectProvider).
+				AddParam(awscb.OpenIdConnectProviderArn, deletion.Name).
+				Build())
+		case orphans.S3Bucket:
+			commands = append(commands, awscb.NewS3CommandBuilder().
+				SetCommand(awscb.Remove).
+				AddValueNoParam(fmt.Sprintf("s3://%s", deletion.Name)).
+				AddParamNoValue(awscb.Recursive).
+				AddParam(awscb.Region, deletion.Region).
+				Build())
+			commands = append(commands, awscb.NewS3CommandBuilder().
+				SetCommand(awscb.RemoveBucket).
+				AddValueNoParam(fmt.Sprintf("s3://%s", deletion.Name)).
+				AddParam(awscb.Region, deletion.Region).
+				Build())
+		case orphans.PrivateKeySecret:
+			commands = append(commands, awscb.NewSecretsManagerCommandBuilder().
+				SetCommand(awscb.DeleteSecret).
+				AddParam(awscb.SecretID, deletion.Name).
+				AddParam(awscb.Region, deletion.Region).
+				Build())
+		}
+	}
+	return commands, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/openshift/rosa/cmd/verify/oc"
 	"github.com/openshift/rosa/cmd/verify/quota"
 	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -128,6 +129,8 @@ func init() {
 	flags.MarkHidden("hosted-cp")
 
 	aws.AddModeFlag(Cmd)
+	aws.AddCloudFormationMode(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -136,7 +139,7 @@ func init() {
 func run(cmd *cobra.Command, argv []string) {
 	r := rosa.NewRuntime()
 
-	mode, err := aws.GetMode()
+	mode, err := aws.GetMode(aws.ModeCloudFormation)
 	if err != nil {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
@@ -382,14 +385,60 @@ func run(cmd *cobra.Command, argv []string) {
 			r.Reporter.Errorf("%s", err)
 			os.Exit(1)
 		}
+		commands, err = aws.FormatCommands(commands, awscb.TemplateParameters{
+			Prefix:              prefix,
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the account roles and policies:\n")
+			r.Reporter.Infof("%s", aws.ManualInstructions("create the account roles and policies"))
 		}
 		r.OCMClient.LogEvent("ROSACreateAccountRolesModeManual", map[string]string{
 			ocm.Version: policyVersion,
 		})
 		fmt.Println(commands)
+	case aws.ModeCloudFormation:
+		err = aws.GeneratePolicyFiles(r.Reporter, env, true, false, policies, nil, managedPolicies)
+		if err != nil {
+			r.Reporter.Errorf("There was an error generating the policy files: %s", err)
+			r.OCMClient.LogEvent("ROSACreateAccountRolesModeCloudFormation", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		commands, err := rolesCreator.buildCommands(input)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		stackName := aws.GetStackName("rosa", prefix, "account-roles")
+		r.Reporter.Infof("Creating or updating CloudFormation stack '%s' using '%s'", stackName, r.Creator.ARN)
+		manual, err := aws.EnsureStackFromCommands(r.AWSClient, stackName, commands, awscb.TemplateParameters{
+			Prefix:              prefix,
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("There was an error creating the account roles: %s", err)
+			r.OCMClient.LogEvent("ROSACreateAccountRolesModeCloudFormation", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		r.Reporter.Infof("CloudFormation stack '%s' is ready", stackName)
+		if len(manual) > 0 {
+			r.Reporter.Infof("Run the following commands to finish the creation of the account roles:\n")
+			fmt.Println(awscb.JoinCommands(manual))
+		}
+		r.OCMClient.LogEvent("ROSACreateAccountRolesModeCloudFormation", map[string]string{
+			ocm.Response: ocm.Success,
+			ocm.Version:  policyVersion,
+		})
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
@@ -37,6 +37,7 @@ import (
 	installLogs "github.com/openshift/rosa/cmd/logs/install"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/clusterspec"
 	"github.com/openshift/rosa/pkg/fedramp"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/helper/roles"
@@ -45,6 +46,7 @@ import (
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/properties"
+	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -150,6 +152,10 @@ var args struct {
 	// Hypershift options:
 	hostedClusterEnabled bool
 	billingAccount       string
+	auditLogRoleARN      string
+
+	// Declarative cluster spec file
+	fromFile string
 }
 
 var Cmd = &cobra.Command{
@@ -160,7 +166,10 @@ var Cmd = &cobra.Command{
   rosa create cluster --cluster-name=mycluster
 
   # Create a cluster in the us-east-2 region
-  rosa create cluster --cluster-name=mycluster --region=us-east-2`,
+  rosa create cluster --cluster-name=mycluster --region=us-east-2
+
+  # Create a cluster from a spec file, overriding the name given in the file
+  rosa create cluster --from-file=mycluster.yaml --cluster-name=mycluster-2`,
 	Run: run,
 }
 
@@ -589,6 +598,22 @@ func init() {
 	)
 	flags.MarkHidden("billing-account")
 
+	flags.StringVar(
+		&args.auditLogRoleARN,
+		"audit-log-arn",
+		"",
+		"The ARN of the role that is used to forward the audit logs of hosted clusters to AWS CloudWatch",
+	)
+	flags.MarkHidden("audit-log-arn")
+
+	flags.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Path to a YAML or JSON cluster spec file. Flags given on the command line override "+
+			"the values in the file.",
+	)
+
 	aws.AddModeFlag(Cmd)
 	interactive.AddFlag(flags)
 	output.AddFlag(Cmd)
@@ -600,7 +625,30 @@ func networkTypeCompletion(cmd *cobra.Command, args []string, toComplete string)
 }
 
 func run(cmd *cobra.Command, _ []string) {
-	r := rosa.NewRuntime().WithAWS().WithOCM()
+	r := rosa.NewRuntime()
+
+	// The spec file has to be loaded before anything else, as it can change the region or profile
+	// used to build the clients:
+	if args.fromFile != "" {
+		spec, err := clusterspec.Load(args.fromFile)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		err = spec.ApplyFlags(cmd.Flags())
+		if err != nil {
+			r.Reporter.Errorf("Failed to apply cluster spec file '%s': %s", args.fromFile, err)
+			os.Exit(1)
+		}
+		if spec.HasResources() {
+			r.Reporter.Warnf("Machine pools, identity providers, ingresses, tuning configs and add-ons in "+
+				"'%s' are not created along with the cluster, use 'rosa apply -f %s' once it is ready",
+				args.fromFile, args.fromFile)
+		}
+		r.Reporter.Debugf("Loaded cluster spec from '%s'", args.fromFile)
+	}
+
+	r = r.WithAWS().WithOCM()
 	defer r.Cleanup()
 
 	supportedRegions, err := r.OCMClient.GetDatabaseRegionList()
@@ -740,6 +788,19 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	auditLogRoleARN := args.auditLogRoleARN
+	if auditLogRoleARN != "" {
+		if !isHostedCP {
+			r.Reporter.Errorf("Audit log forwarding is only supported for Hosted Control Plane clusters")
+			os.Exit(1)
+		}
+		err = aws.ARNValidator(auditLogRoleARN)
+		if err != nil {
+			r.Reporter.Errorf("Expected a valid role ARN for audit log forwarding: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	if isHostedCP && cmd.Flags().Changed("default-mp-labels") {
 		r.Reporter.Errorf("Setting the default machine pool labels is not supported for hosted clusters")
 		os.Exit(1)
@@ -2247,7 +2308,8 @@ func run(cmd *cobra.Command, _ []string) {
 		Hypershift: ocm.Hypershift{
 			Enabled: isHostedCP,
 		},
-		BillingAccount: billingAccount,
+		BillingAccount:  billingAccount,
+		AuditLogRoleARN: auditLogRoleARN,
 	}
 
 	if oidcConfig != nil {
@@ -2305,9 +2367,9 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := r.OCMClient.CreateCluster(clusterConfig)
 	if err != nil {
 		if args.dryRun {
-			r.Reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
+			r.Reporter.WithCode(reporter.CodeCreateCluster).Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
 		} else {
-			r.Reporter.Errorf("Failed to create cluster: %s", err)
+			r.Reporter.WithCode(reporter.CodeCreateCluster).Errorf("Failed to create cluster: %s", err)
 		}
 		os.Exit(1)
 	}
@@ -2787,6 +2849,9 @@ func buildCommand(spec ocm.Spec, operatorRolesPrefix string,
 	if spec.EtcdEncryptionKMSArn != "" {
 		command += fmt.Sprintf(" --etcd-encryption-kms-arn %s", spec.EtcdEncryptionKMSArn)
 	}
+	if spec.AuditLogRoleARN != "" {
+		command += fmt.Sprintf(" --audit-log-arn %s", spec.AuditLogRoleARN)
+	}
 
 	return command
 }
@@ -102,6 +102,7 @@ func init() {
 	flags.MarkHidden("mp")
 
 	aws.AddModeFlag(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -303,10 +304,6 @@ func run(cmd *cobra.Command, argv []string) {
 			})
 			os.Exit(1)
 		}
-		if r.Reporter.IsTerminal() {
-			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the ocm role and policies:\n")
-		}
 		var commands string
 		commands, err = buildCommands(
 			prefix,
@@ -323,7 +320,19 @@ func run(cmd *cobra.Command, argv []string) {
 			r.Reporter.Errorf("Failed to generate commands for manual mode: %v", err)
 			os.Exit(1)
 		}
-
+		commands, err = aws.FormatCommands(commands, awscb.TemplateParameters{
+			Prefix:              prefix,
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("All policy files saved to the current directory")
+			r.Reporter.Infof("%s", aws.ManualInstructions("create the ocm role and policies"))
+		}
 		fmt.Println(commands)
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
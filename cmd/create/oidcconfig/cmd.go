@@ -109,6 +109,7 @@ func init() {
 	)
 
 	aws.AddModeFlag(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -313,11 +314,6 @@ type CreateUnmanagedOidcConfigAutoStrategy struct {
 	oidcConfig *oidc_config.OidcConfigInput
 }
 
-const (
-	discoveryDocumentKey = ".well-known/openid-configuration"
-	jwksKey              = "keys.json"
-)
-
 func (s *CreateUnmanagedOidcConfigAutoStrategy) execute(r *rosa.Runtime) {
 	bucketUrl := s.oidcConfig.IssuerUrl
 	bucketName := s.oidcConfig.BucketName
@@ -340,13 +336,13 @@ func (s *CreateUnmanagedOidcConfigAutoStrategy) execute(r *rosa.Runtime) {
 		os.Exit(1)
 	}
 	err = r.AWSClient.PutPublicReadObjectInS3Bucket(
-		bucketName, strings.NewReader(discoveryDocument), discoveryDocumentKey)
+		bucketName, strings.NewReader(discoveryDocument), oidc_config.DiscoveryDocumentKey)
 	if err != nil {
 		r.Reporter.Errorf("There was a problem populating discovery "+
 			"document to S3 bucket '%s': %s", bucketName, err)
 		os.Exit(1)
 	}
-	err = r.AWSClient.PutPublicReadObjectInS3Bucket(bucketName, bytes.NewReader(jwks), jwksKey)
+	err = r.AWSClient.PutPublicReadObjectInS3Bucket(bucketName, bytes.NewReader(jwks), oidc_config.JwksKey)
 	if err != nil {
 		if spin != nil {
 			spin.Stop()
@@ -464,7 +460,7 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		SetCommand(awscb.PutObject).
 		AddParam(awscb.Body, fmt.Sprintf("./%s", discoveryDocumentFilename)).
 		AddParam(awscb.Bucket, bucketName).
-		AddParam(awscb.Key, discoveryDocumentKey).
+		AddParam(awscb.Key, oidc_config.DiscoveryDocumentKey).
 		AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
 		Build()
 	commands = append(commands, putDiscoveryDocumentCommand)
@@ -479,7 +475,7 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		SetCommand(awscb.PutObject).
 		AddParam(awscb.Body, fmt.Sprintf("./%s", jwksFilename)).
 		AddParam(awscb.Bucket, bucketName).
-		AddParam(awscb.Key, jwksKey).
+		AddParam(awscb.Key, oidc_config.JwksKey).
 		AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
 		Build()
 	commands = append(commands, putJwksCommand)
@@ -496,7 +492,12 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		Build()
 	commands = append(commands, createSecretCommand)
 	commands = append(commands, fmt.Sprintf("rm %s", privateKeyFilename))
-	fmt.Println(awscb.JoinCommands(commands))
+	output, err := aws.FormatCommands(awscb.JoinCommands(commands), awscb.TemplateParameters{})
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	fmt.Println(output)
 	if r.Reporter.IsTerminal() {
 		r.Reporter.Infof("Please run commands above to generate OIDC compliant configuration in your AWS account. " +
 			"After running the commands please refer to the documentation to register your unmanaged OIDC Configuration " +
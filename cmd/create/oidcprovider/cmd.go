@@ -17,13 +17,7 @@ limitations under the License.
 package oidcprovider
 
 import (
-	// nolint:gosec
-	"bytes"
-	"crypto/sha1" //#nosec GSC-G505 -- Import blacklist: crypto/sha1
-	"encoding/hex"
 	"fmt"
-	"net/http"
-	"net/url"
 	"os"
 	"strings"
 
@@ -33,6 +27,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
 	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -72,6 +67,8 @@ func init() {
 
 	ocm.AddOptionalClusterFlag(Cmd)
 	aws.AddModeFlag(Cmd)
+	aws.AddCloudFormationMode(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -102,7 +99,7 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	mode, err := aws.GetMode()
+	mode, err := aws.GetMode(aws.ModeCloudFormation)
 	if err != nil {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
@@ -222,13 +219,40 @@ func run(cmd *cobra.Command, argv []string) {
 				ocm.Response:  ocm.Failure,
 			})
 		}
+		commands, err = aws.FormatCommands(commands, awscb.TemplateParameters{})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 		if r.Reporter.IsTerminal() {
-			r.Reporter.Infof("Run the following commands to create the OIDC provider:\n")
+			r.Reporter.Infof("%s", aws.ManualInstructions("create the OIDC provider"))
 		}
 		r.OCMClient.LogEvent("ROSACreateOIDCProviderModeManual", map[string]string{
 			ocm.ClusterID: clusterKey,
 		})
 		fmt.Println(commands)
+	case aws.ModeCloudFormation:
+		commands, err := buildCommands(r, oidcEndpointURL, clusterId)
+		if err != nil {
+			r.Reporter.Errorf("There was an error building the list of resources: %s", err)
+			os.Exit(1)
+		}
+		stackName := aws.GetStackName("rosa-oidc-provider", strings.TrimPrefix(oidcEndpointURL, "https://"))
+		r.Reporter.Infof("Creating or updating CloudFormation stack '%s' using '%s'", stackName, r.Creator.ARN)
+		_, err = aws.EnsureStackFromCommands(r.AWSClient, stackName, commands, awscb.TemplateParameters{})
+		if err != nil {
+			r.Reporter.Errorf("There was an error creating the OIDC provider: %s", err)
+			r.OCMClient.LogEvent("ROSACreateOIDCProviderModeCloudFormation", map[string]string{
+				ocm.ClusterID: clusterKey,
+				ocm.Response:  ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		r.Reporter.Infof("CloudFormation stack '%s' is ready", stackName)
+		r.OCMClient.LogEvent("ROSACreateOIDCProviderModeCloudFormation", map[string]string{
+			ocm.ClusterID: clusterKey,
+			ocm.Response:  ocm.Success,
+		})
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
@@ -236,7 +260,7 @@ func run(cmd *cobra.Command, argv []string) {
 }
 
 func createProvider(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) error {
-	thumbprint, err := getThumbprint(oidcEndpointUrl)
+	thumbprint, err := oidc_config.GetThumbprint(oidcEndpointUrl)
 	if err != nil {
 		return err
 	}
@@ -256,7 +280,7 @@ func createProvider(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) e
 func buildCommands(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) (string, error) {
 	commands := []string{}
 
-	thumbprint, err := getThumbprint(oidcEndpointUrl)
+	thumbprint, err := oidc_config.GetThumbprint(oidcEndpointUrl)
 	if err != nil {
 		return "", err
 	}
@@ -282,39 +306,3 @@ func buildCommands(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) (s
 
 	return awscb.JoinCommands(commands), nil
 }
-
-func getThumbprint(oidcEndpointURL string) (string, error) {
-	connect, err := url.ParseRequestURI(oidcEndpointURL)
-	if err != nil {
-		return "", err
-	}
-
-	response, err := http.Get(fmt.Sprintf("https://%s:443", connect.Host))
-	if err != nil {
-		return "", err
-	}
-
-	certChain := response.TLS.PeerCertificates
-
-	// Grab the CA in the chain
-	for _, cert := range certChain {
-		if cert.IsCA {
-			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
-				return sha1Hash(cert.Raw), nil
-			}
-		}
-	}
-
-	// Fall back to using the last certficiate in the chain
-	cert := certChain[len(certChain)-1]
-	return sha1Hash(cert.Raw), nil
-}
-
-// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
-func sha1Hash(data []byte) string {
-	// nolint:gosec
-	hasher := sha1.New()
-	hasher.Write(data)
-	hashed := hasher.Sum(nil)
-	return hex.EncodeToString(hashed)
-}
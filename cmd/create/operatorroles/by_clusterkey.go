@@ -131,15 +131,48 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 				ocm.Response:  ocm.Failure,
 			})
 		}
+		commands, err = aws.FormatCommands(commands, awscb.TemplateParameters{
+			Prefix:              cluster.AWS().STS().OperatorRolePrefix(),
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the operator roles:\n")
+			r.Reporter.Infof("%s", aws.ManualInstructions("create the operator roles"))
 		}
 		r.OCMClient.LogEvent("ROSACreateOperatorRolesModeManual", map[string]string{
 			ocm.ClusterID: clusterKey,
 		})
 		fmt.Println(commands)
-
+	case aws.ModeCloudFormation:
+		commands, err := buildCommands(r, env, operatorRolePolicyPrefix, permissionsBoundary, defaultPolicyVersion,
+			cluster, policies, credRequests, managedPolicies, hostedCPPolicies)
+		if err != nil {
+			r.Reporter.Errorf("There was an error building the list of resources: %s", err)
+			os.Exit(1)
+		}
+		stackName := aws.GetStackName("rosa", cluster.ID(), "operator-roles")
+		err = ensureOperatorRolesStack(r, stackName, commands, awscb.TemplateParameters{
+			Prefix:              cluster.AWS().STS().OperatorRolePrefix(),
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("There was an error creating the operator roles: %s", err)
+			r.OCMClient.LogEvent("ROSACreateOperatorRolesModeCloudFormation", map[string]string{
+				ocm.ClusterID: clusterKey,
+				ocm.Response:  ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		r.OCMClient.LogEvent("ROSACreateOperatorRolesModeCloudFormation", map[string]string{
+			ocm.ClusterID: clusterKey,
+			ocm.Response:  ocm.Success,
+		})
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
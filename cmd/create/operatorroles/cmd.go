@@ -108,6 +108,8 @@ func init() {
 	)
 
 	aws.AddModeFlag(Cmd)
+	aws.AddCloudFormationMode(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
 }
@@ -135,7 +137,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
-	mode, err := aws.GetMode()
+	mode, err := aws.GetMode(aws.ModeCloudFormation)
 	if err != nil {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
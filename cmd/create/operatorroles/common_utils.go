@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/rosa"
 )
 
 func computePolicyARN(accountID string, prefix string, namespace string, name string, path string) string {
@@ -19,3 +21,20 @@ func computePolicyARN(accountID string, prefix string, namespace string, name st
 	}
 	return fmt.Sprintf("arn:%s:iam::%s:policy/%s", aws.GetPartition(), accountID, policy)
 }
+
+// ensureOperatorRolesStack creates or updates the CloudFormation stack that contains the resources
+// created by the given manual mode commands, and prints the commands that have to be run manually.
+func ensureOperatorRolesStack(r *rosa.Runtime, stackName string, commands string,
+	parameters awscb.TemplateParameters) error {
+	r.Reporter.Infof("Creating or updating CloudFormation stack '%s' using '%s'", stackName, r.Creator.ARN)
+	manual, err := aws.EnsureStackFromCommands(r.AWSClient, stackName, commands, parameters)
+	if err != nil {
+		return err
+	}
+	r.Reporter.Infof("CloudFormation stack '%s' is ready", stackName)
+	if len(manual) > 0 {
+		r.Reporter.Infof("Run the following commands to finish the creation of the operator roles:\n")
+		fmt.Println(awscb.JoinCommands(manual))
+	}
+	return nil
+}
@@ -79,6 +79,7 @@ func init() {
 	)
 
 	aws.AddModeFlag(Cmd)
+	aws.AddOutputFormatFlag(Cmd)
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
 }
@@ -234,10 +235,6 @@ func run(cmd *cobra.Command, argv []string) {
 			r.Reporter.Errorf("There was an error generating the policy files: %s", err)
 			os.Exit(1)
 		}
-		if r.Reporter.IsTerminal() {
-			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the account roles and policies:\n")
-		}
 		commands := buildCommands(
 			prefix,
 			path,
@@ -246,6 +243,19 @@ func run(cmd *cobra.Command, argv []string) {
 			env,
 			permissionsBoundary,
 		)
+		commands, err = aws.FormatCommands(commands, awscb.TemplateParameters{
+			Prefix:              prefix,
+			Path:                path,
+			PermissionsBoundary: permissionsBoundary,
+		})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("All policy files saved to the current directory")
+			r.Reporter.Infof("%s", aws.ManualInstructions("create the user role"))
+		}
 		fmt.Println(commands)
 
 	default:
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 	"github.com/spf13/cobra"
 )
@@ -44,6 +45,20 @@ var Cmd = &cobra.Command{
 	},
 }
 
+func init() {
+	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.AddOn{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(addOn *cmv1.AddOn) string { return addOn.ID() }},
+			{Header: "NAME", Value: func(addOn *cmv1.AddOn) string { return addOn.Name() }},
+			{Header: "OPERATOR", Value: func(addOn *cmv1.AddOn) string { return addOn.OperatorName() }},
+			{Header: "TARGET NAMESPACE", Value: func(addOn *cmv1.AddOn) string { return addOn.TargetNamespace() }},
+			{Header: "INSTALL MODE", Value: func(addOn *cmv1.AddOn) string { return string(addOn.InstallMode()) }},
+		},
+	})
+}
+
 func run(_ *cobra.Command, argv []string) {
 	r := rosa.NewRuntime().WithOCM()
 	defer r.Cleanup()
@@ -59,6 +74,15 @@ func run(_ *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		err = output.Print(addOn)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	printDescription(addOn)
 	printCredentialRequests(addOn.CredentialsRequests())
 	printParameters(addOn.Parameters())
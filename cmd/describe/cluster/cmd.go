@@ -25,8 +25,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/helper/logs"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/properties"
@@ -38,20 +41,41 @@ const (
 	ProductionURL = "https://console.redhat.com/openshift/details/s/"
 	StageEnv      = "https://api.stage.openshift.com"
 	ProductionEnv = "https://api.openshift.com"
+
+	// SpecFormat is the output format that exports the cluster as a re-creatable spec document.
+	SpecFormat = "spec"
 )
 
+var args struct {
+	installProgress bool
+}
+
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Show details of a cluster",
 	Long:  "Show details of a cluster",
 	Example: `  # Describe a cluster named "mycluster"
-  rosa describe cluster --cluster=mycluster`,
+  rosa describe cluster --cluster=mycluster
+
+  # Export a cluster as a spec file that can be used with 'rosa create cluster --from-file'
+  rosa describe cluster --cluster=mycluster -o spec > mycluster.yaml
+
+  # Describe a cluster and the progress of its installation
+  rosa describe cluster --cluster=mycluster --install-progress`,
 	Run: run,
 }
 
 func init() {
-	output.AddFlag(Cmd)
+	output.AddFlag(Cmd, SpecFormat)
 	ocm.AddClusterFlag(Cmd)
+
+	Cmd.Flags().BoolVar(
+		&args.installProgress,
+		"install-progress",
+		false,
+		"Show the phases of the installation and, if it failed, the known causes found in the "+
+			"install logs and the commands that verify them.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -69,6 +93,15 @@ func run(cmd *cobra.Command, argv []string) {
 	cluster := r.FetchCluster()
 	isHypershift := cluster.Hypershift().Enabled()
 
+	if output.Output() == SpecFormat {
+		err = printSpec(r, cluster)
+		if err != nil {
+			r.Reporter.Errorf("Failed to export cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var scheduledUpgrade *cmv1.UpgradePolicy
 	var upgradeState *cmv1.UpgradePolicyState
 	var controlPlaneScheduledUpgrade *cmv1.ControlPlaneUpgradePolicy
@@ -379,6 +412,57 @@ func run(cmd *cobra.Command, argv []string) {
 
 	// Print short cluster description:
 	fmt.Print(str)
+
+	if args.installProgress {
+		printInstallProgress(r, cluster)
+	}
+}
+
+func printInstallProgress(r *rosa.Runtime, cluster *cmv1.Cluster) {
+	log, err := r.OCMClient.GetInstallLogsFrom(cluster.ID(), 0)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			r.Reporter.Infof("The install logs of cluster '%s' aren't available yet", r.ClusterKey)
+			return
+		}
+		r.Reporter.Errorf("Failed to get install logs for cluster '%s': %v", r.ClusterKey, err)
+		os.Exit(1)
+	}
+	progress := logs.NewProgress()
+	progress.Add(strings.Split(log.Content(), "\n"))
+	logs.ReportProgress(r, cluster, progress)
+}
+
+func printSpec(r *rosa.Runtime, cluster *cmv1.Cluster) error {
+	r.Reporter.Debugf("Loading resources of cluster '%s'", r.ClusterKey)
+	resources, err := clusterspec.FetchResources(r.OCMClient, cluster)
+	if err != nil {
+		return err
+	}
+	spec := clusterspec.Export(cluster, resources)
+	if cluster.Proxy().HTTPProxy() != "" || cluster.Proxy().HTTPSProxy() != "" {
+		r.Reporter.Warnf("The additional trust bundle can't be exported, " +
+			"set 'proxy.additionalTrustBundleFile' if the cluster needs one")
+	}
+	oidcConfig := cluster.AWS().STS().OidcConfig()
+	if cluster.Hypershift().Enabled() && oidcConfig.ID() != "" {
+		r.Reporter.Warnf("The OIDC configuration '%s' is exported because hosted control plane clusters "+
+			"require one, create the operator roles of the new cluster for it with "+
+			"'rosa create operator-roles --hosted-cp --oidc-config-id %s'", oidcConfig.ID(), oidcConfig.ID())
+	} else if oidcConfig.Reusable() {
+		r.Reporter.Warnf("The OIDC configuration isn't exported, so that the new cluster doesn't share " +
+			"the operator roles of this one, set 'sts.oidcConfigID' if the cluster needs one")
+	}
+	if len(spec.IdentityProviders) > 0 {
+		r.Reporter.Warnf("Identity provider secrets can't be exported, " +
+			"set them in the spec file or reference environment variables using '${NAME}'")
+	}
+	body, err := clusterspec.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(body))
+	return nil
 }
 
 func controlPlaneConfig(cluster *cmv1.Cluster) string {
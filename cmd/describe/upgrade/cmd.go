@@ -19,10 +19,13 @@ package upgrade
 import (
 	"fmt"
 	"os"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -39,6 +42,39 @@ var Cmd = &cobra.Command{
 
 func init() {
 	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.UpgradePolicy{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(upgrade *cmv1.UpgradePolicy) string { return upgrade.ID() }},
+			{Header: "VERSION", Value: func(upgrade *cmv1.UpgradePolicy) string { return upgrade.Version() }},
+			{Header: "SCHEDULE TYPE", Value: func(upgrade *cmv1.UpgradePolicy) string { return upgrade.ScheduleType() }},
+			{Header: "SCHEDULE", Value: func(upgrade *cmv1.UpgradePolicy) string { return upgrade.Schedule() }},
+			{Header: "NEXT RUN", Value: func(upgrade *cmv1.UpgradePolicy) string {
+				return upgrade.NextRun().Format(time.RFC3339)
+			}},
+		},
+	})
+	output.Register(&cmv1.ControlPlaneUpgradePolicy{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string { return upgrade.ID() }},
+			{Header: "VERSION", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string {
+				return upgrade.Version()
+			}},
+			{Header: "STATE", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string {
+				return string(upgrade.State().Value())
+			}},
+			{Header: "SCHEDULE TYPE", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string {
+				return string(upgrade.ScheduleType())
+			}},
+			{Header: "SCHEDULE", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string {
+				return upgrade.Schedule()
+			}},
+			{Header: "NEXT RUN", Value: func(upgrade *cmv1.ControlPlaneUpgradePolicy) string {
+				return upgrade.NextRun().Format(time.RFC3339)
+			}},
+		},
+	})
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -61,6 +97,14 @@ func returnHypershiftUpgrades(r *rosa.Runtime, clusterID string) {
 		r.Reporter.Errorf("Failed to get upgrade with cluster id '%s': %v", clusterID, err)
 		os.Exit(1)
 	}
+	if output.HasFlag() {
+		err = output.Print(upgrades)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if len(upgrades) < 1 {
 		r.Reporter.Warnf("No scheduled upgrades for cluster id '%s'", clusterID)
 		os.Exit(1)
@@ -98,6 +142,14 @@ func returnClassicUpgrades(r *rosa.Runtime, clusterID string) {
 		r.Reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterID, err)
 		os.Exit(1)
 	}
+	if output.HasFlag() {
+		err = output.Print(upgrades)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if len(upgrades) < 1 {
 		r.Reporter.Warnf("No scheduled upgrades for cluster id '%s'", clusterID)
 		os.Exit(1)
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/reporter"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	file         string
+	otherCluster string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Compare a cluster with a spec file or another cluster",
+	Long: "Compare the version, networking, proxy, encryption, STS roles, machine pools, identity " +
+		"providers, ingresses, tuning configs and add-ons of a cluster with a spec file or with another " +
+		"cluster. The command exits with status 0 when there are no differences, 1 when there are " +
+		"differences and 2 when the comparison fails.",
+	Example: `  # Compare a cluster with the spec file it was created from
+  rosa diff cluster --cluster=mycluster --file=mycluster.yaml
+
+  # Compare two clusters, printing the differences as a JSON patch
+  rosa diff cluster --cluster=mycluster --other-cluster=mycluster-staging -o json`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	ocm.AddClusterFlag(Cmd)
+	flags.StringVar(
+		&args.file,
+		"file",
+		"",
+		"Path of the YAML or JSON cluster spec file to compare the cluster with. Fields that are "+
+			"omitted from the file aren't compared.",
+	)
+	flags.StringVar(
+		&args.otherCluster,
+		"other-cluster",
+		"",
+		"Name or ID of the cluster to compare the cluster with.",
+	)
+	output.AddFlag(Cmd)
+
+	output.Register(clusterspec.PatchOperation{}, output.Type{
+		Columns: []output.Column{
+			{Header: "OP", Value: func(operation clusterspec.PatchOperation) string { return operation.Op }},
+			{Header: "PATH", Value: func(operation clusterspec.PatchOperation) string { return operation.Path }},
+			{Header: "VALUE", Value: patchValue},
+		},
+	})
+}
+
+// patchValue returns the JSON representation of the value of the operation, or an empty string for
+// the 'remove' operations, which don't have a value.
+func patchValue(operation clusterspec.PatchOperation) string {
+	if operation.Op == "remove" {
+		return ""
+	}
+	data, err := json.Marshal(operation.Value)
+	if err != nil {
+		return fmt.Sprintf("%v", operation.Value)
+	}
+	return string(data)
+}
+
+// Like diff(1), the command exits with 1 when there are differences and with 2 when it fails, so
+// that scripts can tell them apart.
+const (
+	exitDifferent = 1
+	exitError     = 2
+)
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime()
+
+	if (args.file == "") == (args.otherCluster == "") {
+		fail(r, "Exactly one of '--file' or '--other-cluster' must be specified")
+	}
+
+	var spec *clusterspec.Cluster
+	var err error
+	if args.file != "" {
+		spec, err = clusterspec.Load(args.file)
+		if err != nil {
+			fail(r, "%v", err)
+		}
+	} else if !ocm.IsValidClusterKey(args.otherCluster) {
+		fail(r, "Cluster name, identifier or external identifier '%s' isn't valid: it "+
+			"must contain only letters, digits, dashes and underscores", args.otherCluster)
+	}
+
+	// The clients are created here instead of with the runtime, which exits with 1 on errors:
+	r.AWSClient, err = aws.NewClient().Logger(r.Logger).Build()
+	if err != nil {
+		fail(r, "Failed to create AWS client: %v", err)
+	}
+	r.Creator, err = r.AWSClient.GetCreator()
+	if err != nil {
+		fail(r, "Failed to get AWS creator: %v", err)
+	}
+	r.OCMClient, err = ocm.NewClient().Logger(r.Logger).Build()
+	if err != nil {
+		fail(r, "Failed to create OCM connection: %v", err)
+	}
+	defer r.Cleanup()
+
+	clusterKey, err := ocm.GetClusterKey()
+	if err != nil {
+		fail(r, "%s", err)
+	}
+	r.ClusterKey = clusterKey
+	reporter.SetClusterKey(clusterKey)
+	current, err := fetchAndExport(r, clusterKey)
+	if err != nil {
+		fail(r, "%v", err)
+	}
+
+	target := args.file
+	if spec == nil {
+		target = args.otherCluster
+		spec, err = fetchAndExport(r, args.otherCluster)
+		if err != nil {
+			fail(r, "%v", err)
+		}
+	}
+
+	patch, err := clusterspec.Diff(current, spec, clusterspec.DiffOptions{
+		IgnoreMissing: args.file != "",
+	})
+	if err != nil {
+		fail(r, "Failed to compare cluster '%s' with '%s': %v", clusterKey, target, err)
+	}
+
+	if output.HasFlag() {
+		if patch == nil {
+			patch = []clusterspec.PatchOperation{}
+		}
+		err = output.Print(patch)
+		if err != nil {
+			fail(r, "%s", err)
+		}
+	} else if len(patch) == 0 {
+		r.Reporter.Infof("Cluster '%s' doesn't differ from '%s'", clusterKey, target)
+	} else {
+		fmt.Printf("Differences between cluster '%s' and '%s':\n", clusterKey, target)
+		for _, operation := range patch {
+			fmt.Printf("  %s\n", operation)
+		}
+	}
+
+	if len(patch) > 0 {
+		os.Exit(exitDifferent)
+	}
+}
+
+func fail(r *rosa.Runtime, format string, args ...interface{}) {
+	r.Reporter.Errorf(format, args...)
+	os.Exit(exitError)
+}
+
+func fetchAndExport(r *rosa.Runtime, clusterKey string) (*clusterspec.Cluster, error) {
+	cluster, err := r.OCMClient.GetCluster(clusterKey, r.Creator)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+	spec, err := exportCluster(r, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to export cluster '%s': %v", clusterKey, err)
+	}
+	return spec, nil
+}
+
+func exportCluster(r *rosa.Runtime, cluster *cmv1.Cluster) (*clusterspec.Cluster, error) {
+	resources, err := clusterspec.FetchResources(r.OCMClient, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return clusterspec.Export(cluster, resources), nil
+}
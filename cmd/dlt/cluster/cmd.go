@@ -30,6 +30,7 @@ import (
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -75,7 +76,7 @@ func run(cmd *cobra.Command, _ []string) {
 	r.Reporter.Debugf("Deleting cluster '%s'", clusterKey)
 	cluster, err := r.OCMClient.DeleteCluster(clusterKey, r.Creator)
 	if err != nil {
-		r.Reporter.Errorf("%s", err)
+		r.Reporter.WithCode(reporter.CodeDeleteCluster).Errorf("%s", err)
 		os.Exit(1)
 	}
 	r.Reporter.Infof("Cluster '%s' will start uninstalling now", clusterKey)
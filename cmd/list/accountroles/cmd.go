@@ -17,14 +17,13 @@ limitations under the License.
 package accountroles
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
@@ -56,6 +55,19 @@ func init() {
 	output.AddFlag(Cmd)
 }
 
+var columns = []output.Column{
+	{Header: "ROLE NAME", Value: func(role aws.Role) string { return role.RoleName }},
+	{Header: "ROLE TYPE", Value: func(role aws.Role) string { return role.RoleType }},
+	{Header: "ROLE ARN", Value: func(role aws.Role) string { return role.RoleARN }},
+	{Header: "OPENSHIFT VERSION", Value: func(role aws.Role) string { return role.Version }},
+	{Header: "AWS Managed", Value: func(role aws.Role) string {
+		if role.ManagedPolicy {
+			return "Yes"
+		}
+		return "No"
+	}},
+}
+
 func run(_ *cobra.Command, _ []string) {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
@@ -93,36 +105,14 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(accountRoles) == 0 {
+	if len(accountRoles) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No account roles available")
 		os.Exit(0)
 	}
-	if output.HasFlag() {
-		err = output.Print(accountRoles)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ROLE NAME\tROLE TYPE\tROLE ARN\tOPENSHIFT VERSION\tAWS Managed\n")
-	for _, accountRole := range accountRoles {
-		awsManaged := "No"
-		if accountRole.ManagedPolicy {
-			awsManaged = "Yes"
-		}
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\t%s\t%s\n",
-			accountRole.RoleName,
-			accountRole.RoleType,
-			accountRole.RoleARN,
-			accountRole.Version,
-			awsManaged,
-		)
+	err = output.Print(accountRoles, columns...)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
@@ -17,14 +17,13 @@ limitations under the License.
 package addon
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -32,6 +31,13 @@ var args struct {
 	clusterKey string
 }
 
+// availableAddOn is the printed representation of an add-on that can be installed.
+type availableAddOn struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Availability string `json:"availability"`
+}
+
 var Cmd = &cobra.Command{
 	Use:     "addons",
 	Aliases: []string{"addon", "add-ons", "add-on"},
@@ -52,6 +58,22 @@ func init() {
 		"",
 		"Name or ID of the cluster to list the add-ons of (required).",
 	)
+	output.AddFlag(Cmd)
+
+	output.Register(availableAddOn{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(a availableAddOn) string { return a.ID }},
+			{Header: "NAME", Value: func(a availableAddOn) string { return a.Name }},
+			{Header: "AVAILABILITY", Value: func(a availableAddOn) string { return a.Availability }},
+		},
+	})
+	output.Register(&ocm.ClusterAddOn{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(a *ocm.ClusterAddOn) string { return a.ID }},
+			{Header: "NAME", Value: func(a *ocm.ClusterAddOn) string { return a.Name }},
+			{Header: "STATE", Value: func(a *ocm.ClusterAddOn) string { return a.State }},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -78,23 +100,28 @@ func run(_ *cobra.Command, _ []string) {
 			r.Reporter.Errorf("Failed to fetch add-ons: %v", err)
 			os.Exit(1)
 		}
-		if len(addOnResources) == 0 {
+		if len(addOnResources) == 0 && !output.HasFlag() {
 			r.Reporter.Infof("There are no add-ons available")
 			os.Exit(0)
 		}
 
-		// Create the writer that will be used to print the tabulated results:
-		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintf(writer, "ID\t\tNAME\t\tAVAILABILITY\n")
-		for _, addOnResource := range addOnResources {
+		availableAddOns := make([]availableAddOn, len(addOnResources))
+		for i, addOnResource := range addOnResources {
 			availability := "unavailable"
 			if addOnResource.Available {
 				availability = "available"
 			}
-			fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", addOnResource.AddOn.ID(), addOnResource.AddOn.Name(), availability)
+			availableAddOns[i] = availableAddOn{
+				ID:           addOnResource.AddOn.ID(),
+				Name:         addOnResource.AddOn.Name(),
+				Availability: availability,
+			}
+		}
+		err = output.Print(availableAddOns)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
-		writer.Flush()
-
 		os.Exit(0)
 	}
 
@@ -112,16 +139,14 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(clusterAddOns) == 0 {
+	if len(clusterAddOns) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no add-ons installed on cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\t\tNAME\t\tSTATE\n")
-	for _, clusterAddOn := range clusterAddOns {
-		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", clusterAddOn.ID, clusterAddOn.Name, clusterAddOn.State)
+	err = output.Print(clusterAddOns)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+)
+
+// accountSummary is a row of the summary of AWS accounts printed by '--all-accounts'.
+type accountSummary struct {
+	ID       string
+	Regions  []string
+	Clusters int
+	OCMRole  bool
+	UserRole bool
+}
+
+func init() {
+	output.Register(accountSummary{}, output.Type{
+		Columns: []output.Column{
+			{Header: "AWS ACCOUNT", Value: func(a accountSummary) string { return a.ID }},
+			{Header: "CLUSTERS", Value: func(a accountSummary) string { return fmt.Sprintf("%d", a.Clusters) }},
+			{Header: "REGIONS", Value: func(a accountSummary) string { return strings.Join(a.Regions, ", ") }},
+			{Header: "OCM ROLE", Value: func(a accountSummary) string { return yesNo(a.OCMRole) }},
+			{Header: "USER ROLE", Value: func(a accountSummary) string { return yesNo(a.UserRole) }},
+		},
+	})
+}
+
+// summarizeAccounts groups the clusters by AWS account, and adds the linked accounts that don't
+// have clusters.
+func summarizeAccounts(clusters []*cmv1.Cluster,
+	linkedAccounts map[string]*ocm.LinkedAWSAccount) []accountSummary {
+	summaries := map[string]*accountSummary{}
+	summary := func(accountID string) *accountSummary {
+		if _, ok := summaries[accountID]; !ok {
+			summaries[accountID] = &accountSummary{ID: accountID}
+			if linked, ok := linkedAccounts[accountID]; ok {
+				summaries[accountID].OCMRole = len(linked.OCMRoles) > 0
+				summaries[accountID].UserRole = len(linked.UserRoles) > 0
+			}
+		}
+		return summaries[accountID]
+	}
+	for _, cluster := range clusters {
+		accountID := ocm.GetClusterAWSAccountID(cluster)
+		if accountID == "" {
+			accountID = "unknown"
+		}
+		s := summary(accountID)
+		s.Clusters++
+		region := cluster.Region().ID()
+		if i := sort.SearchStrings(s.Regions, region); i == len(s.Regions) || s.Regions[i] != region {
+			s.Regions = append(s.Regions[:i], append([]string{region}, s.Regions[i:]...)...)
+		}
+	}
+	for accountID := range linkedAccounts {
+		summary(accountID)
+	}
+
+	result := make([]accountSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
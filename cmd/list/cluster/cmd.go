@@ -19,76 +19,288 @@ package cluster
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
+var args struct {
+	states      []string
+	versions    []string
+	regions     []string
+	topologies  []string
+	nameRegex   string
+	search      string
+	sortBy      string
+	columns     []string
+	allAccounts bool
+}
+
 var Cmd = &cobra.Command{
 	Use:     "clusters",
 	Aliases: []string{"cluster"},
 	Short:   "List clusters",
 	Long:    "List clusters.",
 	Example: `  # List all clusters
-  rosa list clusters`,
+  rosa list clusters
+
+  # List the hosted control plane clusters that are ready, with their version and region
+  rosa list clusters --topology=hosted-cp --state=ready --columns=name,version,region
+
+  # List the clusters in region us-east-2
+  rosa list clusters --filter-region=us-east-2
+
+  # List the clusters in version 4.12, newest first
+  rosa list clusters --version=4.12 --sort-by=-created
+
+  # List the clusters of all the AWS accounts of the organization
+  rosa list clusters --all-accounts`,
 	Args: cobra.NoArgs,
 	Run:  run,
 }
 
+// column is a field of a cluster that can be displayed with the '--columns' flag. The selected
+// columns are used by the table and CSV output formats.
+type column struct {
+	header string
+	value  func(cluster *cmv1.Cluster) string
+}
+
+var columns = map[string]column{
+	"id": {"ID", func(cluster *cmv1.Cluster) string {
+		return cluster.ID()
+	}},
+	"name": {"NAME", func(cluster *cmv1.Cluster) string {
+		return cluster.Name()
+	}},
+	"state": {"STATE", func(cluster *cmv1.Cluster) string {
+		return string(cluster.State())
+	}},
+	"topology": {"TOPOLOGY", topology},
+	"version": {"VERSION", func(cluster *cmv1.Cluster) string {
+		return cluster.Version().RawID()
+	}},
+	"region": {"REGION", func(cluster *cmv1.Cluster) string {
+		return cluster.Region().ID()
+	}},
+	"created": {"CREATED", func(cluster *cmv1.Cluster) string {
+		return cluster.CreationTimestamp().UTC().Format(time.RFC3339)
+	}},
+	"multi-az": {"MULTI AZ", func(cluster *cmv1.Cluster) string {
+		return fmt.Sprintf("%t", cluster.MultiAZ())
+	}},
+	"private": {"PRIVATE", func(cluster *cmv1.Cluster) string {
+		return fmt.Sprintf("%t", cluster.API().Listening() == cmv1.ListeningMethodInternal)
+	}},
+	"account": {"AWS ACCOUNT", ocm.GetClusterAWSAccountID},
+}
+
+var columnNames = []string{
+	"id", "name", "state", "topology", "version", "region", "created", "multi-az", "private", "account",
+}
+
+var defaultColumns = []string{"id", "name", "state", "topology"}
+
+// allAccountsColumns are the default columns when listing the clusters of all AWS accounts.
+var allAccountsColumns = []string{"account", "region", "id", "name", "state", "topology"}
+
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
+	flags.StringSliceVar(
+		&args.states,
+		"state",
+		nil,
+		"Only list clusters in the given states, for example 'ready' or 'installing'.",
+	)
+	flags.StringSliceVar(
+		&args.versions,
+		"version",
+		nil,
+		"Only list clusters in the given OpenShift versions. A version such as '4.12' also matches "+
+			"all its patch versions.",
+	)
+	flags.StringSliceVar(
+		&args.regions,
+		"filter-region",
+		nil,
+		"Only list clusters in the given AWS regions. The '--region' flag only selects the region "+
+			"used to connect to AWS, and doesn't filter the clusters.",
+	)
+	flags.StringSliceVar(
+		&args.topologies,
+		"topology",
+		nil,
+		fmt.Sprintf("Only list clusters with the given topologies. Allowed values are %s", ocm.Topologies),
+	)
+	flags.StringVar(
+		&args.nameRegex,
+		"name-regex",
+		"",
+		"Only list clusters whose name matches the given regular expression.",
+	)
+	flags.StringVar(
+		&args.search,
+		"search",
+		"",
+		"Additional OCM search query used to select clusters, for example \"multi_az = 'true'\".",
+	)
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		fmt.Sprintf("Column used to sort the clusters. Prefix it with '-' to sort in descending order. "+
+			"Allowed values are %s", columnNames),
+	)
+	flags.StringSliceVar(
+		&args.columns,
+		"columns",
+		defaultColumns,
+		fmt.Sprintf("Columns to display. Allowed values are %s", columnNames),
+	)
+	flags.BoolVar(
+		&args.allAccounts,
+		"all-accounts",
+		false,
+		"List the clusters of all the AWS accounts of the organization, grouped by AWS account and "+
+			"region, followed by a summary of the accounts and the roles linked to them.",
+	)
+
 	output.AddFlag(Cmd)
+
+	// Other commands that print clusters use the default columns:
+	output.Register(&cmv1.Cluster{}, output.Type{Columns: selectColumns(defaultColumns)})
 }
 
-func run(_ *cobra.Command, _ []string) {
-	r := rosa.NewRuntime().WithAWS().WithOCM()
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime()
+
+	if args.allAccounts && !cmd.Flags().Changed("columns") {
+		args.columns = allAccountsColumns
+	}
+	for _, name := range args.columns {
+		if _, ok := columns[name]; !ok {
+			r.Reporter.Errorf("Invalid column '%s', allowed values are %s", name, columnNames)
+			os.Exit(1)
+		}
+	}
+	sortBy := strings.TrimPrefix(args.sortBy, "-")
+	if _, ok := columns[sortBy]; sortBy != "" && !ok {
+		r.Reporter.Errorf("Invalid sort column '%s', allowed values are %s", sortBy, columnNames)
+		os.Exit(1)
+	}
+	var nameRE *regexp.Regexp
+	if args.nameRegex != "" {
+		var err error
+		nameRE, err = regexp.Compile(args.nameRegex)
+		if err != nil {
+			r.Reporter.Errorf("Invalid name regular expression '%s': %v", args.nameRegex, err)
+			os.Exit(1)
+		}
+	}
+
+	// The AWS credentials are only needed to select the clusters of the current AWS account:
+	if !args.allAccounts {
+		r = r.WithAWS()
+	}
+	r = r.WithOCM()
 	defer r.Cleanup()
 
 	// Retrieve the list of clusters:
-	clusters, err := r.OCMClient.GetClusters(r.Creator, 1000)
+	clusters, err := r.OCMClient.GetFilteredClusters(r.Creator, ocm.ClusterFilter{
+		States:      args.states,
+		Regions:     args.regions,
+		Versions:    args.versions,
+		Topologies:  args.topologies,
+		Search:      args.search,
+		AllAccounts: args.allAccounts,
+	}, 0)
 	if err != nil {
 		r.Reporter.Errorf("Failed to get clusters: %v", err)
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(clusters)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
+	// Regular expressions aren't supported by the OCM search language, so names are matched here:
+	if nameRE != nil {
+		matching := []*cmv1.Cluster{}
+		for _, cluster := range clusters {
+			if nameRE.MatchString(cluster.Name()) {
+				matching = append(matching, cluster)
+			}
 		}
-		os.Exit(0)
+		clusters = matching
 	}
 
-	if len(clusters) == 0 {
+	if sortBy == "" && args.allAccounts {
+		sort.SliceStable(clusters, func(i, j int) bool {
+			accountI, accountJ := ocm.GetClusterAWSAccountID(clusters[i]), ocm.GetClusterAWSAccountID(clusters[j])
+			if accountI != accountJ {
+				return accountI < accountJ
+			}
+			return clusters[i].Region().ID() < clusters[j].Region().ID()
+		})
+	}
+	if sortBy != "" {
+		value := columns[sortBy].value
+		descending := strings.HasPrefix(args.sortBy, "-")
+		sort.SliceStable(clusters, func(i, j int) bool {
+			if descending {
+				return value(clusters[j]) < value(clusters[i])
+			}
+			return value(clusters[i]) < value(clusters[j])
+		})
+	}
+
+	if len(clusters) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No clusters available")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\tTOPOLOGY\n")
-	for _, cluster := range clusters {
-		typeOutput := "Classic"
-		if cluster.AWS() != nil && cluster.AWS().STS() != nil && cluster.AWS().STS().Enabled() {
-			typeOutput = "Classic (STS)"
+	err = output.Print(clusters, selectColumns(args.columns)...)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	if args.allAccounts && !output.HasFlag() {
+		linkedAccounts, err := r.OCMClient.GetLinkedAWSAccounts()
+		if err != nil {
+			r.Reporter.Errorf("Failed to get AWS accounts linked to the organization: %v", err)
+			os.Exit(1)
 		}
-		if cluster.Hypershift().Enabled() {
-			typeOutput = "Hosted CP"
+		fmt.Println()
+		err = output.Print(summarizeAccounts(clusters, linkedAccounts))
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\t%s\n",
-			cluster.ID(),
-			cluster.Name(),
-			cluster.State(),
-			typeOutput,
-		)
 	}
-	writer.Flush()
+}
+
+func selectColumns(names []string) []output.Column {
+	selected := make([]output.Column, len(names))
+	for i, name := range names {
+		selected[i] = output.Column{Header: columns[name].header, Value: columns[name].value}
+	}
+	return selected
+}
+
+func topology(cluster *cmv1.Cluster) string {
+	typeOutput := "Classic"
+	if cluster.AWS() != nil && cluster.AWS().STS() != nil && cluster.AWS().STS().Enabled() {
+		typeOutput = "Classic (STS)"
+	}
+	if cluster.Hypershift().Enabled() {
+		typeOutput = "Hosted CP"
+	}
+	return typeOutput
 }
@@ -85,6 +85,17 @@ func init() {
 	Cmd.MarkFlagRequired("version")
 
 	output.AddFlag(Cmd)
+
+	output.Register(&v1.VersionGate{}, output.Type{
+		Columns: []output.Column{
+			{Header: "Gate Description", Value: func(gate *v1.VersionGate) string {
+				return strings.TrimSuffix(gate.Description(), "\n")
+			}},
+			{Header: "STS", Value: func(gate *v1.VersionGate) string { return fmt.Sprintf("%t", gate.STSOnly()) }},
+			{Header: "OCP Version", Value: func(gate *v1.VersionGate) string { return gate.VersionRawIDPrefix() }},
+			{Header: "Documentation URL", Value: func(gate *v1.VersionGate) string { return gate.DocumentationURL() }},
+		},
+	})
 }
 
 const (
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -43,6 +42,18 @@ var Cmd = &cobra.Command{
 func init() {
 	ocm.AddClusterFlag(Cmd)
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.Ingress{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(ingress *cmv1.Ingress) string { return ingress.ID() }},
+			{Header: "APPLICATION ROUTER", Value: func(ingress *cmv1.Ingress) string {
+				return fmt.Sprintf("https://%s", ingress.DNSName())
+			}},
+			{Header: "PRIVATE", Value: func(ingress *cmv1.Ingress) string { return isPrivate(ingress.Listening()) }},
+			{Header: "DEFAULT", Value: isDefault},
+			{Header: "ROUTE SELECTORS", Value: printRouteSelectors},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -65,34 +76,16 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(ingresses)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(ingresses) == 0 {
+	if len(ingresses) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no ingresses configured for cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tAPPLICATION ROUTER\t\t\tPRIVATE\t\tDEFAULT\t\tROUTE SELECTORS\n")
-	for _, ingress := range ingresses {
-		fmt.Fprintf(writer, "%s\thttps://%s\t\t\t%s\t\t%s\t\t%s\n",
-			ingress.ID(),
-			ingress.DNSName(),
-			isPrivate(ingress.Listening()),
-			isDefault(ingress),
-			printRouteSelectors(ingress),
-		)
+	err = output.Print(ingresses)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func isPrivate(listeningMethod cmv1.ListeningMethod) string {
@@ -19,7 +19,6 @@ package instancetypes
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -40,6 +39,21 @@ var Cmd = &cobra.Command{
 
 func init() {
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.MachineType{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(machineType *cmv1.MachineType) string { return machineType.ID() }},
+			{Header: "CATEGORY", Value: func(machineType *cmv1.MachineType) string {
+				return string(machineType.Category())
+			}},
+			{Header: "CPU_CORES", Value: func(machineType *cmv1.MachineType) string {
+				return fmt.Sprintf("%d", int(machineType.CPU().Value()))
+			}},
+			{Header: "MEMORY", Value: func(machineType *cmv1.MachineType) string {
+				return ByteCountIEC(int(machineType.Memory().Value()), machineType.Memory().Unit())
+			}},
+		},
+	})
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -59,36 +73,18 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		var instanceTypes []*cmv1.MachineType
-		for _, machine := range machineTypes {
+	// Unavailable instance types are only included when an output format is explicitly requested:
+	var instanceTypes []*cmv1.MachineType
+	for _, machine := range machineTypes {
+		if machine.Available || output.HasFlag() {
 			instanceTypes = append(instanceTypes, machine.MachineType)
 		}
-		err = output.Print(instanceTypes)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
 	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\t\n")
-
-	for _, machine := range machineTypes {
-		if !machine.Available {
-			continue
-		}
-		availableMachine := machine.MachineType
-		fmt.Fprintf(writer,
-			"%s\t%s\t%d\t%s\n",
-			availableMachine.ID(), availableMachine.Category(), int(availableMachine.CPU().Value()),
-			ByteCountIEC(int(availableMachine.Memory().Value()),
-				availableMachine.Memory().Unit()),
-		)
+	err = output.Print(instanceTypes)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func ByteCountIEC(b int, uValue string) string {
@@ -40,6 +40,9 @@ var Cmd = &cobra.Command{
 func init() {
 	ocm.AddClusterFlag(Cmd)
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.MachinePool{}, output.Type{Columns: machinePoolColumns})
+	output.Register(&cmv1.NodePool{}, output.Type{Columns: nodePoolColumns})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -3,7 +3,6 @@ package machinepool
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/output"
@@ -43,34 +42,32 @@ func listMachinePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster)
 
 	machinePools = append([]*cmv1.MachinePool{defaultMachinePool}, machinePools...)
 
-	if output.HasFlag() {
-		err = output.Print(machinePools)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
+	err = output.Print(machinePools)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
+}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintf(writer,
-		"ID\tAUTOSCALING\tREPLICAS\tINSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONES\t\tSUBNETS\t\tSPOT INSTANCES\n")
-	for _, machinePool := range machinePools {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\t\t%s\t\t%s\n",
-			machinePool.ID(),
-			printMachinePoolAutoscaling(machinePool.Autoscaling()),
-			printMachinePoolReplicas(machinePool.Autoscaling(), machinePool.Replicas()),
-			machinePool.InstanceType(),
-			printLabels(machinePool.Labels()),
-			printTaints(machinePool.Taints()),
-			printStringSlice(machinePool.AvailabilityZones()),
-			printStringSlice(machinePool.Subnets()),
-			printSpot(machinePool),
-		)
-	}
-	writer.Flush()
+// machinePoolColumns are the columns of the table representation of machine pools.
+var machinePoolColumns = []output.Column{
+	{Header: "ID", Value: func(machinePool *cmv1.MachinePool) string { return machinePool.ID() }},
+	{Header: "AUTOSCALING", Value: func(machinePool *cmv1.MachinePool) string {
+		return printMachinePoolAutoscaling(machinePool.Autoscaling())
+	}},
+	{Header: "REPLICAS", Value: func(machinePool *cmv1.MachinePool) string {
+		return printMachinePoolReplicas(machinePool.Autoscaling(), machinePool.Replicas())
+	}},
+	{Header: "INSTANCE TYPE", Value: func(machinePool *cmv1.MachinePool) string { return machinePool.InstanceType() }},
+	{Header: "LABELS", Value: func(machinePool *cmv1.MachinePool) string { return printLabels(machinePool.Labels()) }},
+	{Header: "TAINTS", Value: func(machinePool *cmv1.MachinePool) string { return printTaints(machinePool.Taints()) }},
+	{Header: "AVAILABILITY ZONES", Value: func(machinePool *cmv1.MachinePool) string {
+		return printStringSlice(machinePool.AvailabilityZones())
+	}},
+	{Header: "SUBNETS", Value: func(machinePool *cmv1.MachinePool) string {
+		return printStringSlice(machinePool.Subnets())
+	}},
+	{Header: "SPOT INSTANCES", Value: printSpot},
 }
 
 func printMachinePoolAutoscaling(autoscaling *cmv1.MachinePoolAutoscaling) string {
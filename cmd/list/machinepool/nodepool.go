@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -20,29 +20,41 @@ func listNodePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster) {
 		os.Exit(1)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tAUTOSCALING\tDESIRED REPLICAS\tCURRENT REPLICAS\t"+
-		"INSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONE\tSUBNET\tVERSION\tAUTOREPAIR\tTUNING CONFIGS\tMESSAGE\t\n")
-	for _, nodePool := range nodePools {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
-			nodePool.ID(),
-			printNodePoolAutoscaling(nodePool.Autoscaling()),
-			printNodePoolReplicas(nodePool.Autoscaling(), nodePool.Replicas()),
-			printNodePoolCurrentReplicas(nodePool.Status()),
-			printNodePoolInstanceType(nodePool.AWSNodePool()),
-			printLabels(nodePool.Labels()),
-			printTaints(nodePool.Taints()),
-			nodePool.AvailabilityZone(),
-			nodePool.Subnet(),
-			printNodePoolVersion(nodePool.Version()),
-			printNodePoolAutorepair(nodePool.AutoRepair()),
-			printTuningConfigs(nodePool.TuningConfigs()),
-			printNodePoolMessage(nodePool.Status()),
-		)
+	err = output.Print(nodePools)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
+}
+
+// nodePoolColumns are the columns of the table representation of the machine pools of hosted
+// control plane clusters.
+var nodePoolColumns = []output.Column{
+	{Header: "ID", Value: func(nodePool *cmv1.NodePool) string { return nodePool.ID() }},
+	{Header: "AUTOSCALING", Value: func(nodePool *cmv1.NodePool) string {
+		return printNodePoolAutoscaling(nodePool.Autoscaling())
+	}},
+	{Header: "DESIRED REPLICAS", Value: func(nodePool *cmv1.NodePool) string {
+		return printNodePoolReplicas(nodePool.Autoscaling(), nodePool.Replicas())
+	}},
+	{Header: "CURRENT REPLICAS", Value: func(nodePool *cmv1.NodePool) string {
+		return printNodePoolCurrentReplicas(nodePool.Status())
+	}},
+	{Header: "INSTANCE TYPE", Value: func(nodePool *cmv1.NodePool) string {
+		return printNodePoolInstanceType(nodePool.AWSNodePool())
+	}},
+	{Header: "LABELS", Value: func(nodePool *cmv1.NodePool) string { return printLabels(nodePool.Labels()) }},
+	{Header: "TAINTS", Value: func(nodePool *cmv1.NodePool) string { return printTaints(nodePool.Taints()) }},
+	{Header: "AVAILABILITY ZONE", Value: func(nodePool *cmv1.NodePool) string { return nodePool.AvailabilityZone() }},
+	{Header: "SUBNET", Value: func(nodePool *cmv1.NodePool) string { return nodePool.Subnet() }},
+	{Header: "VERSION", Value: func(nodePool *cmv1.NodePool) string { return printNodePoolVersion(nodePool.Version()) }},
+	{Header: "AUTOREPAIR", Value: func(nodePool *cmv1.NodePool) string {
+		return printNodePoolAutorepair(nodePool.AutoRepair())
+	}},
+	{Header: "TUNING CONFIGS", Value: func(nodePool *cmv1.NodePool) string {
+		return printTuningConfigs(nodePool.TuningConfigs())
+	}},
+	{Header: "MESSAGE", Value: func(nodePool *cmv1.NodePool) string { return printNodePoolMessage(nodePool.Status()) }},
 }
 
 func printNodePoolAutoscaling(autoscaling *cmv1.NodePoolAutoscaling) string {
@@ -19,7 +19,6 @@ package ocmroles
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -45,6 +44,19 @@ func init() {
 	output.AddFlag(Cmd)
 }
 
+var columns = []output.Column{
+	{Header: "ROLE NAME", Value: func(role aws.Role) string { return role.RoleName }},
+	{Header: "ROLE ARN", Value: func(role aws.Role) string { return role.RoleARN }},
+	{Header: "LINKED", Value: func(role aws.Role) string { return role.Linked }},
+	{Header: "ADMIN", Value: func(role aws.Role) string { return role.Admin }},
+	{Header: "AWS Managed", Value: func(role aws.Role) string {
+		if role.ManagedPolicy {
+			return "Yes"
+		}
+		return "No"
+	}},
+}
+
 func run(_ *cobra.Command, _ []string) {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
@@ -69,33 +81,16 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(ocmRoles) == 0 {
+	if len(ocmRoles) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No ocm roles available")
 		os.Exit(0)
 	}
-	if output.HasFlag() {
-		err = output.Print(ocmRoles)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprint(writer, "ROLE NAME\tROLE ARN\tLINKED\tADMIN\tAWS Managed\n")
-	for _, ocmRole := range ocmRoles {
-		var awsManaged string
-		if ocmRole.ManagedPolicy {
-			awsManaged = "Yes"
-		} else {
-			awsManaged = "No"
-		}
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ocmRole.RoleName, ocmRole.RoleARN, ocmRole.Linked, ocmRole.Admin,
-			awsManaged)
+	err = output.Print(ocmRoles, columns...)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func listOCMRoles(r *rosa.Runtime) ([]aws.Role, error) {
@@ -19,8 +19,8 @@ package oidcconfig
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/output"
@@ -39,6 +39,17 @@ var Cmd = &cobra.Command{
 
 func init() {
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.OidcConfig{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(oidcConfig *cmv1.OidcConfig) string { return oidcConfig.ID() }},
+			{Header: "MANAGED", Value: func(oidcConfig *cmv1.OidcConfig) string {
+				return fmt.Sprintf("%v", oidcConfig.Managed())
+			}},
+			{Header: "ISSUER URL", Value: func(oidcConfig *cmv1.OidcConfig) string { return oidcConfig.IssuerUrl() }},
+			{Header: "SECRET ARN", Value: func(oidcConfig *cmv1.OidcConfig) string { return oidcConfig.SecretArn() }},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -53,31 +64,14 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(oidcConfigs)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(oidcConfigs) == 0 {
+	if len(oidcConfigs) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no OIDC Configurations for your organization")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tMANAGED\tISSUER URL\tSECRET ARN\n")
-	for _, oidcConfig := range oidcConfigs {
-		fmt.Fprintf(writer, "%s\t%v\t%s\t%s\n",
-			oidcConfig.ID(),
-			oidcConfig.Managed(),
-			oidcConfig.IssuerUrl(),
-			oidcConfig.SecretArn(),
-		)
+	err = output.Print(oidcConfigs)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -71,6 +72,18 @@ func init() {
 	output.AddFlag(Cmd)
 }
 
+var columns = []output.Column{
+	{Header: "ROLE NAME", Value: func(role aws.Role) string { return role.RoleName }},
+	{Header: "ROLE ARN", Value: func(role aws.Role) string { return role.RoleARN }},
+	{Header: "VERSION", Value: func(role aws.Role) string { return role.Version }},
+	{Header: "MANAGED", Value: func(role aws.Role) string {
+		if role.ManagedPolicy {
+			return "Yes"
+		}
+		return "No"
+	}},
+}
+
 func run(cmd *cobra.Command, _ []string) {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
@@ -125,7 +138,7 @@ func run(cmd *cobra.Command, _ []string) {
 			}
 			resource = operatorsMap[args.prefix]
 		}
-		err = output.Print(resource)
+		err = output.Print(resource, columns...)
 		if err != nil {
 			r.Reporter.Errorf("%s", err)
 			os.Exit(1)
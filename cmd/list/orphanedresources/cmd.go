@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/orphans"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "orphaned-resources",
+	Aliases: []string{"orphanedresources", "orphaned-resource", "orphans"},
+	Short:   "List AWS resources left behind by deleted clusters",
+	Long: "List the operator roles, operator policies and OIDC providers of the current AWS account " +
+		"that were created by ROSA and no longer belong to any cluster or OIDC configuration.",
+	Example: `  # List the resources left behind by deleted clusters
+  rosa list orphaned-resources
+
+  # List them in JSON format
+  rosa list orphaned-resources -o json`,
+	Run: run,
+}
+
+func init() {
+	output.AddFlag(Cmd)
+
+	output.Register(&orphans.Resource{}, output.Type{
+		Columns: []output.Column{
+			{Header: "TYPE", Value: func(resource *orphans.Resource) string { return string(resource.Kind) }},
+			{Header: "NAME", Value: func(resource *orphans.Resource) string { return resource.Name }},
+			{Header: "CLUSTER ID", Value: func(resource *orphans.Resource) string { return resource.ClusterID }},
+			{Header: "PREFIX", Value: func(resource *orphans.Resource) string { return resource.Prefix }},
+			{Header: "CREATED", Value: func(resource *orphans.Resource) string {
+				if resource.CreatedAt.IsZero() {
+					return ""
+				}
+				return resource.CreatedAt.Format("2006-01-02")
+			}},
+			{Header: "REASON", Value: func(resource *orphans.Resource) string { return resource.Reason }},
+		},
+	})
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	var spin *spinner.Spinner
+	if r.Reporter.IsTerminal() {
+		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	}
+	if spin != nil {
+		r.Reporter.Infof("Looking for orphaned resources in AWS account '%s'", r.Creator.AccountID)
+		spin.Start()
+	}
+
+	resources, err := orphans.Find(r.AWSClient, r.OCMClient, r.Creator.AccountID)
+
+	if spin != nil {
+		spin.Stop()
+	}
+
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	if len(resources) == 0 && !output.HasFlag() {
+		r.Reporter.Infof("No orphaned resources found")
+		os.Exit(0)
+	}
+
+	err = output.Print(resources)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+}
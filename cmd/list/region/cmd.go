@@ -19,7 +19,6 @@ package region
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -122,40 +121,24 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(availableRegions)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
+	columns := []output.Column{
+		{Header: "ID", Value: func(region *cmv1.CloudRegion) string { return region.ID() }},
+		{Header: "NAME", Value: func(region *cmv1.CloudRegion) string { return region.DisplayName() }},
+		{Header: "MULTI-AZ SUPPORT", Value: func(region *cmv1.CloudRegion) string {
+			return fmt.Sprintf("%t", region.SupportsMultiAZ())
+		}},
 	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	headerFormat := "ID\t\tNAME\t\tMULTI-AZ SUPPORT\n"
 	if hypershiftEnabled {
-		headerFormat = "ID\t\tNAME\t\tMULTI-AZ SUPPORT\t\tHOSTED-CP SUPPORT\n"
+		columns = append(columns, output.Column{
+			Header: "HOSTED-CP SUPPORT",
+			Value: func(region *cmv1.CloudRegion) string {
+				return fmt.Sprintf("%t", region.SupportsHypershift())
+			},
+		})
 	}
-	fmt.Fprint(writer, headerFormat)
-
-	for _, region := range availableRegions {
-		if hypershiftEnabled {
-			fmt.Fprintf(writer,
-				"%s\t\t%s\t\t%t\t\t%t\n",
-				region.ID(),
-				region.DisplayName(),
-				region.SupportsMultiAZ(),
-				region.SupportsHypershift(),
-			)
-		} else {
-			fmt.Fprintf(writer,
-				"%s\t\t%s\t\t%t\n",
-				region.ID(),
-				region.DisplayName(),
-				region.SupportsMultiAZ(),
-			)
-		}
+	err = output.Print(availableRegions, columns...)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
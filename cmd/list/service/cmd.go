@@ -17,9 +17,7 @@ limitations under the License.
 package service
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	msv1 "github.com/openshift-online/ocm-sdk-go/servicemgmt/v1"
 	"github.com/openshift/rosa/pkg/output"
@@ -46,6 +44,15 @@ func init() {
 	flags.SortFlags = false
 
 	output.AddFlag(Cmd)
+
+	output.Register(&msv1.ManagedService{}, output.Type{
+		Columns: []output.Column{
+			{Header: "SERVICE_ID", Value: func(srv *msv1.ManagedService) string { return srv.ID() }},
+			{Header: "SERVICE", Value: func(srv *msv1.ManagedService) string { return srv.Service() }},
+			{Header: "SERVICE_STATE", Value: func(srv *msv1.ManagedService) string { return srv.ServiceState() }},
+			{Header: "CLUSTER_NAME", Value: func(srv *msv1.ManagedService) string { return srv.Cluster().Name() }},
+		},
+	})
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -62,12 +69,9 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "SERVICE_ID\tSERVICE\tSERVICE_STATE\tCLUSTER_NAME\n")
-	servicesList.Each(func(srv *msv1.ManagedService) bool {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
-			srv.ID(), srv.Service(), srv.ServiceState(), srv.Cluster().Name())
-		return true
-	})
-	writer.Flush()
+	err = output.Print(servicesList.Slice())
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
@@ -17,10 +17,9 @@ limitations under the License.
 package tuningconfigs
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/input"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
@@ -41,6 +40,13 @@ var Cmd = &cobra.Command{
 func init() {
 	ocm.AddClusterFlag(Cmd)
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.TuningConfig{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(tuningConfig *cmv1.TuningConfig) string { return tuningConfig.ID() }},
+			{Header: "NAME", Value: func(tuningConfig *cmv1.TuningConfig) string { return tuningConfig.Name() }},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -60,29 +66,14 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(tuningConfigs)
-		if err != nil {
-			r.Reporter.Errorf("%v", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(tuningConfigs) == 0 {
+	if len(tuningConfigs) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no tuning configs for this cluster.")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tNAME\n")
-	for _, tuningConfig := range tuningConfigs {
-		fmt.Fprintf(writer, "%s\t%s\n",
-			tuningConfig.ID(),
-			tuningConfig.Name(),
-		)
+	err = output.Print(tuningConfigs)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
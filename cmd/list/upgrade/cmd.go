@@ -21,15 +21,21 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
+// availableUpgrade is the printed representation of a version that a cluster can be upgraded to.
+type availableUpgrade struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes,omitempty"`
+}
+
 var Cmd = &cobra.Command{
 	Use:     "upgrades",
 	Aliases: []string{"upgrade"},
@@ -40,6 +46,14 @@ var Cmd = &cobra.Command{
 
 func init() {
 	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
+
+	output.Register(availableUpgrade{}, output.Type{
+		Columns: []output.Column{
+			{Header: "VERSION", Value: func(upgrade availableUpgrade) string { return upgrade.Version }},
+			{Header: "NOTES", Value: func(upgrade availableUpgrade) string { return upgrade.Notes }},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -67,7 +81,7 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(availableUpgrades) == 0 {
+	if len(availableUpgrades) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no available upgrades for cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
@@ -89,22 +103,24 @@ func run(_ *cobra.Command, _ []string) {
 		}
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "VERSION\tNOTES\n")
-	for i, availableUpgrade := range availableUpgrades {
+	upgrades := make([]availableUpgrade, len(availableUpgrades))
+	for i, version := range availableUpgrades {
 		notes := ""
-		if notes == "" && (i == 0 || availableUpgrade == latestRev) {
+		if notes == "" && (i == 0 || version == latestRev) {
 			notes = "recommended"
 		}
 		if !isHypershift {
-			notes = formatScheduledUpgrade(availableUpgrade, scheduledUpgrade, notes, upgradeState)
+			notes = formatScheduledUpgrade(version, scheduledUpgrade, notes, upgradeState)
 		} else {
-			notes = formatScheduledUpgradeHypershift(availableUpgrade, controlPlaneScheduledUpgrade, notes)
+			notes = formatScheduledUpgradeHypershift(version, controlPlaneScheduledUpgrade, notes)
 		}
-		fmt.Fprintf(writer, "%s\t%s\n", availableUpgrade, notes)
+		upgrades[i] = availableUpgrade{Version: version, Notes: notes}
+	}
+	err = output.Print(upgrades)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func formatScheduledUpgrade(availableUpgrade string,
@@ -17,20 +17,25 @@ limitations under the License.
 package user
 
 import (
-	"fmt"
-	"math"
 	"os"
+	"sort"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/cmd/create/idp"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
+// clusterUser is the printed representation of an administrative user and the groups it belongs to.
+type clusterUser struct {
+	ID     string   `json:"id"`
+	Groups []string `json:"groups"`
+}
+
 var Cmd = &cobra.Command{
 	Use:     "users",
 	Aliases: []string{"user"},
@@ -43,6 +48,14 @@ var Cmd = &cobra.Command{
 
 func init() {
 	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
+
+	output.Register(clusterUser{}, output.Type{
+		Columns: []output.Column{
+			{Header: "ID", Value: func(user clusterUser) string { return user.ID }},
+			{Header: "GROUPS", Value: func(user clusterUser) string { return strings.Join(user.Groups, ", ") }},
+		},
+	})
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -80,32 +93,29 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 {
+	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 && !output.HasFlag() {
 		r.Reporter.Warnf("There are no users configured for cluster '%s'", clusterKey)
 		os.Exit(1)
 	}
 
-	longestUserId := 0.0
 	groups := make(map[string][]string)
 	for _, user := range clusterAdmins {
-		longestUserId = math.Max(longestUserId, float64(len(user.ID())))
 		groups[user.ID()] = []string{"cluster-admins"}
 	}
 	for _, user := range dedicatedAdmins {
-		longestUserId = math.Max(longestUserId, float64(len(user.ID())))
-		if _, ok := groups[user.ID()]; ok {
-			groups[user.ID()] = []string{"cluster-admins", "dedicated-admins"}
-		} else {
-			groups[user.ID()] = []string{"dedicated-admins"}
-		}
+		groups[user.ID()] = append(groups[user.ID()], "dedicated-admins")
 	}
+	users := make([]clusterUser, 0, len(groups))
+	for id, userGroups := range groups {
+		users = append(users, clusterUser{ID: id, Groups: userGroups})
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, int(longestUserId)+2, 4, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tGROUPS\t\n")
-
-	for u, r := range groups {
-		fmt.Fprintf(writer, "%s\t%s\t\n", u, strings.Join(r, ", "))
-		writer.Flush()
+	err = output.Print(users)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
 }
@@ -16,7 +16,6 @@ package userroles
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -42,6 +41,12 @@ func init() {
 	output.AddFlag(Cmd)
 }
 
+var columns = []output.Column{
+	{Header: "ROLE NAME", Value: func(role aws.Role) string { return role.RoleName }},
+	{Header: "ROLE ARN", Value: func(role aws.Role) string { return role.RoleARN }},
+	{Header: "LINKED", Value: func(role aws.Role) string { return role.Linked }},
+}
+
 func run(_ *cobra.Command, _ []string) {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
@@ -66,26 +71,16 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(userRoles) == 0 {
+	if len(userRoles) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No user roles available")
 		os.Exit(0)
 	}
-	if output.HasFlag() {
-		err = output.Print(userRoles)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprint(writer, "ROLE NAME\tROLE ARN\tLINKED\n")
-	for _, userRole := range userRoles {
-		fmt.Fprintf(writer, "%s\t%s\t%s\n", userRole.RoleName, userRole.RoleARN, userRole.Linked)
+	err = output.Print(userRoles, columns...)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func listUserRoles(r *rosa.Runtime) ([]aws.Role, error) {
@@ -17,10 +17,8 @@ limitations under the License.
 package version
 
 import (
-	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -59,6 +57,21 @@ func init() {
 		false,
 		"Lists only versions that are hosted-cp enabled")
 	output.AddFlag(Cmd)
+
+	output.Register(&cmv1.Version{}, output.Type{
+		Columns: []output.Column{
+			{Header: "VERSION", Value: func(version *cmv1.Version) string { return version.RawID() }},
+			{Header: "DEFAULT", Value: func(version *cmv1.Version) string {
+				if version.Default() {
+					return "yes"
+				}
+				return "no"
+			}},
+			{Header: "AVAILABLE UPGRADES", Value: func(version *cmv1.Version) string {
+				return strings.Join(version.AvailableUpgrades(), ", ")
+			}},
+		},
+	})
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -88,30 +101,9 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(availableVersions)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "VERSION\t\tDEFAULT\t\tAVAILABLE UPGRADES\n")
-
-	for _, version := range availableVersions {
-		isDefault := "no"
-		if version.Default() {
-			isDefault = "yes"
-		}
-		fmt.Fprintf(writer,
-			"%s\t\t%s\t\t%s\n",
-			version.RawID(),
-			isDefault,
-			strings.Join(version.AvailableUpgrades(), ", "),
-		)
+	err = output.Print(availableVersions)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
@@ -0,0 +1,101 @@
+package version
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/cassette"
+)
+
+var _ = Describe("List versions", func() {
+	var (
+		dir    string
+		server *httptest.Server
+		calls  int
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/token":
+				_, _ = w.Write([]byte(`{"access_token": "` + accessToken + `", ` +
+					`"refresh_token": "my-new-refresh-token", "token_type": "Bearer", "expires_in": 900}`))
+			case "/api/clusters_mgmt/v1/versions":
+				_, _ = w.Write([]byte(`{"kind": "VersionList", "page": 1, "size": 1, "total": 1, "items": [
+					{"kind": "Version", "id": "openshift-v4.12.10", "raw_id": "4.12.10", "enabled": true,
+					"default": true, "channel_group": "stable", "rosa_enabled": true}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		config := filepath.Join(dir, "ocm.json")
+		Expect(os.WriteFile(config, []byte(`{"refresh_token": "my-refresh-token", `+
+			`"token_url": "`+server.URL+`/token", "url": "`+server.URL+`"}`), 0600)).To(Succeed())
+		os.Setenv("OCM_CONFIG", config)
+		os.Setenv(cassette.DirEnv, filepath.Join(dir, "cassettes"))
+		Expect(Cmd.Flags().Set("output", "json")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.Unsetenv("OCM_CONFIG")
+		os.Unsetenv(cassette.ModeEnv)
+		os.Unsetenv(cassette.DirEnv)
+	})
+
+	execute := func(mode string) string {
+		os.Setenv(cassette.ModeEnv, mode)
+		Expect(cassette.Start("rosa list versions", []string{"list", "versions"})).To(Succeed())
+		reader, writer, err := os.Pipe()
+		Expect(err).NotTo(HaveOccurred())
+		stdout := os.Stdout
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = stdout
+		}()
+		Cmd.Run(Cmd, []string{})
+		Expect(writer.Close()).To(Succeed())
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("Replays the command from the cassette without sending requests", func() {
+		recorded := execute(cassette.ModeRecord)
+		Expect(recorded).To(ContainSubstring(`"raw_id": "4.12.10"`))
+		Expect(calls).To(Equal(2))
+		files, err := os.ReadDir(filepath.Join(dir, "cassettes"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		data, err := os.ReadFile(filepath.Join(dir, "cassettes", files[0].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring(accessToken))
+		Expect(string(data)).NotTo(ContainSubstring("my-refresh-token"))
+		Expect(string(data)).NotTo(ContainSubstring("my-new-refresh-token"))
+		Expect(json.Valid(data)).To(BeTrue())
+
+		server.Close()
+		replayed := execute(cassette.ModeReplay)
+		Expect(replayed).To(Equal(recorded))
+		Expect(calls).To(Equal(2))
+	})
+})
+
+// accessToken is an unsigned access token that doesn't expire until 2100.
+var accessToken = strings.Join([]string{
+	"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9",
+	"eyJ0eXAiOiJCZWFyZXIiLCJleHAiOjQxMDI0NDQ4MDB9",
+	"signature",
+}, ".")
@@ -27,13 +27,16 @@ import (
 	"github.com/spf13/cobra"
 	errors "github.com/zgalor/weberr"
 
+	"github.com/openshift/rosa/pkg/helper/logs"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
 var args struct {
-	tail  int
-	watch bool
+	tail    int
+	watch   bool
+	summary bool
+	options logs.Options
 }
 
 var Cmd = &cobra.Command{
@@ -44,7 +47,13 @@ var Cmd = &cobra.Command{
   rosa logs install mycluster --tail=100
 
   # Show install logs for a cluster using the --cluster flag
-  rosa logs install --cluster=mycluster`,
+  rosa logs install --cluster=mycluster
+
+  # Watch the errors logged in the last 30 minutes, saving the complete log to a file
+  rosa logs install --cluster=mycluster --watch --since=30m --level=error --output-file=install.log
+
+  # Watch the phases of the installation, and the possible causes if it fails
+  rosa logs install --cluster=mycluster --watch --summary`,
 	Run: run,
 }
 
@@ -67,6 +76,16 @@ func init() {
 		false,
 		"After getting the logs, watch for changes.",
 	)
+
+	flags.BoolVar(
+		&args.summary,
+		"summary",
+		false,
+		"Instead of the log lines, show the phases of the installation and, if it fails, the known "+
+			"causes found in the logs and the commands that verify them.",
+	)
+
+	logs.AddFlags(flags, &args.options)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -115,8 +134,19 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	stream, err := logs.NewStream(args.options)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	defer stream.Close()
+	if args.summary {
+		stream.Progress = logs.NewProgress()
+	}
+
 	// Get logs from Hive
-	logs, err := r.OCMClient.GetInstallLogs(cluster.ID(), args.tail)
+	stream.Tail = args.tail
+	log, err := r.OCMClient.GetInstallLogsFrom(cluster.ID(), 0)
 	if err != nil {
 		if errors.GetType(err) == errors.NotFound {
 			r.Reporter.Infof(pendingMessage)
@@ -125,7 +155,16 @@ func run(cmd *cobra.Command, argv []string) {
 			os.Exit(1)
 		}
 	}
-	printLog(logs, nil)
+	if args.summary && !watch {
+		_, err = stream.Next(log.Content())
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		logs.ReportProgress(r, cluster, stream.Progress)
+		return
+	}
+	printLog(r, stream, log, nil)
 
 	if watch {
 		if cluster.State() == cmv1.ClusterStateReady {
@@ -142,36 +181,61 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 
 		// Poll for changing logs:
-		response, err := r.OCMClient.PollInstallLogs(cluster.ID(), func(logResponse *cmv1.LogGetResponse) bool {
-			state, _ := r.OCMClient.GetClusterState(cluster.ID())
-			if state == cmv1.ClusterStateError {
-				r.Reporter.Errorf("There was an error installing cluster '%s'", clusterKey)
-				os.Exit(1)
-			}
-			if state == cmv1.ClusterStateReady {
-				r.Reporter.Infof("Cluster '%s' is now ready", clusterKey)
-				os.Exit(0)
-			}
-			printLog(logResponse.Body(), spin)
-			return false
-		})
+		offset := func() int {
+			return stream.Offset
+		}
+		err = r.OCMClient.PollInstallLogs(cluster.ID(), offset, args.options.Timeout,
+			func(log *cmv1.Log, err error) bool {
+				state, _ := r.OCMClient.GetClusterState(cluster.ID())
+				if state == cmv1.ClusterStateError {
+					if args.summary {
+						printLog(r, stream, log, spin)
+						if spin != nil {
+							spin.Stop()
+						}
+						reportProgress(r, stream, cluster.ID())
+					}
+					r.Reporter.Errorf("There was an error installing cluster '%s'", clusterKey)
+					os.Exit(1)
+				}
+				if state == cmv1.ClusterStateReady {
+					r.Reporter.Infof("Cluster '%s' is now ready", clusterKey)
+					os.Exit(0)
+				}
+				// The logs aren't available yet:
+				if err != nil {
+					return false
+				}
+				printLog(r, stream, log, spin)
+				return false
+			})
 		if err != nil {
-			if errors.GetType(err) != errors.NotFound {
-				r.Reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
-			}
+			r.Reporter.Errorf("Failed to watch logs for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
 		}
-		printLog(response, spin)
 	}
 }
 
-var lastLine string
+// reportProgress prints the phases of the installation and the known causes of the failure, using
+// the cluster as it is after the failure, which contains the provisioning error.
+func reportProgress(r *rosa.Runtime, stream *logs.Stream, clusterID string) {
+	cluster, err := r.OCMClient.GetClusterByID(clusterID, r.Creator)
+	if err != nil {
+		r.Reporter.Errorf("Failed to get cluster '%s': %v", clusterID, err)
+		os.Exit(1)
+	}
+	logs.ReportProgress(r, cluster, stream.Progress)
+}
 
 // Print next log lines
-func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
-	lines := findNextLines(logs)
-	if lines != "" {
-		fmt.Printf("%s\n", lines)
+func printLog(r *rosa.Runtime, stream *logs.Stream, log *cmv1.Log, spin *spinner.Spinner) {
+	lines, err := stream.Next(log.Content())
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if len(lines) > 0 {
+		fmt.Printf("%s\n", strings.Join(lines, "\n"))
 		if spin != nil {
 			spin.Stop()
 		}
@@ -179,25 +243,3 @@ func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
 		spin.Restart()
 	}
 }
-
-// Remove duplicate lines from the log poll response
-func findNextLines(logs *cmv1.Log) string {
-	lines := strings.Split(logs.Content(), "\n")
-	// Last element is always empty, remove it
-	if len(lines) > 0 {
-		lines = lines[:len(lines)-1]
-	}
-	// Find where the new logs and the last line overlap
-	for i, line := range lines {
-		if lastLine != "" && line == lastLine {
-			// Remove any duplicate lines
-			lines = lines[i+1:]
-			break
-		}
-	}
-	// Store the last log lne
-	if len(lines) > 0 {
-		lastLine = lines[len(lines)-1]
-	}
-	return strings.Join(lines, "\n")
-}
@@ -27,13 +27,15 @@ import (
 	"github.com/spf13/cobra"
 	errors "github.com/zgalor/weberr"
 
+	"github.com/openshift/rosa/pkg/helper/logs"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
 var args struct {
-	tail  int
-	watch bool
+	tail    int
+	watch   bool
+	options logs.Options
 }
 
 var Cmd = &cobra.Command{
@@ -44,7 +46,10 @@ var Cmd = &cobra.Command{
   rosa logs uninstall mycluster --tail=100
 
   # Show uninstall logs for a cluster using the --cluster flag
-  rosa logs uninstall --cluster=mycluster`,
+  rosa logs uninstall --cluster=mycluster
+
+  # Watch the errors logged in the last 30 minutes, saving the complete log to a file
+  rosa logs uninstall --cluster=mycluster --watch --since=30m --level=error --output-file=uninstall.log`,
 	Run: run,
 }
 
@@ -67,6 +72,8 @@ func init() {
 		false,
 		"After getting the logs, watch for changes.",
 	)
+
+	logs.AddFlags(flags, &args.options)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -101,8 +108,16 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	stream, err := logs.NewStream(args.options)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	defer stream.Close()
+
 	// Get logs from Hive
-	logs, err := r.OCMClient.GetUninstallLogs(cluster.ID(), args.tail)
+	stream.Tail = args.tail
+	log, err := r.OCMClient.GetUninstallLogsFrom(cluster.ID(), 0)
 	if err != nil {
 		if errors.GetType(err) == errors.NotFound {
 			r.Reporter.Warnf("Logs for cluster '%s' are not available", clusterKey)
@@ -111,7 +126,7 @@ func run(cmd *cobra.Command, argv []string) {
 			os.Exit(1)
 		}
 	}
-	printLog(logs, nil)
+	printLog(r, stream, log, nil)
 
 	if watch {
 		var spin *spinner.Spinner
@@ -123,32 +138,46 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 
 		// Poll for changing logs:
-		response, err := r.OCMClient.PollUninstallLogs(cluster.ID(), func(logResponse *cmv1.LogGetResponse) bool {
-			state, err := r.OCMClient.GetClusterState(cluster.ID())
-			if err != nil || state == cmv1.ClusterState("") {
-				r.Reporter.Infof("Cluster '%s' completed uninstallation", clusterKey)
-				os.Exit(0)
-			}
-			printLog(logResponse.Body(), spin)
-			return false
-		})
+		offset := func() int {
+			return stream.Offset
+		}
+		err = r.OCMClient.PollUninstallLogs(cluster.ID(), offset, args.options.Timeout,
+			func(log *cmv1.Log, err error) bool {
+				// The logs of a cluster that has been deleted aren't found:
+				if uninstalled(r, cluster.ID()) {
+					return true
+				}
+				if err == nil {
+					printLog(r, stream, log, spin)
+				}
+				return false
+			})
 		if err != nil {
-			if errors.GetType(err) != errors.NotFound {
-				r.Reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
-			}
+			r.Reporter.Errorf("Failed to watch logs for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		if spin != nil {
+			spin.Stop()
 		}
-		printLog(response, spin)
+		r.Reporter.Infof("Cluster '%s' completed uninstallation", clusterKey)
 	}
 }
 
-var lastLine string
+// uninstalled returns true if the cluster doesn't exist any more.
+func uninstalled(r *rosa.Runtime, clusterID string) bool {
+	state, err := r.OCMClient.GetClusterState(clusterID)
+	return err != nil || state == cmv1.ClusterState("")
+}
 
 // Print next log lines
-func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
-	lines := findNextLines(logs)
-	if lines != "" {
-		fmt.Printf("%s\n", lines)
+func printLog(r *rosa.Runtime, stream *logs.Stream, log *cmv1.Log, spin *spinner.Spinner) {
+	lines, err := stream.Next(log.Content())
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if len(lines) > 0 {
+		fmt.Printf("%s\n", strings.Join(lines, "\n"))
 		if spin != nil {
 			spin.Stop()
 		}
@@ -156,25 +185,3 @@ func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
 		spin.Restart()
 	}
 }
-
-// Remove duplicate lines from the log poll response
-func findNextLines(logs *cmv1.Log) string {
-	lines := strings.Split(logs.Content(), "\n")
-	// Last element is always empty, remove it
-	if len(lines) > 0 {
-		lines = lines[:len(lines)-1]
-	}
-	// Find where the new logs and the last line overlap
-	for i, line := range lines {
-		if lastLine != "" && line == lastLine {
-			// Remove any duplicate lines
-			lines = lines[i+1:]
-			break
-		}
-	}
-	// Store the last log lne
-	if len(lines) > 0 {
-		lastLine = lines[len(lines)-1]
-	}
-	return strings.Join(lines, "\n")
-}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	prefix    string
+	hostedCP  bool
+	toVersion string
+	history   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "Rollback the policies of the account roles to a previous version",
+	Long: "Restore the versions of the permission policies of the account roles that were created for " +
+		"a previous version of OpenShift, and revert the OpenShift version tags of the roles and the " +
+		"policies. Only the policy versions kept by 'rosa upgrade account-roles' can be restored.",
+	Example: `  # List the policy versions of the account roles with prefix 'myprefix'
+  rosa rollback account-roles --prefix myprefix --history
+
+  # Restore the policy versions of the account roles created for OpenShift 4.12
+  rosa rollback account-roles --prefix myprefix --to-version 4.12`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.prefix,
+		"prefix",
+		"p",
+		aws.DefaultPrefix,
+		"User-defined prefix of the account roles",
+	)
+
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"Rollback the account roles of hosted control planes (HyperShift)",
+	)
+
+	flags.StringVar(
+		&args.toVersion,
+		"to-version",
+		"",
+		"Version of OpenShift whose policy versions are restored",
+	)
+
+	flags.BoolVar(
+		&args.history,
+		"history",
+		false,
+		"List the versions of the policies of the account roles, and the versions of OpenShift and rosa "+
+			"that created them",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	if args.history == (args.toVersion != "") {
+		r.Reporter.Errorf("Either '--to-version' or '--history' is required")
+		os.Exit(1)
+	}
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if mode == "" {
+		mode = aws.ModeAuto
+	}
+
+	accountRoles := aws.AccountRoles
+	if args.hostedCP {
+		accountRoles = aws.HCPAccountRoles
+	}
+	files := make([]string, 0, len(accountRoles))
+	for file := range accountRoles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	roleNames := []string{}
+	for _, file := range files {
+		roleName := aws.GetRoleName(args.prefix, accountRoles[file].Name)
+		_, err := r.AWSClient.GetRoleTags(roleName)
+		if err != nil {
+			if errors.GetType(err) == errors.NotFound {
+				r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+				continue
+			}
+			r.Reporter.Errorf("Failed to get role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		roleNames = append(roleNames, roleName)
+	}
+	if len(roleNames) == 0 {
+		r.Reporter.Errorf("There are no account roles with prefix '%s'", args.prefix)
+		os.Exit(1)
+	}
+
+	if args.history {
+		err = roles.PrintPolicyHistory(r, roleNames)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	rollbacks := []roles.PolicyRollback{}
+	for _, roleName := range roleNames {
+		roleRollbacks, err := roles.PlanRollback(r, roleName, args.toVersion)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		rollbacks = append(rollbacks, roleRollbacks...)
+	}
+	if len(rollbacks) == 0 {
+		r.Reporter.Infof("The policies of the account roles with prefix '%s' are already the ones of "+
+			"OpenShift '%s'", args.prefix, args.toVersion)
+		return
+	}
+
+	err = roles.Rollback(r, mode, args.toVersion, rollbacks)
+	if err != nil {
+		r.OCMClient.LogEvent("ROSARollbackAccountRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSARollbackAccountRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+}
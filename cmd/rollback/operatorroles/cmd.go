@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"os"
+	"sort"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+const (
+	PrefixFlag   = "prefix"
+	HostedCpFlag = "hosted-cp"
+)
+
+var args struct {
+	prefix    string
+	hostedCp  bool
+	toVersion string
+	history   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "Rollback the policies of the operator roles to a previous version",
+	Long: "Restore the versions of the permission policies of the operator roles that were created for " +
+		"a previous version of OpenShift, and revert the OpenShift version tags of the roles and the " +
+		"policies. Only the policy versions kept by 'rosa upgrade operator-roles' can be restored.",
+	Example: `  # List the policy versions of the operator roles of a cluster
+  rosa rollback operator-roles --cluster=mycluster --history
+
+  # Restore the policy versions of the operator roles created for OpenShift 4.12
+  rosa rollback operator-roles --prefix=myprefix --to-version=4.12`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	ocm.AddOptionalClusterFlag(Cmd)
+
+	flags.StringVar(
+		&args.prefix,
+		PrefixFlag,
+		"",
+		"User-defined prefix of the operator roles. Not to be used alongside --cluster flag.",
+	)
+
+	flags.BoolVar(
+		&args.hostedCp,
+		HostedCpFlag,
+		false,
+		"Indicates whether to rollback the hosted control planes operator roles when using --prefix option.",
+	)
+
+	flags.StringVar(
+		&args.toVersion,
+		"to-version",
+		"",
+		"Version of OpenShift whose policy versions are restored",
+	)
+
+	flags.BoolVar(
+		&args.history,
+		"history",
+		false,
+		"List the versions of the policies of the operator roles, and the versions of OpenShift and rosa "+
+			"that created them",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	if args.history == (args.toVersion != "") {
+		r.Reporter.Errorf("Either '--to-version' or '--history' is required")
+		os.Exit(1)
+	}
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if mode == "" {
+		mode = aws.ModeAuto
+	}
+
+	clusterFlag := cmd.Flags().Changed("cluster")
+	if clusterFlag == (args.prefix != "") {
+		r.Reporter.Errorf("Either a cluster key or an operator roles prefix is required")
+		os.Exit(1)
+	}
+	if clusterFlag && cmd.Flags().Changed(HostedCpFlag) {
+		r.Reporter.Errorf("Flag '%s' can't be used alongside the '--cluster' flag", HostedCpFlag)
+		os.Exit(1)
+	}
+
+	hostedCP := args.hostedCp
+	prefix := args.prefix
+	var cluster *cmv1.Cluster
+	if clusterFlag {
+		r.GetClusterKey()
+		cluster = r.FetchCluster()
+		if cluster.AWS().STS().RoleARN() == "" {
+			r.Reporter.Errorf("Cluster '%s' is not an STS cluster", r.ClusterKey)
+			os.Exit(1)
+		}
+		hostedCP = cluster.Hypershift().Enabled()
+		prefix = cluster.AWS().STS().OperatorRolePrefix()
+	}
+
+	credRequests, err := r.OCMClient.GetCredRequests(hostedCP)
+	if err != nil {
+		r.Reporter.Errorf("Error getting operator credential request from OCM %v", err)
+		os.Exit(1)
+	}
+	keys := make([]string, 0, len(credRequests))
+	for key := range credRequests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	roleNames := []string{}
+	for _, key := range keys {
+		operator := credRequests[key]
+		var roleName string
+		if cluster != nil {
+			name, found := aws.FindOperatorRoleNameBySTSOperator(cluster, operator)
+			if !found {
+				continue
+			}
+			roleName = name
+		} else {
+			roleName, err = aws.GetResourceIdFromARN(aws.ComputeOperatorRoleArn(prefix, operator,
+				r.Creator, ""))
+			if err != nil {
+				r.Reporter.Errorf("%v", err)
+				os.Exit(1)
+			}
+		}
+		_, err := r.AWSClient.GetRoleTags(roleName)
+		if err != nil {
+			if errors.GetType(err) == errors.NotFound {
+				r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+				continue
+			}
+			r.Reporter.Errorf("Failed to get role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		roleNames = append(roleNames, roleName)
+	}
+	if len(roleNames) == 0 {
+		r.Reporter.Errorf("There are no operator roles with prefix '%s'", prefix)
+		os.Exit(1)
+	}
+
+	if args.history {
+		err = roles.PrintPolicyHistory(r, roleNames)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	rollbacks := []roles.PolicyRollback{}
+	for _, roleName := range roleNames {
+		roleRollbacks, err := roles.PlanRollback(r, roleName, args.toVersion)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		rollbacks = append(rollbacks, roleRollbacks...)
+	}
+	if len(rollbacks) == 0 {
+		r.Reporter.Infof("The policies of the operator roles with prefix '%s' are already the ones of "+
+			"OpenShift '%s'", prefix, args.toVersion)
+		return
+	}
+
+	err = roles.Rollback(r, mode, args.toVersion, rollbacks)
+	if err != nil {
+		r.OCMClient.LogEvent("ROSARollbackOperatorRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSARollbackOperatorRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+}
@@ -23,9 +23,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/cmd/adopt"
+	"github.com/openshift/rosa/cmd/apply"
+	"github.com/openshift/rosa/cmd/cleanup"
 	"github.com/openshift/rosa/cmd/completion"
 	"github.com/openshift/rosa/cmd/create"
 	"github.com/openshift/rosa/cmd/describe"
+	"github.com/openshift/rosa/cmd/diff"
 	"github.com/openshift/rosa/cmd/dlt"
 	"github.com/openshift/rosa/cmd/docs"
 	"github.com/openshift/rosa/cmd/download"
@@ -41,14 +45,20 @@ import (
 	"github.com/openshift/rosa/cmd/logs"
 	"github.com/openshift/rosa/cmd/resume"
 	"github.com/openshift/rosa/cmd/revoke"
+	"github.com/openshift/rosa/cmd/rollback"
+	"github.com/openshift/rosa/cmd/rotate"
 	"github.com/openshift/rosa/cmd/uninstall"
 	"github.com/openshift/rosa/cmd/unlink"
 	"github.com/openshift/rosa/cmd/upgrade"
 	"github.com/openshift/rosa/cmd/verify"
 	"github.com/openshift/rosa/cmd/version"
+	"github.com/openshift/rosa/cmd/wait"
 	"github.com/openshift/rosa/cmd/whoami"
 	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/cassette"
 	"github.com/openshift/rosa/pkg/color"
+	"github.com/openshift/rosa/pkg/har"
+	"github.com/openshift/rosa/pkg/reporter"
 )
 
 var root = &cobra.Command{
@@ -57,18 +67,42 @@ var root = &cobra.Command{
 	Long: "Command line tool for Red Hat OpenShift Service on AWS.\n" +
 		"For further documentation visit " +
 		"https://access.redhat.com/documentation/en-us/red_hat_openshift_service_on_aws\n",
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		err := reporter.ValidateLogFormat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		reporter.SetCommandPath(cmd.CommandPath())
+		err = har.Start()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		err = cassette.Start(cmd.CommandPath(), os.Args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+	},
 }
 
 func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
 	color.AddFlag(root)
+	reporter.AddFlag(root)
 	arguments.AddDebugFlag(fs)
+	arguments.AddRecordFlag(fs)
 
 	// Register the subcommands:
+	root.AddCommand(adopt.Cmd)
+	root.AddCommand(apply.Cmd)
+	root.AddCommand(cleanup.Cmd)
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(create.Cmd)
 	root.AddCommand(describe.Cmd)
+	root.AddCommand(diff.Cmd)
 	root.AddCommand(dlt.Cmd)
 	root.AddCommand(docs.Cmd)
 	root.AddCommand(download.Cmd)
@@ -81,10 +115,13 @@ func init() {
 	root.AddCommand(logout.Cmd)
 	root.AddCommand(logs.Cmd)
 	root.AddCommand(revoke.Cmd)
+	root.AddCommand(rollback.Cmd)
+	root.AddCommand(rotate.Cmd)
 	root.AddCommand(uninstall.Cmd)
 	root.AddCommand(upgrade.Cmd)
 	root.AddCommand(verify.Cmd)
 	root.AddCommand(version.Cmd)
+	root.AddCommand(wait.Cmd)
 	root.AddCommand(whoami.Cmd)
 	root.AddCommand(hibernate.GenerateCommand())
 	root.AddCommand(resume.GenerateCommand())
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/cassette"
+)
+
+var _ = Describe("Cassettes", func() {
+	// run executes the command line with the root command, like the 'main' function, and returns
+	// what it writes to the standard output:
+	run := func(args ...string) string {
+		oldArgs, oldStdout := os.Args, os.Stdout
+		defer func() {
+			os.Args, os.Stdout = oldArgs, oldStdout
+		}()
+		reader, writer, err := os.Pipe()
+		Expect(err).ToNot(HaveOccurred())
+		os.Args = append([]string{"rosa"}, args...)
+		os.Stdout = writer
+		root.SetArgs(args)
+		err = root.Execute()
+		Expect(writer.Close()).To(Succeed())
+		Expect(err).ToNot(HaveOccurred())
+		output, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		return string(output)
+	}
+
+	It("Replays a recorded command without logging in", func() {
+		// The configuration file doesn't exist, so the command fails unless it uses a placeholder
+		// token:
+		os.Setenv("OCM_CONFIG", filepath.Join(GinkgoT().TempDir(), "ocm.json"))
+		os.Setenv(cassette.ModeEnv, cassette.ModeReplay)
+		os.Setenv(cassette.DirEnv, filepath.Join("testdata", "cassettes"))
+		defer func() {
+			os.Unsetenv("OCM_CONFIG")
+			os.Unsetenv(cassette.ModeEnv)
+			os.Unsetenv(cassette.DirEnv)
+		}()
+
+		Expect(run("list", "versions")).To(Equal("" +
+			"VERSION  DEFAULT  AVAILABLE UPGRADES\n" +
+			"4.12.6   no       \n" +
+			"4.12.5   yes      4.12.6\n" +
+			"4.11.30  no       4.11.31, 4.12.5\n"))
+	})
+})
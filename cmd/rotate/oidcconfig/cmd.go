@@ -0,0 +1,440 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/briandowns/spinner"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-config",
+	Aliases: []string{"oidcconfig"},
+	Short:   "Rotate the signing key of an OIDC config",
+	Long: "Rotate the key pair of an unmanaged OIDC config in two steps. First a new key pair is " +
+		"generated, its public key is published in the JSON web key set next to the current one, and " +
+		"the private key secret is updated with a new version containing the new private key, which is " +
+		"used by the clusters created from then on. Clusters that already use the OIDC config keep " +
+		"signing their tokens with the previous key, so it can only be removed once no cluster uses " +
+		"the OIDC config. Then, once the grace period has passed and the tokens signed with the " +
+		"previous key have expired, run the command again with '--remove-previous-key' to remove the " +
+		"previous public key from the JSON web key set.",
+	Example: `  # Generate a new key pair for the OIDC config
+  rosa rotate oidc-config --oidc-config-id <oidc_config_id>
+
+  # Remove the previous key once the grace period has passed
+  rosa rotate oidc-config --oidc-config-id <oidc_config_id> --remove-previous-key
+
+  # Print the AWS CLI commands that rotate the key instead of running them
+  rosa rotate oidc-config --oidc-config-id <oidc_config_id> --mode manual`,
+	Run: run,
+}
+
+const (
+	OidcConfigIdFlag      = "oidc-config-id"
+	removePreviousKeyFlag = "remove-previous-key"
+	gracePeriodFlag       = "grace-period"
+
+	defaultGracePeriod = 24 * time.Hour
+)
+
+var args struct {
+	oidcConfigId      string
+	removePreviousKey bool
+	gracePeriod       time.Duration
+	region            string
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.oidcConfigId,
+		OidcConfigIdFlag,
+		"",
+		"Registered ID for identification of OIDC config",
+	)
+	flags.BoolVar(
+		&args.removePreviousKey,
+		removePreviousKeyFlag,
+		false,
+		"Remove the previous public key from the JSON web key set, once the grace period of a "+
+			"previous rotation has passed.",
+	)
+	flags.DurationVar(
+		&args.gracePeriod,
+		gracePeriodFlag,
+		defaultGracePeriod,
+		"Minimum time since the rotation before the previous public key can be removed. It should "+
+			"be longer than the lifetime of the tokens signed with the previous key.",
+	)
+
+	aws.AddModeFlag(Cmd)
+
+	interactive.AddFlag(flags)
+}
+
+// rotation contains the details of the OIDC config, and of the current version of its private
+// key secret, needed to rotate its key pair.
+type rotation struct {
+	oidcConfigId string
+	issuerUrl    string
+	bucketName   string
+	secretArn    string
+	secret       *aws.SecretVersion
+	jwks         []byte
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	region, err := aws.GetRegion(arguments.GetRegion())
+	if err != nil {
+		r.Reporter.Errorf("Error getting region: %v", err)
+		os.Exit(1)
+	}
+	args.region = region
+
+	// Determine if interactive mode is needed
+	if !interactive.Enabled() && !cmd.Flags().Changed("mode") {
+		interactive.Enable()
+	}
+
+	if interactive.Enabled() {
+		mode, err = interactive.GetOption(interactive.Input{
+			Question: "OIDC Config rotation mode",
+			Help:     cmd.Flags().Lookup("mode").Usage,
+			Default:  aws.ModeAuto,
+			Options:  aws.Modes,
+			Required: true,
+		})
+		if err != nil {
+			r.Reporter.Errorf("Expected a valid OIDC config rotation mode: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	if args.oidcConfigId == "" || interactive.Enabled() {
+		args.oidcConfigId = interactive.GetOidcConfigID(r, cmd)
+	}
+
+	rotation := buildRotation(r)
+	if args.removePreviousKey {
+		removePreviousKey(r, mode, rotation)
+	} else {
+		rotateKey(r, mode, rotation)
+	}
+}
+
+func buildRotation(r *rosa.Runtime) *rotation {
+	oidcConfig, err := r.OCMClient.GetOidcConfig(args.oidcConfigId)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem retrieving the OIDC Config '%s': %v", args.oidcConfigId, err)
+		os.Exit(1)
+	}
+	if oidcConfig.Managed() {
+		r.Reporter.Errorf("OIDC Config '%s' is managed by Red Hat, its keys can't be rotated", args.oidcConfigId)
+		os.Exit(1)
+	}
+	secretArn := oidcConfig.SecretArn()
+	parsedSecretArn, err := arn.Parse(secretArn)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem parsing secret ARN '%s' : %v", secretArn, err)
+		os.Exit(1)
+	}
+	if args.region != parsedSecretArn.Region {
+		r.Reporter.Errorf("Secret region '%s' differs from chosen region '%s', "+
+			"please run the command supplying region parameter.", parsedSecretArn.Region, args.region)
+		os.Exit(1)
+	}
+	bucketName, err := oidc_config.BucketNameFromSecretArn(secretArn)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem parsing secret ARN '%s' : %v", secretArn, err)
+		os.Exit(1)
+	}
+
+	secret, err := r.AWSClient.GetCurrentSecretVersion(secretArn)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem retrieving private key secret '%s': %v", secretArn, err)
+		os.Exit(1)
+	}
+	jwks, err := oidc_config.FetchDocument(oidcConfig.IssuerUrl(), oidc_config.JwksKey)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem retrieving the JSON web key set of OIDC Config '%s': %v",
+			args.oidcConfigId, err)
+		os.Exit(1)
+	}
+
+	return &rotation{
+		oidcConfigId: args.oidcConfigId,
+		issuerUrl:    oidcConfig.IssuerUrl(),
+		bucketName:   bucketName,
+		secretArn:    secretArn,
+		secret:       secret,
+		jwks:         jwks,
+	}
+}
+
+// rotateKey publishes the public key of a new key pair and then makes its private key the current
+// version of the secret, so that the tokens signed with it can be verified as soon as they are
+// issued. Clusters that already use the OIDC config keep signing with the previous key.
+func rotateKey(r *rosa.Runtime, mode string, rotation *rotation) {
+	keySet, err := oidc_config.ParseJSONWebKeySet(rotation.jwks)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if len(keySet.Keys) > 1 {
+		r.Reporter.Errorf("The JSON web key set of OIDC Config '%s' already contains %d keys, "+
+			"run 'rosa rotate oidc-config --oidc-config-id %s --%s' to finish the previous rotation first",
+			rotation.oidcConfigId, len(keySet.Keys), rotation.oidcConfigId, removePreviousKeyFlag)
+		os.Exit(1)
+	}
+
+	privateKey, publicKey, err := oidc_config.CreateKeyPair()
+	if err != nil {
+		r.Reporter.Errorf("There was a problem generating key pair: %s", err)
+		os.Exit(1)
+	}
+	jwks, err := oidc_config.AddKeyToJSONWebKeySet(rotation.jwks, publicKey)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem generating JSON Web Key Set: %s", err)
+		os.Exit(1)
+	}
+	// The version identifier is chosen here so that the manual commands can reference it:
+	versionID := uuid.New().String()
+
+	switch mode {
+	case aws.ModeAuto:
+		var spin *spinner.Spinner
+		if r.Reporter.IsTerminal() {
+			spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+			r.Reporter.Infof("Rotating the key of OIDC Config '%s'", rotation.oidcConfigId)
+			spin.Start()
+		}
+		err = publishKey(r, rotation, versionID, privateKey, jwks)
+		if spin != nil {
+			spin.Stop()
+		}
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			r.OCMClient.LogEvent("ROSARotateOIDCConfigModeAuto", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		r.OCMClient.LogEvent("ROSARotateOIDCConfigModeAuto", map[string]string{
+			ocm.Response: ocm.Success,
+		})
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Published the new key of OIDC Config '%s', clusters created from now on "+
+				"sign their tokens with it", rotation.oidcConfigId)
+		}
+	case aws.ModeManual:
+		r.OCMClient.LogEvent("ROSARotateOIDCConfigModeManual", map[string]string{})
+		commands := []string{}
+		privateKeyFilename := fmt.Sprintf("%s.key", oidc_config.PrivateKeySecretName(rotation.bucketName))
+		err = helper.SaveDocument(string(privateKey), privateKeyFilename)
+		if err != nil {
+			r.Reporter.Errorf("There was a problem saving private key to a file: %s", err)
+			os.Exit(1)
+		}
+		putSecretValueCommand := awscb.NewSecretsManagerCommandBuilder().
+			SetCommand(awscb.PutSecretValue).
+			AddParam(awscb.SecretID, rotation.secretArn).
+			AddParam(awscb.SecretString, fmt.Sprintf("file://%s", privateKeyFilename)).
+			AddParam(awscb.ClientRequestToken, versionID).
+			AddParam(awscb.VersionStages, aws.SecretStagePending).
+			AddParam(awscb.Region, args.region).
+			Build()
+		commands = append(commands, putSecretValueCommand)
+		commands = append(commands, fmt.Sprintf("rm %s", privateKeyFilename))
+		commands = append(commands, putJwksCommands(r, rotation, jwks)...)
+		promoteCommand := awscb.NewSecretsManagerCommandBuilder().
+			SetCommand(awscb.UpdateSecretVersionStage).
+			AddParam(awscb.SecretID, rotation.secretArn).
+			AddParam(awscb.VersionStage, aws.SecretStageCurrent).
+			AddParam(awscb.MoveToVersionId, versionID).
+			AddParam(awscb.RemoveFromVersionId, rotation.secret.ID).
+			AddParam(awscb.Region, args.region).
+			Build()
+		commands = append(commands, promoteCommand)
+		removePendingCommand := awscb.NewSecretsManagerCommandBuilder().
+			SetCommand(awscb.UpdateSecretVersionStage).
+			AddParam(awscb.SecretID, rotation.secretArn).
+			AddParam(awscb.VersionStage, aws.SecretStagePending).
+			AddParam(awscb.RemoveFromVersionId, versionID).
+			AddParam(awscb.Region, args.region).
+			Build()
+		commands = append(commands, removePendingCommand)
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Run the following commands to rotate the key of OIDC Config '%s':",
+				rotation.oidcConfigId)
+		}
+		fmt.Println(awscb.JoinCommands(commands))
+	}
+	if inUse(r, rotation) {
+		r.Reporter.Warnf("There are clusters using OIDC Config '%s', they keep signing their tokens with "+
+			"the previous key, which can't be removed while they exist", rotation.oidcConfigId)
+	}
+	if r.Reporter.IsTerminal() {
+		r.Reporter.Infof("Once %s have passed and no cluster created before the rotation uses the OIDC "+
+			"config, remove the previous key running 'rosa rotate oidc-config --oidc-config-id %s --%s'",
+			args.gracePeriod, rotation.oidcConfigId, removePreviousKeyFlag)
+	}
+}
+
+// inUse checks if there are clusters using the OIDC config.
+func inUse(r *rosa.Runtime, rotation *rotation) bool {
+	hasClusterUsingOidcConfig, err := r.OCMClient.HasAClusterUsingOidcEndpointUrl(rotation.issuerUrl)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem checking if any clusters are using OIDC Config '%s': %v",
+			rotation.oidcConfigId, err)
+		os.Exit(1)
+	}
+	return hasClusterUsingOidcConfig
+}
+
+// removePreviousKey removes from the JSON web key set the keys that don't correspond to the current
+// version of the private key secret.
+func removePreviousKey(r *rosa.Runtime, mode string, rotation *rotation) {
+	keyID, err := oidc_config.KeyIDFromPrivateKey([]byte(rotation.secret.Value))
+	if err != nil {
+		r.Reporter.Errorf("There was a problem reading private key secret '%s': %v", rotation.secretArn, err)
+		os.Exit(1)
+	}
+	jwks, removed, err := oidc_config.RemoveKeysFromJSONWebKeySet(rotation.jwks, keyID)
+	if err != nil {
+		r.Reporter.Errorf("The current private key of OIDC Config '%s' isn't published: %v",
+			rotation.oidcConfigId, err)
+		os.Exit(1)
+	}
+	if len(removed) == 0 {
+		r.Reporter.Infof("The JSON web key set of OIDC Config '%s' doesn't contain previous keys",
+			rotation.oidcConfigId)
+		return
+	}
+	elapsed := time.Since(rotation.secret.CreatedDate)
+	if elapsed < args.gracePeriod {
+		r.Reporter.Errorf("The key of OIDC Config '%s' was rotated %s ago, the previous key can be "+
+			"removed once %s have passed. Use '--%s' to change the grace period.",
+			rotation.oidcConfigId, elapsed.Round(time.Minute), args.gracePeriod, gracePeriodFlag)
+		os.Exit(1)
+	}
+	// The clusters keep signing their tokens with the key that was current when they were created,
+	// so removing it would prevent their operators from assuming their roles:
+	if inUse(r, rotation) {
+		r.Reporter.Errorf("There are clusters using OIDC Config '%s', which may still sign their tokens "+
+			"with key '%s'. It can only be removed once those clusters are deleted.",
+			rotation.oidcConfigId, strings.Join(removed, "', '"))
+		os.Exit(1)
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		if !confirm.Confirm("remove key '%s' from the JSON web key set of OIDC Config '%s'",
+			strings.Join(removed, "', '"), rotation.oidcConfigId) {
+			os.Exit(0)
+		}
+		err = r.AWSClient.PutPublicReadObjectInS3Bucket(rotation.bucketName, bytes.NewReader(jwks),
+			oidc_config.JwksKey)
+		if err != nil {
+			r.Reporter.Errorf("There was a problem publishing the JSON web key set: %v", err)
+			r.OCMClient.LogEvent("ROSARotateOIDCConfigRemoveKeyModeAuto", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			os.Exit(1)
+		}
+		r.OCMClient.LogEvent("ROSARotateOIDCConfigRemoveKeyModeAuto", map[string]string{
+			ocm.Response: ocm.Success,
+		})
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Removed key '%s' from the JSON web key set of OIDC Config '%s'",
+				strings.Join(removed, "', '"), rotation.oidcConfigId)
+		}
+	case aws.ModeManual:
+		r.OCMClient.LogEvent("ROSARotateOIDCConfigRemoveKeyModeManual", map[string]string{})
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Run the following commands to remove key '%s' from the JSON web key set "+
+				"of OIDC Config '%s':", strings.Join(removed, "', '"), rotation.oidcConfigId)
+		}
+		fmt.Println(awscb.JoinCommands(putJwksCommands(r, rotation, jwks)))
+	}
+}
+
+// publishKey stores the new private key as a pending version of the secret, publishes the JSON web
+// key set containing its public key and only then makes it the current version.
+func publishKey(r *rosa.Runtime, rotation *rotation, versionID string, privateKey []byte, jwks []byte) error {
+	err := r.AWSClient.PutPendingSecretVersion(rotation.secretArn, versionID, string(privateKey))
+	if err != nil {
+		return fmt.Errorf("There was a problem storing the new private key in secrets manager: %v", err)
+	}
+	err = r.AWSClient.PutPublicReadObjectInS3Bucket(rotation.bucketName, bytes.NewReader(jwks),
+		oidc_config.JwksKey)
+	if err != nil {
+		return fmt.Errorf("There was a problem publishing the JSON web key set: %v", err)
+	}
+	err = r.AWSClient.PromoteSecretVersion(rotation.secretArn, versionID, rotation.secret.ID)
+	if err != nil {
+		return fmt.Errorf("There was a problem promoting the new private key version: %v", err)
+	}
+	return nil
+}
+
+func putJwksCommands(r *rosa.Runtime, rotation *rotation, jwks []byte) []string {
+	jwksFilename := fmt.Sprintf("jwks-%s.json", rotation.bucketName)
+	err := helper.SaveDocument(string(jwks), jwksFilename)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem saving JSON Web Key Set to a file: %s", err)
+		os.Exit(1)
+	}
+	putJwksCommand := awscb.NewS3ApiCommandBuilder().
+		SetCommand(awscb.PutObject).
+		AddParam(awscb.Body, fmt.Sprintf("./%s", jwksFilename)).
+		AddParam(awscb.Bucket, rotation.bucketName).
+		AddParam(awscb.Key, oidc_config.JwksKey).
+		AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
+		Build()
+	return []string{putJwksCommand, fmt.Sprintf("rm %s", jwksFilename)}
+}
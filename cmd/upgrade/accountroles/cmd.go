@@ -265,8 +265,12 @@ func run(cmd *cobra.Command, argv []string) error {
 			reporter.Infof("Run the following commands to upgrade the account role policies:\n")
 		}
 
-		commands := buildCommands(prefix, creator.AccountID, isUpgradeNeedForAccountRolePolicies,
+		commands, err := buildCommands(prefix, creator.AccountID, isUpgradeNeedForAccountRolePolicies,
 			awsClient, policyVersion, policyPath)
+		if err != nil {
+			reporter.Errorf("Failed to build the commands to upgrade the account role policies: %v", err)
+			os.Exit(1)
+		}
 		fmt.Println(commands)
 
 	default:
@@ -336,7 +340,7 @@ func upgradeAccountRolePolicies(reporter *rprtr.Object, awsClient aws.Client, pr
 }
 
 func buildCommands(prefix string, accountID string, isUpgradeNeedForAccountRolePolicies bool,
-	awsClient aws.Client, defaultPolicyVersion string, policyPath string) string {
+	awsClient aws.Client, defaultPolicyVersion string, policyPath string) (string, error) {
 	commands := []string{}
 	if isUpgradeNeedForAccountRolePolicies {
 		for file, role := range aws.AccountRoles {
@@ -344,26 +348,35 @@ func buildCommands(prefix string, accountID string, isUpgradeNeedForAccountRoleP
 			policyARN := aws.GetPolicyARN(accountID, accRoleName, policyPath)
 			_, err := awsClient.IsPolicyExists(policyARN)
 			hasPolicy := err == nil
+			var prunedVersions []string
+			if hasPolicy {
+				prunedVersions, err = aws.PolicyVersionsToPrune(awsClient, policyARN)
+				if err != nil {
+					return "", err
+				}
+			}
 			policyName := aws.GetPolicyName(accRoleName)
 			_, err = awsClient.IsRolePolicyExists(accRoleName, policyName)
 			hasInlinePolicy := err == nil
 			upgradeAccountPolicyCommands := awscbRoles.ManualCommandsForUpgradeAccountRolePolicy(
 				awscbRoles.ManualCommandsForUpgradeAccountRolePolicyInput{
-					DefaultPolicyVersion: defaultPolicyVersion,
-					RoleName:             accRoleName,
-					HasPolicy:            hasPolicy,
-					Prefix:               prefix,
-					File:                 file,
-					PolicyName:           policyName,
-					AccountPolicyPath:    policyPath,
-					PolicyARN:            policyARN,
-					HasInlinePolicy:      hasInlinePolicy,
+					DefaultPolicyVersion:  defaultPolicyVersion,
+					RoleName:              accRoleName,
+					HasPolicy:             hasPolicy,
+					Prefix:                prefix,
+					File:                  file,
+					PolicyName:            policyName,
+					AccountPolicyPath:     policyPath,
+					PolicyARN:             policyARN,
+					HasInlinePolicy:       hasInlinePolicy,
+					PrunedPolicyVersions:  prunedVersions,
+					PolicyVersionTagValue: aws.PolicyVersionTagValue(defaultPolicyVersion),
 				},
 			)
 			commands = append(commands, upgradeAccountPolicyCommands...)
 		}
 	}
-	return awscb.JoinCommands(commands)
+	return awscb.JoinCommands(commands), nil
 }
 
 func getAccountPolicyPath(awsClient aws.Client, prefix string) (string, error) {
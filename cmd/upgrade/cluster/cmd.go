@@ -31,6 +31,7 @@ import (
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -194,7 +195,8 @@ func run(cmd *cobra.Command, _ []string) {
 		err = createUpgradePolicyClassic(r, cmd, clusterKey, cluster, version, scheduleDate, scheduleTime)
 	}
 	if err != nil {
-		r.Reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
+		r.Reporter.WithCode(reporter.CodeScheduleUpgrade).Errorf(
+			"Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
 		os.Exit(1)
 	}
 
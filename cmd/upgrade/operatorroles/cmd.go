@@ -281,8 +281,11 @@ func upgradeOperatorPolicies(mode string, r *rosa.Runtime,
 					"Account Role policies upgrade has completed.\n")
 			}
 		}
-		commands := aws.BuildOperatorRoleCommands(prefix, r.Creator.AccountID, r.AWSClient,
+		commands, err := aws.BuildOperatorRoleCommands(prefix, r.Creator.AccountID, r.AWSClient,
 			defaultPolicyVersion, credRequests, policyPath)
+		if err != nil {
+			return r.Reporter.Errorf("Failed to build the commands to upgrade the operator policies: %v", err)
+		}
 		fmt.Println(awscb.JoinCommands(commands))
 	default:
 		return r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
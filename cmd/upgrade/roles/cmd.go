@@ -664,6 +664,13 @@ func buildAccountRoleCommandsFromCluster(
 			}
 			_, err = awsClient.IsPolicyExists(policyARN)
 			hasPolicy := err == nil
+			var prunedVersions []string
+			if hasPolicy {
+				prunedVersions, err = aws.PolicyVersionsToPrune(awsClient, policyARN)
+				if err != nil {
+					return "", err
+				}
+			}
 			policyName := aws.GetPolicyName(accRoleName)
 			_, err = awsClient.IsRolePolicyExists(accRoleName, policyName)
 			hasInlinePolicy := err == nil
@@ -683,6 +690,8 @@ func buildAccountRoleCommandsFromCluster(
 					PolicyARN:                                policyARN,
 					HasInlinePolicy:                          hasInlinePolicy,
 					HasDetachPolicyCommandsForExpectedPolicy: hasDetachPolicyCommandsForExpectedPolicy,
+					PrunedPolicyVersions:                     prunedVersions,
+					PolicyVersionTagValue:                    aws.PolicyVersionTagValue(defaultPolicyVersion),
 				},
 			)
 			commands = append(commands, upgradeAccountPolicyCommands...)
@@ -908,6 +917,13 @@ func buildOperatorRoleCommandsFromCluster(
 		)
 		_, err = awsClient.IsPolicyExists(policyARN)
 		hasPolicy := err == nil
+		var prunedVersions []string
+		if hasPolicy {
+			prunedVersions, err = aws.PolicyVersionsToPrune(awsClient, policyARN)
+			if err != nil {
+				return "", err
+			}
+		}
 
 		upgradePoliciesCommands := awscbRoles.ManualCommandsForUpgradeOperatorRolePolicy(
 			awscbRoles.ManualCommandsForUpgradeOperatorRolePolicyInput{
@@ -921,6 +937,8 @@ func buildOperatorRoleCommandsFromCluster(
 				PolicyName:                               policyName,
 				HasDetachPolicyCommandsForExpectedPolicy: hasDetachPolicyCommandsForExpectedPolicy,
 				OperatorRoleName:                         operatorRoleName,
+				PrunedPolicyVersions:                     prunedVersions,
+				PolicyVersionTagValue:                    aws.PolicyVersionTagValue(defaultPolicyVersion),
 			},
 		)
 		commands = append(commands, upgradePoliciesCommands...)
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	prefix   string
+	hostedCP bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "Verify account roles policies haven't been modified",
+	Long: "Verify that the trust and permission policies of the account roles match the policies " +
+		"expected by ROSA, reporting the actions, conditions and principals that have been modified " +
+		"outside of rosa, for example in the AWS console.",
+	Example: `  # Verify the account roles with the default prefix
+  rosa verify account-roles
+
+  # Verify the hosted control plane account roles with prefix 'myprefix'
+  rosa verify account-roles --prefix myprefix --hosted-cp`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+
+	flags.StringVarP(
+		&args.prefix,
+		"prefix",
+		"p",
+		aws.DefaultPrefix,
+		"User-defined prefix of the account roles",
+	)
+
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"Verify the account roles of hosted control planes (HyperShift)",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	env, err := ocm.GetEnv()
+	if err != nil {
+		r.Reporter.Errorf("Failed to determine OCM environment: %v", err)
+		os.Exit(1)
+	}
+
+	policies, err := r.OCMClient.GetPolicies("AccountRole")
+	if err != nil {
+		r.Reporter.Errorf("Failed to fetch the account role policies: %v", err)
+		os.Exit(1)
+	}
+
+	accountRoles := aws.AccountRoles
+	if args.hostedCP {
+		accountRoles = aws.HCPAccountRoles
+	}
+	files := make([]string, 0, len(accountRoles))
+	for file := range accountRoles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	drift := false
+	for _, file := range files {
+		roleName := aws.GetRoleName(args.prefix, accountRoles[file].Name)
+		verification, err := roles.VerifyAccountRole(r, env, file, roleName, policies)
+		if err != nil {
+			r.OCMClient.LogEvent("ROSAVerifyAccountRoles", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			r.Reporter.Errorf("Failed to verify role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		drift = drift || verification.Drift
+	}
+
+	if drift {
+		r.OCMClient.LogEvent("ROSAVerifyAccountRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("The policies of the account roles with prefix '%s' have been modified. "+
+			"Revert the changes, or run 'rosa create account-roles --prefix %s --force-policy-creation' "+
+			"to create new versions of the permission policies", args.prefix, args.prefix)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSAVerifyAccountRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+	r.Reporter.Infof("The policies of the account roles with prefix '%s' are as expected", args.prefix)
+}
@@ -19,7 +19,10 @@ package verify
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/cmd/verify/accountroles"
 	"github.com/openshift/rosa/cmd/verify/oc"
+	"github.com/openshift/rosa/cmd/verify/oidcconfig"
+	"github.com/openshift/rosa/cmd/verify/operatorroles"
 	"github.com/openshift/rosa/cmd/verify/permissions"
 	"github.com/openshift/rosa/cmd/verify/quota"
 	"github.com/openshift/rosa/cmd/verify/rosa"
@@ -32,7 +35,10 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(oc.Cmd)
+	Cmd.AddCommand(oidcconfig.Cmd)
+	Cmd.AddCommand(operatorroles.Cmd)
 	Cmd.AddCommand(permissions.Cmd)
 	Cmd.AddCommand(quota.Cmd)
 	Cmd.AddCommand(rosa.Cmd)
@@ -0,0 +1,299 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	oidcConfigId string
+	region       string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-config",
+	Aliases: []string{"oidcconfig"},
+	Short:   "Verify an OIDC config is reachable and configured correctly",
+	Long: "Verify that the discovery document and the JSON web key set of an OIDC config can be " +
+		"downloaded from the issuer URL and contain the expected values, that the IAM OIDC provider " +
+		"trusts the issuer with the expected thumbprint and client IDs and, for unmanaged OIDC " +
+		"configs, that the private key secret matches the published keys and that the bucket allows " +
+		"public read access to the documents.",
+	Example: `  # Verify the OIDC config with ID <oidc_config_id>
+  rosa verify oidc-config --oidc-config-id <oidc_config_id>`,
+	Run: run,
+}
+
+const (
+	OidcConfigIdFlag = "oidc-config-id"
+)
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.oidcConfigId,
+		OidcConfigIdFlag,
+		"",
+		"Registered ID for identification of OIDC config",
+	)
+
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+	interactive.AddFlag(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	region, err := aws.GetRegion(arguments.GetRegion())
+	if err != nil {
+		r.Reporter.Errorf("Error getting region: %v", err)
+		os.Exit(1)
+	}
+	args.region = region
+
+	if args.oidcConfigId == "" || interactive.Enabled() {
+		args.oidcConfigId = interactive.GetOidcConfigID(r, cmd)
+	}
+
+	oidcConfig, err := r.OCMClient.GetOidcConfig(args.oidcConfigId)
+	if err != nil {
+		r.Reporter.Errorf("There was a problem retrieving the OIDC Config '%s': %v", args.oidcConfigId, err)
+		os.Exit(1)
+	}
+	issuerUrl := oidcConfig.IssuerUrl()
+
+	bucketName := ""
+	if !oidcConfig.Managed() {
+		parsedSecretArn, err := arn.Parse(oidcConfig.SecretArn())
+		if err != nil {
+			r.Reporter.Errorf("There was a problem parsing secret ARN '%s' : %v", oidcConfig.SecretArn(), err)
+			os.Exit(1)
+		}
+		if args.region != parsedSecretArn.Region {
+			r.Reporter.Errorf("Secret region '%s' differs from chosen region '%s', "+
+				"please run the command supplying region parameter.", parsedSecretArn.Region, args.region)
+			os.Exit(1)
+		}
+		bucketName, err = oidc_config.BucketNameFromSecretArn(oidcConfig.SecretArn())
+		if err != nil {
+			r.Reporter.Errorf("There was a problem parsing secret ARN '%s' : %v", oidcConfig.SecretArn(), err)
+			os.Exit(1)
+		}
+	}
+
+	healthy := true
+	problems, err := verifyDiscoveryDocument(issuerUrl)
+	healthy = report(r, "discovery document", problems, err) && healthy
+
+	keySet, problems, err := verifyJSONWebKeySet(issuerUrl)
+	healthy = report(r, "JSON web key set", problems, err) && healthy
+
+	if !oidcConfig.Managed() {
+		problems, err = verifyPrivateKey(r, oidcConfig.SecretArn(), keySet)
+		healthy = report(r, "private key secret", problems, err) && healthy
+	}
+
+	problems, err = verifyOIDCProvider(r, issuerUrl)
+	healthy = report(r, "OIDC provider", problems, err) && healthy
+
+	if !oidcConfig.Managed() {
+		problems, err = verifyPublicAccessBlock(r, bucketName)
+		healthy = report(r, fmt.Sprintf("public access block of bucket '%s'", bucketName), problems, err) &&
+			healthy
+		healthy = verifyBucketPolicy(r, bucketName) && healthy
+	}
+
+	if !healthy {
+		r.OCMClient.LogEvent("ROSAVerifyOIDCConfig", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("OIDC Config '%s' isn't configured correctly, clusters using it may fail to "+
+			"authenticate their operators", args.oidcConfigId)
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSAVerifyOIDCConfig", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+	r.Reporter.Infof("OIDC Config '%s' is configured correctly", args.oidcConfigId)
+}
+
+// report prints the problems found verifying a part of the OIDC config, and returns false if
+// there is any.
+func report(r *rosa.Runtime, description string, problems []string, err error) bool {
+	if err != nil {
+		r.Reporter.Warnf("Failed to verify the %s: %v", description, err)
+		return false
+	}
+	if len(problems) > 0 {
+		r.Reporter.Warnf("The %s isn't configured correctly:\n  - %s", description,
+			strings.Join(problems, "\n  - "))
+		return false
+	}
+	r.Reporter.Infof("The %s is configured correctly", description)
+	return true
+}
+
+func verifyDiscoveryDocument(issuerUrl string) ([]string, error) {
+	document, err := oidc_config.FetchDocument(issuerUrl, oidc_config.DiscoveryDocumentKey)
+	if err != nil {
+		return nil, fmt.Errorf("%v, check that the bucket policy allows public read access", err)
+	}
+	return oidc_config.ValidateDiscoveryDocument(document, issuerUrl)
+}
+
+func verifyJSONWebKeySet(issuerUrl string) (*oidc_config.JSONWebKeySet, []string, error) {
+	jwks, err := oidc_config.FetchDocument(issuerUrl, oidc_config.JwksKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v, check that the bucket policy allows public read access", err)
+	}
+	problems, err := oidc_config.ValidateJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, nil, err
+	}
+	keySet, err := oidc_config.ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keySet, problems, nil
+}
+
+// verifyPrivateKey checks that the JSON web key set contains the public key of the private key
+// that is used to sign the tokens.
+func verifyPrivateKey(r *rosa.Runtime, secretArn string, keySet *oidc_config.JSONWebKeySet) ([]string, error) {
+	secret, err := r.AWSClient.GetCurrentSecretVersion(secretArn)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := oidc_config.KeyIDFromPrivateKey([]byte(secret.Value))
+	if err != nil {
+		return nil, err
+	}
+	if keySet == nil {
+		return nil, fmt.Errorf("the JSON web key set isn't available")
+	}
+	for _, key := range keySet.Keys {
+		if key.KeyID == keyID {
+			return nil, nil
+		}
+	}
+	return []string{fmt.Sprintf("the JSON web key set doesn't contain its public key '%s'", keyID)}, nil
+}
+
+func verifyOIDCProvider(r *rosa.Runtime, issuerUrl string) ([]string, error) {
+	provider, err := r.AWSClient.GetOpenIDConnectProvider(issuerUrl, r.Creator.AccountID)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			return []string{fmt.Sprintf("%v, run 'rosa create oidc-provider --oidc-config-id %s' to create it",
+				err, args.oidcConfigId)}, nil
+		}
+		return nil, err
+	}
+
+	problems := []string{}
+	expectedClientIDs := []string{aws.OIDCClientIDOpenShift, aws.OIDCClientIDSTSAWS}
+	clientIDs := append([]string{}, provider.ClientIDs...)
+	sort.Strings(expectedClientIDs)
+	sort.Strings(clientIDs)
+	if strings.Join(clientIDs, ",") != strings.Join(expectedClientIDs, ",") {
+		problems = append(problems, fmt.Sprintf("client IDs are '%s' instead of '%s'",
+			strings.Join(clientIDs, "', '"), strings.Join(expectedClientIDs, "', '")))
+	}
+
+	thumbprint, err := oidc_config.GetThumbprint(issuerUrl)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, value := range provider.Thumbprints {
+		if strings.EqualFold(value, thumbprint) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		problems = append(problems, fmt.Sprintf("thumbprints '%s' don't contain the thumbprint '%s' "+
+			"of the issuer certificate", strings.Join(provider.Thumbprints, "', '"), thumbprint))
+	}
+	return problems, nil
+}
+
+func verifyPublicAccessBlock(r *rosa.Runtime, bucketName string) ([]string, error) {
+	expected := aws.PublicReadAccessBlockConfiguration()
+	block, err := r.AWSClient.GetS3BucketPublicAccessBlock(bucketName)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			return []string{"the bucket doesn't have a public access block"}, nil
+		}
+		return nil, err
+	}
+
+	problems := []string{}
+	settings := []struct {
+		name     string
+		expected *bool
+		actual   *bool
+	}{
+		{"BlockPublicAcls", expected.BlockPublicAcls, block.BlockPublicAcls},
+		{"IgnorePublicAcls", expected.IgnorePublicAcls, block.IgnorePublicAcls},
+		{"BlockPublicPolicy", expected.BlockPublicPolicy, block.BlockPublicPolicy},
+		{"RestrictPublicBuckets", expected.RestrictPublicBuckets, block.RestrictPublicBuckets},
+	}
+	for _, setting := range settings {
+		if awssdk.BoolValue(setting.actual) != awssdk.BoolValue(setting.expected) {
+			problems = append(problems, fmt.Sprintf("'%s' is '%t' instead of '%t'", setting.name,
+				awssdk.BoolValue(setting.actual), awssdk.BoolValue(setting.expected)))
+		}
+	}
+	return problems, nil
+}
+
+func verifyBucketPolicy(r *rosa.Runtime, bucketName string) bool {
+	description := fmt.Sprintf("policy of bucket '%s'", bucketName)
+	policy, err := r.AWSClient.GetS3BucketPolicy(bucketName)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			return report(r, description, []string{"the bucket doesn't have a policy"}, nil)
+		}
+		return report(r, description, nil, err)
+	}
+	drift, err := roles.ReportPolicyDrift(r, description,
+		fmt.Sprintf(aws.ReadOnlyAnonUserPolicyTemplate, bucketName), policy)
+	if err != nil {
+		return report(r, description, nil, err)
+	}
+	return !drift
+}
@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper/roles"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+const (
+	PrefixFlag       = "prefix"
+	OidcConfigIdFlag = "oidc-config-id"
+	HostedCpFlag     = "hosted-cp"
+)
+
+var args struct {
+	prefix       string
+	oidcConfigId string
+	hostedCp     bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "Verify operator roles policies haven't been modified",
+	Long: "Verify that the trust and permission policies of the operator roles match the policies " +
+		"expected by ROSA, reporting the actions, conditions and principals that have been modified " +
+		"outside of rosa, for example in the AWS console. When the OIDC endpoint is known, also verify " +
+		"that the trust policies allow the service accounts of the operators to assume the roles, and " +
+		"print the commands that fix them.",
+	Example: `  # Verify the operator roles of a cluster
+  rosa verify operator-roles --cluster=mycluster
+
+  # Verify the operator roles with prefix 'myprefix' that trust an OIDC configuration
+  rosa verify operator-roles --prefix=myprefix --oidc-config-id=<oidc-config-id>`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+
+	ocm.AddOptionalClusterFlag(Cmd)
+
+	flags.StringVar(
+		&args.prefix,
+		PrefixFlag,
+		"",
+		"User-defined prefix of the operator roles. Not to be used alongside --cluster flag.",
+	)
+
+	flags.StringVar(
+		&args.oidcConfigId,
+		OidcConfigIdFlag,
+		"",
+		"Registered OIDC configuration ID that the operator roles should trust. Without it the trust "+
+			"policies aren't verified. Not to be used alongside --cluster flag.",
+	)
+
+	flags.BoolVar(
+		&args.hostedCp,
+		HostedCpFlag,
+		false,
+		"Indicates whether to verify the hosted control planes operator roles when using --prefix option.",
+	)
+}
+
+// operatorRole is an operator role to verify and the credentials request it was created for.
+type operatorRole struct {
+	name     string
+	operator *cmv1.STSOperator
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterFlag := cmd.Flags().Changed("cluster")
+	if clusterFlag == (args.prefix != "") {
+		r.Reporter.Errorf("Either a cluster key or an operator roles prefix is required")
+		os.Exit(1)
+	}
+	if clusterFlag && (args.oidcConfigId != "" || cmd.Flags().Changed(HostedCpFlag)) {
+		r.Reporter.Errorf("Flags '%s' and '%s' can't be used alongside the '--cluster' flag",
+			OidcConfigIdFlag, HostedCpFlag)
+		os.Exit(1)
+	}
+
+	hostedCP := args.hostedCp
+	oidcEndpointURL := ""
+	var cluster *cmv1.Cluster
+	if clusterFlag {
+		r.GetClusterKey()
+		cluster = r.FetchCluster()
+		if cluster.AWS().STS().RoleARN() == "" {
+			r.Reporter.Errorf("Cluster '%s' is not an STS cluster", r.ClusterKey)
+			os.Exit(1)
+		}
+		hostedCP = cluster.Hypershift().Enabled()
+		oidcEndpointURL = cluster.AWS().STS().OIDCEndpointURL()
+	} else if args.oidcConfigId != "" {
+		oidcConfig, err := r.OCMClient.GetOidcConfig(args.oidcConfigId)
+		if err != nil {
+			r.Reporter.Errorf("There was a problem retrieving OIDC Config '%s': %v", args.oidcConfigId, err)
+			os.Exit(1)
+		}
+		oidcEndpointURL = oidcConfig.IssuerUrl()
+	}
+
+	credRequests, err := r.OCMClient.GetCredRequests(hostedCP)
+	if err != nil {
+		r.Reporter.Errorf("Error getting operator credential request from OCM %v", err)
+		os.Exit(1)
+	}
+	policies, err := r.OCMClient.GetPolicies("OperatorRole")
+	if err != nil {
+		r.Reporter.Errorf("Failed to fetch the operator role policies: %v", err)
+		os.Exit(1)
+	}
+
+	keys := make([]string, 0, len(credRequests))
+	for key := range credRequests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	drift := false
+	operatorRoles := map[string]operatorRole{}
+	for _, key := range keys {
+		operator := credRequests[key]
+		var roleName string
+		if cluster != nil {
+			name, found := aws.FindOperatorRoleNameBySTSOperator(cluster, operator)
+			if !found {
+				continue
+			}
+			roleName = name
+		} else {
+			roleName, err = aws.GetResourceIdFromARN(aws.ComputeOperatorRoleArn(args.prefix, operator,
+				r.Creator, ""))
+			if err != nil {
+				r.Reporter.Errorf("%v", err)
+				os.Exit(1)
+			}
+		}
+		operatorRoles[key] = operatorRole{name: roleName, operator: operator}
+	}
+	if oidcEndpointURL == "" {
+		r.Reporter.Infof("The trust policies won't be verified, use the '--%s' flag to verify them",
+			OidcConfigIdFlag)
+	} else {
+		oidcProviderExists, err := r.AWSClient.HasOpenIDConnectProvider(oidcEndpointURL, r.Creator.AccountID)
+		if err != nil {
+			r.Reporter.Errorf("Failed to verify if OIDC provider exists: %v", err)
+			os.Exit(1)
+		}
+		if !oidcProviderExists {
+			command := fmt.Sprintf("rosa create oidc-provider --oidc-config-id %s", args.oidcConfigId)
+			if cluster != nil {
+				command = fmt.Sprintf("rosa create oidc-provider --cluster %s", r.ClusterKey)
+			}
+			r.Reporter.Warnf("There is no OIDC provider for '%s', so the operator roles can't be assumed. "+
+				"To fix it, run the following command:\n\n%s\n", oidcEndpointURL, command)
+			drift = true
+		}
+	}
+
+	for _, key := range keys {
+		role, ok := operatorRoles[key]
+		if !ok {
+			continue
+		}
+		verification, err := roles.VerifyOperatorRole(r, key, role.name, role.operator, oidcEndpointURL, policies)
+		if err != nil {
+			r.OCMClient.LogEvent("ROSAVerifyOperatorRoles", map[string]string{
+				ocm.Response: ocm.Failure,
+			})
+			r.Reporter.Errorf("Failed to verify role '%s': %v", role.name, err)
+			os.Exit(1)
+		}
+		drift = drift || verification.Drift
+	}
+
+	if drift {
+		r.OCMClient.LogEvent("ROSAVerifyOperatorRoles", map[string]string{
+			ocm.Response: ocm.Failure,
+		})
+		r.Reporter.Errorf("The operator roles aren't configured as expected. Run the commands above, " +
+			"revert the changes, or run 'rosa create operator-roles' with the '--force-policy-creation' " +
+			"flag to create new versions of the permission policies")
+		os.Exit(1)
+	}
+	r.OCMClient.LogEvent("ROSAVerifyOperatorRoles", map[string]string{
+		ocm.Response: ocm.Success,
+	})
+	r.Reporter.Infof("The policies of the operator roles are as expected")
+}
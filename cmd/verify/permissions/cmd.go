@@ -28,16 +28,29 @@ import (
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
+var args struct {
+	roleArn string
+	prefix  string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "permissions",
 	Aliases: []string{"scp"},
-	Short:   "Verify AWS permissions are ok for non-STS cluster install",
-	Long:    "Verify AWS permissions needed to create a non-STS cluster are configured as expected",
+	Short:   "Verify AWS permissions are ok for cluster install",
+	Long: "Verify AWS permissions needed to create a non-STS cluster are configured as expected. " +
+		"When account roles are given, verify that organization SCPs and permissions boundaries " +
+		"don't deny the permissions that STS clusters need.",
 	Example: `  # Verify AWS permissions are configured correctly
   rosa verify permissions
 
   # Verify AWS permissions in a different region
-  rosa verify permissions --region=us-west-2`,
+  rosa verify permissions --region=us-west-2
+
+  # Verify the permissions of the account roles with prefix 'myprefix'
+  rosa verify permissions --prefix=myprefix
+
+  # Verify the permissions of a single account role
+  rosa verify permissions --role-arn=arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role`,
 	Run: run,
 }
 
@@ -46,6 +59,20 @@ func init() {
 
 	arguments.AddProfileFlag(flags)
 	arguments.AddRegionFlag(flags)
+
+	flags.StringVar(
+		&args.roleArn,
+		"role-arn",
+		"",
+		"ARN of the account role to verify the permissions of, for STS clusters.",
+	)
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		"",
+		"User-defined prefix of the account roles to verify the permissions of, for STS clusters.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -73,6 +100,15 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if args.roleArn != "" && args.prefix != "" {
+		r.Reporter.Errorf("Flags '--role-arn' and '--prefix' can't be used together")
+		os.Exit(1)
+	}
+	if args.roleArn != "" || args.prefix != "" {
+		verifySTSPermissions(r)
+		return
+	}
+
 	r.Reporter.Infof("Verifying permissions for non-STS clusters")
 	r.Reporter.Infof("Validating SCP policies...")
 	policies, err := r.OCMClient.GetPolicies("OSDSCPPolicy")
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissions
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// verifySTSPermissions simulates, for each account role, the actions of the policy that OCM
+// defines for it, and reports the actions denied by organization SCPs or permissions boundaries.
+func verifySTSPermissions(r *rosa.Runtime) {
+	r.Reporter.Infof("Verifying permissions for STS clusters")
+	policies, err := r.OCMClient.GetPolicies("AccountRole")
+	if err != nil {
+		r.Reporter.Errorf("Failed to fetch the account role policies: %v", err)
+		os.Exit(1)
+	}
+
+	roleARNs := map[string]string{}
+	if args.prefix != "" {
+		for roleType, role := range aws.AccountRoles {
+			roleARN, err := r.AWSClient.GetAccountRoleARN(args.prefix, role.Name)
+			if err != nil {
+				if errors.GetType(err) == errors.NotFound {
+					r.Reporter.Warnf("Role '%s' not found", aws.GetRoleName(args.prefix, role.Name))
+					continue
+				}
+				r.Reporter.Errorf("Failed to get role '%s': %v", aws.GetRoleName(args.prefix, role.Name), err)
+				os.Exit(1)
+			}
+			roleARNs[roleType] = roleARN
+		}
+		if len(roleARNs) == 0 {
+			r.Reporter.Errorf("There are no account roles with prefix '%s'", args.prefix)
+			os.Exit(1)
+		}
+	} else {
+		role, err := r.AWSClient.GetRoleByARN(args.roleArn)
+		if err != nil {
+			r.Reporter.Errorf("Failed to get role '%s': %v", args.roleArn, err)
+			os.Exit(1)
+		}
+		roleType := ""
+		for _, tag := range role.Tags {
+			if awssdk.StringValue(tag.Key) == tags.RoleType {
+				roleType = awssdk.StringValue(tag.Value)
+			}
+		}
+		if _, ok := aws.AccountRoles[roleType]; !ok {
+			r.Reporter.Errorf("Role '%s' isn't an account role: expected the '%s' tag to be one of "+
+				"'installer', 'instance_controlplane', 'instance_worker' or 'support'", args.roleArn, tags.RoleType)
+			os.Exit(1)
+		}
+		roleARNs[roleType] = args.roleArn
+	}
+
+	roleTypes := make([]string, 0, len(roleARNs))
+	for roleType := range roleARNs {
+		roleTypes = append(roleTypes, roleType)
+	}
+	sort.Strings(roleTypes)
+
+	denied := false
+	for _, roleType := range roleTypes {
+		roleARN := roleARNs[roleType]
+		policyDetails := aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_permission_policy", roleType))
+		if policyDetails == "" {
+			r.Reporter.Warnf("There is no permission policy for role '%s', skipping", roleARN)
+			continue
+		}
+		r.Reporter.Infof("Simulating the permissions of role '%s'...", roleARN)
+		deniedActions, err := r.AWSClient.SimulateRolePolicy(roleARN,
+			aws.InterpolatePolicyDocument(policyDetails, map[string]string{
+				"partition": aws.GetPartition(),
+			}))
+		if err != nil {
+			r.OCMClient.LogEvent("ROSAVerifyPermissionsSTSFailed", nil)
+			r.Reporter.Errorf("Unable to simulate the permissions of role '%s'. Make sure that an "+
+				"organizational SCP is not preventing this account from performing the required checks", roleARN)
+			if strings.Contains(err.Error(), "Throttling: Rate exceeded") {
+				r.Reporter.Errorf("Throttling: Rate exceeded. Please wait 3-5 minutes before retrying.")
+				os.Exit(1)
+			}
+			r.Reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if len(deniedActions) == 0 {
+			r.Reporter.Infof("Role '%s' is allowed to perform the actions of its policy", roleARN)
+			continue
+		}
+		denied = true
+		lines := make([]string, len(deniedActions))
+		for i, deniedAction := range deniedActions {
+			lines[i] = "  - " + deniedAction.String()
+		}
+		r.Reporter.Warnf("Role '%s' isn't allowed to perform %d actions:\n%s", roleARN, len(deniedActions),
+			strings.Join(lines, "\n"))
+	}
+
+	if denied {
+		r.OCMClient.LogEvent("ROSAVerifyPermissionsSTSInvalid", nil)
+		r.Reporter.Errorf("Some actions needed by STS clusters are denied. Update the SCPs and " +
+			"permissions boundaries that deny them and try again")
+		os.Exit(1)
+	}
+	r.Reporter.Infof("AWS permissions of the account roles ok")
+}
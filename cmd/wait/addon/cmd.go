@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/wait"
+)
+
+const (
+	conditionReady   = "ready"
+	conditionDeleted = "deleted"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "addon ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Wait for an add-on to be installed or uninstalled",
+	Long:    "Wait for the installation of an add-on on a cluster to be ready or deleted.",
+	Example: `  # Wait for add-on "dbaas-operator" to be installed on a cluster named "mycluster"
+  rosa wait addon --cluster=mycluster dbaas-operator`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf(
+				"Expected exactly one command line parameter containing the id of the add-on",
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ocm.AddClusterFlag(Cmd)
+	wait.AddFlags(Cmd, []string{conditionReady, conditionDeleted})
+}
+
+func run(_ *cobra.Command, argv []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	addOnID := argv[0]
+	cluster := r.FetchCluster()
+	description := fmt.Sprintf("Add-on '%s'", addOnID)
+
+	err := wait.NewPoller(r.Reporter).Poll(func() (wait.Result, string, error) {
+		installation, err := r.OCMClient.GetAddOnInstallation(cluster.ID(), addOnID)
+		if errors.GetType(err) == errors.NotFound {
+			if wait.Condition() == conditionDeleted {
+				return wait.Met, "deleted", nil
+			}
+			return wait.Failed, "add-on is not installed", nil
+		}
+		if err != nil {
+			return wait.Waiting, "", err
+		}
+		state := installation.State()
+		message := fmt.Sprintf("state is '%s'", state)
+		if description := installation.StateDescription(); description != "" {
+			message = fmt.Sprintf("%s: %s", message, description)
+		}
+		switch {
+		case state == cmv1.AddOnInstallationStateFailed:
+			return wait.Failed, message, nil
+		case state == cmv1.AddOnInstallationStateReady && wait.Condition() == conditionReady:
+			return wait.Met, message, nil
+		}
+		return wait.Waiting, message, nil
+	})
+	wait.Exit(r.Reporter, err, description)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/wait"
+)
+
+const (
+	conditionReady       = "ready"
+	conditionHibernating = "hibernating"
+	conditionDeleted     = "deleted"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Wait for a cluster to reach a state",
+	Long:  "Wait for a cluster to be ready, hibernating or deleted.",
+	Example: `  # Wait up to 90 minutes for the installation of a cluster named "mycluster" to finish
+  rosa wait cluster --cluster=mycluster --timeout=90m
+
+  # Wait for a cluster to be deleted
+  rosa wait cluster --cluster=mycluster --for=deleted`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	ocm.AddClusterFlag(Cmd)
+	wait.AddFlags(Cmd, []string{conditionReady, conditionHibernating, conditionDeleted})
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	description := fmt.Sprintf("Cluster '%s'", clusterKey)
+
+	// The cluster may already be gone when waiting for it to be deleted:
+	cluster, err := r.OCMClient.GetCluster(clusterKey, r.Creator)
+	if err != nil {
+		if wait.Condition() == conditionDeleted && errors.GetType(err) == errors.NotFound {
+			wait.Exit(r.Reporter, nil, description)
+		}
+		r.Reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	err = wait.NewPoller(r.Reporter).Poll(func() (wait.Result, string, error) {
+		state, err := r.OCMClient.GetClusterState(cluster.ID())
+		if err != nil && errors.GetType(err) != errors.NotFound {
+			return wait.Waiting, "", err
+		}
+		if err != nil || state == cmv1.ClusterState("") {
+			if wait.Condition() == conditionDeleted {
+				return wait.Met, "deleted", nil
+			}
+			return wait.Failed, "cluster no longer exists", nil
+		}
+		message := fmt.Sprintf("state is '%s'", state)
+		switch wait.Condition() {
+		case conditionReady:
+			switch state {
+			case cmv1.ClusterStateReady:
+				return wait.Met, message, nil
+			case cmv1.ClusterStateError, cmv1.ClusterStateUninstalling:
+				return wait.Failed, message, nil
+			}
+		case conditionHibernating:
+			switch state {
+			case cmv1.ClusterStateHibernating:
+				return wait.Met, message, nil
+			case cmv1.ClusterStateError, cmv1.ClusterStateUninstalling:
+				return wait.Failed, message, nil
+			}
+		}
+		return wait.Waiting, message, nil
+	})
+	wait.Exit(r.Reporter, err, description)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/cmd/wait/addon"
+	"github.com/openshift/rosa/cmd/wait/cluster"
+	"github.com/openshift/rosa/cmd/wait/machinepool"
+	"github.com/openshift/rosa/cmd/wait/upgrade"
+	"github.com/openshift/rosa/pkg/arguments"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for a condition on a resource",
+	Long: "Wait until a resource meets a condition. The command exits with status 0 when the condition " +
+		"is met, 2 when the timeout expires first and 3 when the resource reaches a state from which " +
+		"the condition can't be met, like a cluster in error state.",
+}
+
+func init() {
+	Cmd.AddCommand(addon.Cmd)
+	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
+
+	flags := Cmd.PersistentFlags()
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+}
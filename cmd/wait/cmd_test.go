@@ -0,0 +1,42 @@
+package wait
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/wait"
+)
+
+var _ = Describe("Wait", func() {
+	DescribeTable("Uses the default condition of each command when '--for' isn't given",
+		func(name string, expected string) {
+			cmd, _, err := Cmd.Find([]string{name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Name()).To(Equal(name))
+			Expect(cmd.ParseFlags([]string{})).To(Succeed())
+
+			Expect(cmd.PreRunE(cmd, []string{})).To(Succeed())
+			Expect(wait.Condition()).To(Equal(expected))
+			Expect(cmd.Flag("for").DefValue).To(Equal(expected))
+			Expect(cmd.Flag("timeout").DefValue).To(Equal(time.Hour.String()))
+		},
+		Entry("addon", "addon", "ready"),
+		Entry("cluster", "cluster", "ready"),
+		Entry("machinepool", "machinepool", "ready"),
+		Entry("upgrade", "upgrade", "completed"),
+	)
+
+	It("Uses the condition given with '--for'", func() {
+		cmd, _, err := Cmd.Find([]string{"cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.ParseFlags([]string{"--for", "deleted"})).To(Succeed())
+		DeferCleanup(func() {
+			Expect(cmd.Flags().Set("for", "ready")).To(Succeed())
+		})
+
+		Expect(cmd.PreRunE(cmd, []string{})).To(Succeed())
+		Expect(wait.Condition()).To(Equal("deleted"))
+	})
+})
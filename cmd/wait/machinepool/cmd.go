@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/wait"
+)
+
+const (
+	conditionReady   = "ready"
+	conditionDeleted = "deleted"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "machinepool ID",
+	Aliases: []string{"machinepools", "machine-pool", "machine-pools"},
+	Short:   "Wait for a machine pool to be ready or deleted",
+	Long: "Wait for a machine pool to be ready or deleted. Machine pools of hosted control plane " +
+		"clusters are ready when all their replicas are available, machine pools of other clusters " +
+		"are ready as soon as they exist.",
+	Example: `  # Wait for the nodes of machine pool "mp-1" of a cluster named "mycluster" to be available
+  rosa wait machinepool --cluster=mycluster mp-1`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf(
+				"Expected exactly one command line parameter containing the id of the machine pool",
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ocm.AddClusterFlag(Cmd)
+	wait.AddFlags(Cmd, []string{conditionReady, conditionDeleted})
+}
+
+func run(_ *cobra.Command, argv []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	machinePoolID := argv[0]
+	cluster := r.FetchCluster()
+	description := fmt.Sprintf("Machine pool '%s'", machinePoolID)
+
+	var check wait.Check
+	if cluster.Hypershift().Enabled() {
+		check = func() (wait.Result, string, error) {
+			return checkNodePool(r, cluster.ID(), machinePoolID)
+		}
+	} else {
+		check = func() (wait.Result, string, error) {
+			return checkMachinePool(r, cluster.ID(), machinePoolID)
+		}
+	}
+	err := wait.NewPoller(r.Reporter).Poll(check)
+	wait.Exit(r.Reporter, err, description)
+}
+
+func checkNodePool(r *rosa.Runtime, clusterID string, nodePoolID string) (wait.Result, string, error) {
+	nodePool, err := r.OCMClient.GetNodePool(clusterID, nodePoolID)
+	if errors.GetType(err) == errors.NotFound {
+		if wait.Condition() == conditionDeleted {
+			return wait.Met, "deleted", nil
+		}
+		return wait.Waiting, "doesn't exist", nil
+	}
+	if err != nil {
+		return wait.Waiting, "", err
+	}
+	if wait.Condition() == conditionDeleted {
+		return wait.Waiting, "exists", nil
+	}
+	desired := nodePool.Replicas()
+	if autoscaling, ok := nodePool.GetAutoscaling(); ok {
+		desired = autoscaling.MinReplica()
+	}
+	current := nodePool.Status().CurrentReplicas()
+	message := fmt.Sprintf("%d of %d replicas available", current, desired)
+	if statusMessage := nodePool.Status().Message(); statusMessage != "" {
+		message = fmt.Sprintf("%s (%s)", message, statusMessage)
+	}
+	if current >= desired {
+		return wait.Met, message, nil
+	}
+	return wait.Waiting, message, nil
+}
+
+func checkMachinePool(r *rosa.Runtime, clusterID string, machinePoolID string) (wait.Result, string, error) {
+	machinePools, err := r.OCMClient.GetMachinePools(clusterID)
+	if err != nil {
+		return wait.Waiting, "", err
+	}
+	var machinePool *cmv1.MachinePool
+	for _, item := range machinePools {
+		if item.ID() == machinePoolID {
+			machinePool = item
+		}
+	}
+	switch {
+	case machinePool == nil && wait.Condition() == conditionDeleted:
+		return wait.Met, "deleted", nil
+	case machinePool == nil:
+		return wait.Waiting, "doesn't exist", nil
+	case wait.Condition() == conditionDeleted:
+		return wait.Waiting, "exists", nil
+	default:
+		return wait.Met, "exists", nil
+	}
+}
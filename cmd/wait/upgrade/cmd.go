@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/wait"
+)
+
+const (
+	conditionCompleted = "completed"
+	conditionStarted   = "started"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "upgrade",
+	Aliases: []string{"upgrades"},
+	Short:   "Wait for a cluster upgrade to start or complete",
+	Long: "Wait for the scheduled upgrade of a cluster to start or complete. The upgrade is considered " +
+		"completed once it is no longer scheduled.",
+	Example: `  # Wait for the scheduled upgrade of a cluster named "mycluster" to complete
+  rosa wait upgrade --cluster=mycluster --timeout=3h`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	ocm.AddClusterFlag(Cmd)
+	wait.AddFlags(Cmd, []string{conditionCompleted, conditionStarted})
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+	description := fmt.Sprintf("Upgrade of cluster '%s'", clusterKey)
+
+	err := wait.NewPoller(r.Reporter).Poll(func() (wait.Result, string, error) {
+		var state *cmv1.UpgradePolicyState
+		var version string
+		if cluster.Hypershift().Enabled() {
+			upgradePolicy, err := r.OCMClient.GetControlPlaneScheduledUpgrade(cluster.ID())
+			if err != nil {
+				return wait.Waiting, "", err
+			}
+			if upgradePolicy != nil {
+				state = upgradePolicy.State()
+				version = upgradePolicy.Version()
+			}
+		} else {
+			upgradePolicy, upgradeState, err := r.OCMClient.GetScheduledUpgrade(cluster.ID())
+			if err != nil {
+				return wait.Waiting, "", err
+			}
+			if upgradePolicy != nil {
+				state = upgradeState
+				version = upgradePolicy.Version()
+			}
+		}
+		return checkUpgrade(state, version)
+	})
+	wait.Exit(r.Reporter, err, description)
+}
+
+func checkUpgrade(state *cmv1.UpgradePolicyState, version string) (wait.Result, string, error) {
+	// Upgrade policies are removed once the upgrade finishes:
+	if state == nil {
+		if wait.Condition() == conditionCompleted {
+			return wait.Met, "no upgrade is scheduled", nil
+		}
+		return wait.Failed, "no upgrade is scheduled", nil
+	}
+	message := fmt.Sprintf("upgrade to version '%s' is '%s'", version, state.Value())
+	if description := state.Description(); description != "" {
+		message = fmt.Sprintf("%s: %s", message, description)
+	}
+	switch state.Value() {
+	case cmv1.UpgradePolicyStateValueCompleted:
+		return wait.Met, message, nil
+	case cmv1.UpgradePolicyStateValueFailed, cmv1.UpgradePolicyStateValueCancelled:
+		return wait.Failed, message, nil
+	case cmv1.UpgradePolicyStateValueStarted:
+		if wait.Condition() == conditionStarted {
+			return wait.Met, message, nil
+		}
+	}
+	return wait.Waiting, message, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws/profile"
 	"github.com/openshift/rosa/pkg/aws/region"
 	"github.com/openshift/rosa/pkg/debug"
+	"github.com/openshift/rosa/pkg/har"
 	"github.com/openshift/rosa/pkg/helper"
 )
 
@@ -201,6 +202,11 @@ func AddDebugFlag(fs *pflag.FlagSet) {
 	debug.AddFlag(fs)
 }
 
+// AddRecordFlag adds the '--record' flag to the given set of command line flags.
+func AddRecordFlag(fs *pflag.FlagSet) {
+	har.AddFlag(fs)
+}
+
 // AddProfileFlag adds the '--profile' flag to the given set of command line flags.
 func AddProfileFlag(fs *pflag.FlagSet) {
 	profile.AddFlag(fs)
@@ -49,7 +49,9 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/rosa/pkg/cassette"
 	"github.com/openshift/rosa/pkg/fedramp"
+	"github.com/openshift/rosa/pkg/har"
 	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/sirupsen/logrus"
 	"github.com/zgalor/weberr"
@@ -73,6 +75,12 @@ const (
 	Attached      = "attached"
 )
 
+// Stages of the versions of the secrets stored in Secrets Manager:
+const (
+	SecretStageCurrent = "AWSCURRENT"
+	SecretStagePending = "AWSPENDING"
+)
+
 // addROSAVersionToUserAgent is a named handler that will add ROSA CLI
 // version information to requests made by the AWS SDK.
 var addROSAVersionToUserAgent = request.NamedHandler{
@@ -91,11 +99,13 @@ type Client interface {
 	GetRegion() string
 	ValidateCredentials() (isValid bool, err error)
 	EnsureOsdCcsAdminUser(stackName string, adminUserName string, awsRegion string) (bool, error)
+	EnsureStack(cfTemplateBody string, stackName string) (bool, error)
 	DeleteOsdCcsAdminUser(stackName string) error
 	GetAWSAccessKeys() (*AccessKey, error)
 	GetLocalAWSAccessKeys() (*AccessKey, error)
 	GetCreator() (*Creator, error)
 	ValidateSCP(*string, map[string]*cmv1.AWSSTSPolicy) (bool, error)
+	SimulateRolePolicy(roleARN string, policyDocument string) ([]DeniedAction, error)
 	GetSubnetIDs() ([]*ec2.Subnet, error)
 	GetSubnetAvailabilityZone(subnetID string) (string, error)
 	GetVPCSubnets(subnetID string) ([]*ec2.Subnet, error)
@@ -116,15 +126,20 @@ type Client interface {
 	CreateOpenIDConnectProvider(issuerURL string, thumbprint string, clusterID string) (string, error)
 	DeleteOpenIDConnectProvider(providerURL string) error
 	HasOpenIDConnectProvider(issuerURL string, accountID string) (bool, error)
+	GetOpenIDConnectProvider(issuerURL string, accountID string) (*OpenIDConnectProvider, error)
 	FindRoleARNs(roleType string, version string) ([]string, error)
 	FindPolicyARN(operator Operator, version string) (string, error)
 	ListUserRoles() ([]Role, error)
 	ListOCMRoles() ([]Role, error)
 	ListAccountRoles(version string) ([]Role, error)
 	ListOperatorRoles(version string) (map[string][]Role, error)
+	ListOperatorRoleResources() ([]OperatorRoleResource, error)
+	ListOperatorPolicyResources() ([]OperatorPolicyResource, error)
+	ListOpenIDConnectProviderResources() ([]OIDCProviderResource, error)
 	GetRoleByARN(roleARN string) (*iam.Role, error)
 	HasCompatibleVersionTags(iamTags []*iam.Tag, version string) (bool, error)
 	DeleteOperatorRole(roles string, managedPolicies bool) error
+	DeletePolicy(policyARN string) error
 	GetOperatorRolesFromAccountByClusterID(clusterID string, credRequests map[string]*cmv1.STSOperator) ([]string, error)
 	GetOperatorRolesFromAccountByPrefix(prefix string, credRequest map[string]*cmv1.STSOperator) ([]string, error)
 	GetPolicies(roles []string) (map[string][]string, error)
@@ -137,6 +152,8 @@ type Client interface {
 	DeleteUserRole(roleName string) error
 	GetAccountRolePolicies(roles []string) (map[string][]PolicyDetail, error)
 	GetAttachedPolicy(role *string) ([]PolicyDetail, error)
+	GetAttachedPolicyDocuments(roleName string) (map[string]string, error)
+	GetRoleTrustPolicy(roleName string) (string, error)
 	HasPermissionsBoundary(roleName string) (bool, error)
 	GetOpenIDConnectProviderByClusterIdTag(clusterID string) (string, error)
 	GetOpenIDConnectProviderByOidcEndpointUrl(oidcEndpointUrl string) (string, error)
@@ -159,6 +176,11 @@ type Client interface {
 	) (bool, error)
 	UpdateTag(roleName string, defaultPolicyVersion string) error
 	AddRoleTag(roleName string, key string, value string) error
+	GetRoleTags(roleName string) (map[string]string, error)
+	AddRoleTags(roleName string, tagList map[string]string) error
+	AddPolicyTags(policyARN string, tagList map[string]string) error
+	ListPolicyVersions(policyArn string) ([]PolicyVersion, error)
+	SetDefaultPolicyVersion(policyArn string, versionID string) error
 	IsPolicyCompatible(policyArn string, version string) (bool, error)
 	GetAccountRoleVersion(roleName string) (string, error)
 	IsPolicyExists(policyARN string) (*iam.GetPolicyOutput, error)
@@ -179,9 +201,14 @@ type Client interface {
 		policies map[string]*cmv1.AWSSTSPolicy, hostedCPPolicies bool) error
 	CreateS3Bucket(bucketName string, region string) error
 	DeleteS3Bucket(bucketName string) error
+	GetS3BucketPublicAccessBlock(bucketName string) (*s3.PublicAccessBlockConfiguration, error)
+	GetS3BucketPolicy(bucketName string) (string, error)
 	PutPublicReadObjectInS3Bucket(bucketName string, body io.ReadSeeker, key string) error
 	CreateSecretInSecretsManager(name string, secret string) (string, error)
 	DeleteSecretInSecretsManager(secretArn string) error
+	GetCurrentSecretVersion(secretArn string) (*SecretVersion, error)
+	PutPendingSecretVersion(secretArn string, versionID string, secret string) error
+	PromoteSecretVersion(secretArn string, versionID string, currentVersionID string) error
 }
 
 // ClientBuilder contains the information and logic needed to build a new AWS client.
@@ -205,12 +232,12 @@ type awsClient struct {
 	awsAccessKeys       *AccessKey
 }
 
-func CreateNewClientOrExit(logger *logrus.Logger, reporter *reporter.Object) Client {
+func CreateNewClientOrExit(logger *logrus.Logger, r *reporter.Object) Client {
 	awsClient, err := NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
+		r.WithCode(reporter.CodeAWSClient).Errorf("Failed to create AWS client: %v", err)
 		os.Exit(1)
 	}
 
@@ -367,6 +394,14 @@ func (b *ClientBuilder) Build() (Client, error) {
 		},
 	})
 
+	if cassette.Enabled() {
+		sess.Config.HTTPClient.Transport = cassette.Transport(sess.Config.HTTPClient.Transport)
+	}
+
+	if har.Enabled() {
+		sess.Config.HTTPClient.Transport = har.Transport(sess.Config.HTTPClient.Transport)
+	}
+
 	if b.logger.IsLevelEnabled(logrus.DebugLevel) {
 		var dumper http.RoundTripper
 		dumper, err = logging.NewRoundTripper().
@@ -967,6 +1002,18 @@ const ReadOnlyAnonUserPolicyTemplate = `{
 	]
 }`
 
+// PublicReadAccessBlockConfiguration returns the public access block of the buckets of OIDC
+// configurations, which blocks public ACLs but allows the bucket policy that grants public read
+// access to the documents.
+func PublicReadAccessBlockConfiguration() *s3.PublicAccessBlockConfiguration {
+	return &s3.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.Bool(true),
+		IgnorePublicAcls:      aws.Bool(true),
+		BlockPublicPolicy:     aws.Bool(false),
+		RestrictPublicBuckets: aws.Bool(false),
+	}
+}
+
 func (c *awsClient) CreateS3Bucket(bucketName string, region string) error {
 	_, err := c.s3Client.HeadBucket(&s3.HeadBucketInput{
 		Bucket: aws.String(bucketName),
@@ -988,13 +1035,8 @@ func (c *awsClient) CreateS3Bucket(bucketName string, region string) error {
 	}
 
 	_, err = c.s3Client.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
-		Bucket: aws.String(bucketName),
-		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
-			BlockPublicAcls:       aws.Bool(true),
-			IgnorePublicAcls:      aws.Bool(true),
-			BlockPublicPolicy:     aws.Bool(false),
-			RestrictPublicBuckets: aws.Bool(false),
-		},
+		Bucket:                         aws.String(bucketName),
+		PublicAccessBlockConfiguration: PublicReadAccessBlockConfiguration(),
 	})
 	if err != nil {
 		return err
@@ -1025,6 +1067,36 @@ func (c *awsClient) CreateS3Bucket(bucketName string, region string) error {
 	return nil
 }
 
+// GetS3BucketPublicAccessBlock returns the public access block of the bucket, or an error of type
+// NotFound if it doesn't have one.
+func (c *awsClient) GetS3BucketPublicAccessBlock(bucketName string) (*s3.PublicAccessBlockConfiguration, error) {
+	output, err := c.s3Client.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchPublicAccessBlockConfiguration" {
+			return nil, weberr.NotFound.Errorf("Bucket '%s' doesn't have a public access block", bucketName)
+		}
+		return nil, err
+	}
+	return output.PublicAccessBlockConfiguration, nil
+}
+
+// GetS3BucketPolicy returns the policy of the bucket, or an error of type NotFound if it doesn't
+// have one.
+func (c *awsClient) GetS3BucketPolicy(bucketName string) (string, error) {
+	output, err := c.s3Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchBucketPolicy" {
+			return "", weberr.NotFound.Errorf("Bucket '%s' doesn't have a policy", bucketName)
+		}
+		return "", err
+	}
+	return aws.StringValue(output.Policy), nil
+}
+
 func (c *awsClient) DeleteS3Bucket(bucketName string) error {
 	_, err := c.s3Client.HeadBucket(&s3.HeadBucketInput{
 		Bucket: aws.String(bucketName),
@@ -1117,6 +1189,60 @@ func (c *awsClient) DeleteSecretInSecretsManager(secretArn string) error {
 	return nil
 }
 
+// SecretVersion is a version of a secret stored in Secrets Manager.
+type SecretVersion struct {
+	ID          string
+	Value       string
+	CreatedDate time.Time
+}
+
+// GetCurrentSecretVersion returns the version of the secret with the 'AWSCURRENT' stage.
+func (c *awsClient) GetCurrentSecretVersion(secretArn string) (*SecretVersion, error) {
+	output, err := c.smClient.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SecretVersion{
+		ID:          aws.StringValue(output.VersionId),
+		Value:       aws.StringValue(output.SecretString),
+		CreatedDate: aws.TimeValue(output.CreatedDate),
+	}, nil
+}
+
+// PutPendingSecretVersion adds a version of the secret with the 'AWSPENDING' stage, so that it isn't
+// used until it is promoted with PromoteSecretVersion.
+func (c *awsClient) PutPendingSecretVersion(secretArn string, versionID string, secret string) error {
+	_, err := c.smClient.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:           aws.String(secretArn),
+		ClientRequestToken: aws.String(versionID),
+		SecretString:       aws.String(secret),
+		VersionStages:      aws.StringSlice([]string{SecretStagePending}),
+	})
+	return err
+}
+
+// PromoteSecretVersion moves the 'AWSCURRENT' stage from the current version of the secret to the
+// pending one, which makes the current version the previous one.
+func (c *awsClient) PromoteSecretVersion(secretArn string, versionID string, currentVersionID string) error {
+	_, err := c.smClient.UpdateSecretVersionStage(&secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:            aws.String(secretArn),
+		VersionStage:        aws.String(SecretStageCurrent),
+		MoveToVersionId:     aws.String(versionID),
+		RemoveFromVersionId: aws.String(currentVersionID),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.smClient.UpdateSecretVersionStage(&secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:            aws.String(secretArn),
+		VersionStage:        aws.String(SecretStagePending),
+		RemoveFromVersionId: aws.String(versionID),
+	})
+	return err
+}
+
 // CustomRetryer wraps the aws SDK's built in DefaultRetryer allowing for
 // additional custom features
 type CustomRetryer struct {
@@ -1,17 +1,25 @@
 package aws_test
 
 import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
+	errors "github.com/zgalor/weberr"
 
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/aws/mocks"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/info"
 )
 
 var _ = Describe("Client", func() {
@@ -195,4 +203,314 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Context("ListOperatorRoleResources", func() {
+		It("Returns only the roles tagged with an operator namespace", func() {
+			mockIamAPI.EXPECT().ListRolesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ *iam.ListRolesInput, fn func(*iam.ListRolesOutput, bool) bool) error {
+					fn(&iam.ListRolesOutput{
+						Roles: []*iam.Role{
+							{
+								RoleName: awssdk.String("mycluster-a1b2-openshift-ingress-operator-cloud-credentials"),
+								Arn:      awssdk.String("arn:aws:iam::123456789012:role/mycluster-a1b2-openshift-ingress"),
+							},
+							{RoleName: awssdk.String("custom-role")},
+							{RoleName: awssdk.String("ManagedOpenShift-Installer-Role")},
+						},
+					}, true)
+					return nil
+				})
+			mockIamAPI.EXPECT().ListRoleTags(&iam.ListRoleTagsInput{
+				RoleName: awssdk.String("mycluster-a1b2-openshift-ingress-operator-cloud-credentials"),
+			}).Return(&iam.ListRoleTagsOutput{
+				Tags: []*iam.Tag{
+					{Key: awssdk.String(tags.OperatorNamespace), Value: awssdk.String("openshift-ingress-operator")},
+					{Key: awssdk.String(tags.ClusterID), Value: awssdk.String("123")},
+				},
+			}, nil)
+
+			roles, err := client.ListOperatorRoleResources()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles).To(HaveLen(1))
+			Expect(roles[0].Name).To(Equal("mycluster-a1b2-openshift-ingress-operator-cloud-credentials"))
+			Expect(roles[0].Tags).To(HaveKeyWithValue(tags.ClusterID, "123"))
+		})
+	})
+
+	Context("GetAttachedPolicyDocuments", func() {
+		It("Returns the unescaped documents of the default policy versions", func() {
+			policyARN := "arn:aws:iam::123456789012:policy/ManagedOpenShift-Installer-Role-Policy"
+			mockIamAPI.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: awssdk.String(policyARN)}},
+			}, nil)
+			mockIamAPI.EXPECT().GetPolicy(&iam.GetPolicyInput{PolicyArn: awssdk.String(policyARN)}).Return(
+				&iam.GetPolicyOutput{Policy: &iam.Policy{DefaultVersionId: awssdk.String("v2")}}, nil)
+			mockIamAPI.EXPECT().GetPolicyVersion(&iam.GetPolicyVersionInput{
+				PolicyArn: awssdk.String(policyARN),
+				VersionId: awssdk.String("v2"),
+			}).Return(&iam.GetPolicyVersionOutput{
+				PolicyVersion: &iam.PolicyVersion{Document: awssdk.String("%7B%22Statement%22%3A%5B%5D%7D")},
+			}, nil)
+
+			documents, err := client.GetAttachedPolicyDocuments("ManagedOpenShift-Installer-Role")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(documents).To(Equal(map[string]string{policyARN: `{"Statement":[]}`}))
+		})
+	})
+
+	Context("GetRoleTags", func() {
+		It("Returns the tags of the role", func() {
+			mockIamAPI.EXPECT().GetRole(&iam.GetRoleInput{RoleName: awssdk.String("custom-role")}).Return(
+				&iam.GetRoleOutput{Role: &iam.Role{
+					Tags: []*iam.Tag{{Key: awssdk.String(tags.RoleType), Value: awssdk.String("installer")}},
+				}}, nil)
+
+			roleTags, err := client.GetRoleTags("custom-role")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roleTags).To(Equal(map[string]string{tags.RoleType: "installer"}))
+		})
+
+		It("Returns a not found error if the role doesn't exist", func() {
+			mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(nil,
+				awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+
+			_, err := client.GetRoleTags("missing-role")
+
+			Expect(errors.GetType(err)).To(Equal(errors.NotFound))
+		})
+	})
+
+	Context("ForceEnsurePolicy", func() {
+		policyARN := "arn:aws:iam::123456789012:policy/prefix-Installer-Role-Policy"
+
+		It("Keeps the previous versions of the policy and records the versions that created them", func() {
+			created := func(day int) *time.Time {
+				date := time.Date(2023, 1, day, 0, 0, 0, 0, time.UTC)
+				return &date
+			}
+			mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(&iam.GetPolicyOutput{Policy: &iam.Policy{
+				Arn:              awssdk.String(policyARN),
+				DefaultVersionId: awssdk.String("v5"),
+			}}, nil)
+			mockIamAPI.EXPECT().ListPolicyTags(gomock.Any()).Return(&iam.ListPolicyTagsOutput{
+				Tags: []*iam.Tag{{Key: awssdk.String(tags.OpenShiftVersion), Value: awssdk.String("4.12")}},
+			}, nil)
+			mockIamAPI.EXPECT().TagPolicy(&iam.TagPolicyInput{
+				PolicyArn: awssdk.String(policyARN),
+				Tags: []*iam.Tag{{
+					Key:   awssdk.String(tags.PolicyVersion + "v5"),
+					Value: awssdk.String("openshift=4.12"),
+				}},
+			}).Return(&iam.TagPolicyOutput{}, nil)
+			mockIamAPI.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+				Versions: []*iam.PolicyVersion{
+					{VersionId: awssdk.String("v5"), IsDefaultVersion: awssdk.Bool(true), CreateDate: created(5)},
+					{VersionId: awssdk.String("v2"), IsDefaultVersion: awssdk.Bool(false), CreateDate: created(2)},
+					{VersionId: awssdk.String("v4"), IsDefaultVersion: awssdk.Bool(false), CreateDate: created(4)},
+					{VersionId: awssdk.String("v1"), IsDefaultVersion: awssdk.Bool(false), CreateDate: created(1)},
+					{VersionId: awssdk.String("v3"), IsDefaultVersion: awssdk.Bool(false), CreateDate: created(3)},
+				},
+			}, nil)
+			mockIamAPI.EXPECT().DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+				PolicyArn: awssdk.String(policyARN),
+				VersionId: awssdk.String("v1"),
+			}).Return(&iam.DeletePolicyVersionOutput{}, nil)
+			mockIamAPI.EXPECT().UntagPolicy(&iam.UntagPolicyInput{
+				PolicyArn: awssdk.String(policyARN),
+				TagKeys:   []*string{awssdk.String(tags.PolicyVersion + "v1")},
+			}).Return(&iam.UntagPolicyOutput{}, nil)
+			mockIamAPI.EXPECT().CreatePolicyVersion(gomock.Any()).Return(&iam.CreatePolicyVersionOutput{
+				PolicyVersion: &iam.PolicyVersion{VersionId: awssdk.String("v6")},
+			}, nil)
+			mockIamAPI.EXPECT().TagPolicy(gomock.Any()).DoAndReturn(
+				func(input *iam.TagPolicyInput) (*iam.TagPolicyOutput, error) {
+					policyTags := map[string]string{}
+					for _, tag := range input.Tags {
+						policyTags[awssdk.StringValue(tag.Key)] = awssdk.StringValue(tag.Value)
+					}
+					Expect(policyTags).To(HaveKeyWithValue(tags.OpenShiftVersion, "4.13"))
+					Expect(policyTags).To(HaveKeyWithValue(tags.PolicyVersion+"v6",
+						fmt.Sprintf("openshift=4.13/rosa=%s", info.Version)))
+					return &iam.TagPolicyOutput{}, nil
+				})
+
+			_, err := client.ForceEnsurePolicy(policyARN, `{"Statement":[]}`, "4.13",
+				map[string]string{tags.OpenShiftVersion: "4.13"}, "")
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("ListPolicyVersions", func() {
+		It("Returns the versions the newest first, with the versions that created them", func() {
+			mockIamAPI.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+				Versions: []*iam.PolicyVersion{
+					{
+						VersionId:        awssdk.String("v1"),
+						IsDefaultVersion: awssdk.Bool(false),
+						CreateDate:       awssdk.Time(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+					},
+					{
+						VersionId:        awssdk.String("v2"),
+						IsDefaultVersion: awssdk.Bool(true),
+						CreateDate:       awssdk.Time(time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			}, nil)
+			mockIamAPI.EXPECT().ListPolicyTags(gomock.Any()).Return(&iam.ListPolicyTagsOutput{
+				Tags: []*iam.Tag{
+					{Key: awssdk.String(tags.PolicyVersion + "v1"), Value: awssdk.String("openshift=4.12")},
+					{Key: awssdk.String(tags.PolicyVersion + "v2"), Value: awssdk.String("openshift=4.13 rosa=1.2.22")},
+				},
+			}, nil)
+
+			versions, err := client.ListPolicyVersions("arn:aws:iam::123456789012:policy/policy")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]aws.PolicyVersion{
+				{
+					ID:               "v2",
+					IsDefault:        true,
+					CreateDate:       time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+					OpenShiftVersion: "4.13",
+					RosaVersion:      "1.2.22",
+				},
+				{
+					ID:               "v1",
+					CreateDate:       time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+					OpenShiftVersion: "4.12",
+				},
+			}))
+		})
+	})
+
+	Context("PolicyVersionsToPrune", func() {
+		It("Returns the oldest non default versions beyond the limit for manual mode", func() {
+			versions := []*iam.PolicyVersion{}
+			for day := 1; day <= 5; day++ {
+				versions = append(versions, &iam.PolicyVersion{
+					VersionId:        awssdk.String(fmt.Sprintf("v%d", day)),
+					IsDefaultVersion: awssdk.Bool(day == 1),
+					CreateDate:       awssdk.Time(time.Date(2023, 1, day, 0, 0, 0, 0, time.UTC)),
+				})
+			}
+			mockIamAPI.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+				Versions: versions,
+			}, nil)
+			mockIamAPI.EXPECT().ListPolicyTags(gomock.Any()).Return(&iam.ListPolicyTagsOutput{}, nil)
+
+			pruned, err := aws.PolicyVersionsToPrune(client, "arn:aws:iam::123456789012:policy/policy")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(Equal([]string{"v2"}))
+		})
+	})
+
+	Context("SimulateRolePolicy", func() {
+		var mockOrgAPI *mocks.MockOrganizationsAPI
+
+		BeforeEach(func() {
+			mockOrgAPI = mocks.NewMockOrganizationsAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mockEC2API,
+				mockOrgAPI,
+				mockS3API,
+				mockSecretsManagerAPI,
+				mocks.NewMockSTSAPI(mockCtrl),
+				mockCfAPI,
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				&session.Session{Config: &awssdk.Config{Region: awssdk.String("us-east-1")}},
+				&aws.AccessKey{},
+			)
+		})
+
+		It("Returns the denied actions with the SCP and the boundary that deny them", func() {
+			roleARN := "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+			boundaryARN := "arn:aws:iam::123456789012:policy/boundary"
+			mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+				Role: &iam.Role{
+					Arn: awssdk.String(roleARN),
+					PermissionsBoundary: &iam.AttachedPermissionsBoundary{
+						PermissionsBoundaryArn: awssdk.String(boundaryARN),
+					},
+				},
+			}, nil)
+			mockIamAPI.EXPECT().SimulatePrincipalPolicyPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *iam.SimulatePrincipalPolicyInput,
+					fn func(*iam.SimulatePolicyResponse, bool) bool) error {
+					Expect(awssdk.StringValueSlice(input.ActionNames)).To(Equal([]string{
+						"ec2:RunInstances", "iam:PassRole",
+					}))
+					fn(&iam.SimulatePolicyResponse{
+						EvaluationResults: []*iam.EvaluationResult{
+							{
+								EvalActionName: awssdk.String("ec2:RunInstances"),
+								EvalDecision:   awssdk.String(iam.PolicyEvaluationDecisionTypeImplicitDeny),
+								OrganizationsDecisionDetail: &iam.OrganizationsDecisionDetail{
+									AllowedByOrganizations: awssdk.Bool(false),
+								},
+							},
+							{
+								EvalActionName: awssdk.String("iam:PassRole"),
+								EvalDecision:   awssdk.String(iam.PolicyEvaluationDecisionTypeImplicitDeny),
+								OrganizationsDecisionDetail: &iam.OrganizationsDecisionDetail{
+									AllowedByOrganizations: awssdk.Bool(true),
+								},
+								PermissionsBoundaryDecisionDetail: &iam.PermissionsBoundaryDecisionDetail{
+									AllowedByPermissionsBoundary: awssdk.Bool(false),
+								},
+							},
+						},
+					}, true)
+					return nil
+				})
+			mockOrgAPI.EXPECT().ListParents(gomock.Any()).Return(&organizations.ListParentsOutput{
+				Parents: []*organizations.Parent{
+					{Id: awssdk.String("r-root"), Type: awssdk.String(organizations.ParentTypeRoot)},
+				},
+			}, nil)
+			mockOrgAPI.EXPECT().ListPoliciesForTargetPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *organizations.ListPoliciesForTargetInput,
+					fn func(*organizations.ListPoliciesForTargetOutput, bool) bool) error {
+					if awssdk.StringValue(input.TargetId) == "r-root" {
+						fn(&organizations.ListPoliciesForTargetOutput{
+							Policies: []*organizations.PolicySummary{
+								{Id: awssdk.String("p-1"), Name: awssdk.String("deny-ec2")},
+							},
+						}, true)
+					}
+					return nil
+				}).Times(2)
+			mockOrgAPI.EXPECT().DescribePolicy(gomock.Any()).Return(&organizations.DescribePolicyOutput{
+				Policy: &organizations.Policy{
+					Content: awssdk.String(`{"Statement": [{"Effect": "Deny", "Action": "ec2:*", "Resource": "*"}]}`),
+				},
+			}, nil)
+
+			deniedActions, err := client.SimulateRolePolicy(roleARN, `{"Statement": [
+				{"Effect": "Allow", "Action": ["ec2:RunInstances", "iam:PassRole", "s3:*"], "Resource": "*"},
+				{"Effect": "Allow", "Action": "kms:Decrypt", "Resource": "*",
+					"Condition": {"Bool": {"kms:GrantIsForAWSResource": true}}}
+			]}`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deniedActions).To(Equal([]aws.DeniedAction{
+				{
+					Action:   "ec2:RunInstances",
+					Decision: iam.PolicyEvaluationDecisionTypeImplicitDeny,
+					DeniedBy: []string{"SCP 'deny-ec2 (p-1)'"},
+				},
+				{
+					Action:   "iam:PassRole",
+					Decision: iam.PolicyEvaluationDecisionTypeImplicitDeny,
+					DeniedBy: []string{"permissions boundary '" + boundaryARN + "'"},
+				},
+			}))
+		})
+	})
 })
@@ -18,6 +18,8 @@ package aws
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -26,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 
 	"github.com/openshift/rosa/assets"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
 )
 
 func readCloudFormationTemplate(path string) (string, error) {
@@ -197,6 +200,70 @@ func (c *awsClient) CheckStackReadyOrNotExisting(stackName string) (stackReady b
 	return false, nil, nil
 }
 
+// EnsureStack creates the CloudFormation stack with the given template, or updates it if it
+// already exists. It returns true if the stack was created.
+func (c *awsClient) EnsureStack(cfTemplateBody string, stackName string) (bool, error) {
+	output, err := c.cfClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "ValidationError" ||
+			!strings.Contains(aerr.Message(), "does not exist") {
+			return false, err
+		}
+		_, err = c.CreateStack(cfTemplateBody, stackName)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	for _, stack := range output.Stacks {
+		switch aws.StringValue(stack.StackStatus) {
+		case cloudformation.StackStatusCreateComplete,
+			cloudformation.StackStatusUpdateComplete,
+			cloudformation.StackStatusUpdateRollbackComplete:
+		default:
+			return false, fmt.Errorf("CloudFormation stack '%s' exists with status %s, it must be %s or %s "+
+				"to be updated", stackName, aws.StringValue(stack.StackStatus),
+				cloudformation.StackStatusCreateComplete, cloudformation.StackStatusUpdateComplete)
+		}
+	}
+	_, err = c.UpdateStack(cfTemplateBody, stackName)
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+var invalidStackNameCharacters = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+
+// GetStackName returns a valid CloudFormation stack name built from the given parts, replacing the
+// characters that aren't allowed in stack names.
+func GetStackName(parts ...string) string {
+	name := invalidStackNameCharacters.ReplaceAllString(strings.Join(parts, "-"), "-")
+	if len(name) > 128 {
+		name = name[:128]
+	}
+	return strings.Trim(name, "-")
+}
+
+// EnsureStackFromCommands converts the commands generated in manual mode into a CloudFormation
+// template and creates or updates the stack with it. The files embedded in the template are kept,
+// as they may be the ones that the user saved from a previous run of manual mode. It returns the
+// commands that have no CloudFormation equivalent and must be run after the stack is ready.
+func EnsureStackFromCommands(client Client, stackName string, commands string,
+	parameters awscb.TemplateParameters) ([]string, error) {
+	template, err := awscb.CloudFormation(commands, parameters)
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.EnsureStack(template.Body, stackName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create or update CloudFormation stack '%s': %v", stackName, err)
+	}
+	return template.Manual, nil
+}
+
 func (c *awsClient) DeleteOsdCcsAdminUser(stackName string) error {
 	deleteStackInput := &cloudformation.DeleteStackInput{
 		StackName: aws.String(stackName),
@@ -0,0 +1,452 @@
+package commandbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TemplateParameters contains the values that were used to generate the commands and that templates
+// expose as parameters, so that the same template can be deployed with different values.
+type TemplateParameters struct {
+	Prefix              string
+	Path                string
+	PermissionsBoundary string
+
+	// ReadFile reads the policy documents referenced by the commands. When it is nil the files are
+	// read from the current directory.
+	ReadFile func(name string) ([]byte, error)
+}
+
+// CloudFormationTemplate is the result of converting commands to a CloudFormation template.
+type CloudFormationTemplate struct {
+	// Body is the JSON document of the template.
+	Body string
+
+	// Manual contains the commands that have no equivalent CloudFormation resource, like tagging
+	// policies or 'rosa link ocm-role', and that must be run after creating the stack.
+	Manual []string
+
+	// Files contains the names of the files whose content was embedded in the template.
+	Files []string
+}
+
+// CloudFormation converts commands joined with JoinCommands into a CloudFormation template that
+// creates the same IAM roles, policies and OIDC providers. The prefix, path and permissions
+// boundary are template parameters, as well as the tags that have the same value in all the
+// resources. Policy documents are embedded in the template, and commands that only remove the
+// files are dropped.
+func CloudFormation(text string, parameters TemplateParameters) (*CloudFormationTemplate, error) {
+	var invocations []*Invocation
+	for _, command := range SplitCommands(text) {
+		invocation, err := Parse(command)
+		if err != nil {
+			return nil, err
+		}
+		invocations = append(invocations, invocation)
+	}
+
+	converter := &cloudFormationConverter{
+		parameters:     parameters,
+		resources:      map[string]*cfResource{},
+		usedIDs:        map[string]bool{},
+		roles:          map[string]string{},
+		policies:       map[string]string{},
+		policyARNs:     map[string]string{},
+		tagParameters:  map[string]string{},
+		usedParameters: map[string]bool{},
+	}
+	if converter.parameters.ReadFile == nil {
+		converter.parameters.ReadFile = os.ReadFile
+	}
+	converter.index(invocations)
+	for _, invocation := range invocations {
+		err := converter.convert(invocation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return converter.template()
+}
+
+const (
+	cfRole         = "AWS::IAM::Role"
+	cfPolicy       = "AWS::IAM::ManagedPolicy"
+	cfOIDCProvider = "AWS::IAM::OIDCProvider"
+
+	cfPrefix              = "Prefix"
+	cfPath                = "Path"
+	cfPermissionsBoundary = "PermissionsBoundary"
+
+	cfHasPermissionsBoundary = "HasPermissionsBoundary"
+)
+
+type cfResource struct {
+	Type       string                 `json:"Type"`
+	Properties map[string]interface{} `json:"Properties"`
+}
+
+type cloudFormationConverter struct {
+	parameters TemplateParameters
+	resources  map[string]*cfResource
+	usedIDs    map[string]bool
+	manual     []string
+	files      []string
+
+	// roles and policies contain the logical identifiers of the roles and policies created by the
+	// commands, indexed by name, and policyARNs contains the ARNs used to attach the policies.
+	roles      map[string]string
+	policies   map[string]string
+	policyARNs map[string]string
+
+	// tagParameters contains the names of the parameters of the tags that have the same value in
+	// all the resources, indexed by tag key.
+	tagParameters  map[string]string
+	tagValues      map[string]string
+	usedParameters map[string]bool
+}
+
+// index assigns logical identifiers to the resources created by the commands before they are
+// converted, so that references work regardless of the order of the commands, and finds the tags
+// that can be parameters.
+func (c *cloudFormationConverter) index(invocations []*Invocation) {
+	values := map[string]map[string]bool{}
+	for _, i := range invocations {
+		switch {
+		case i.Service == IAM && i.Command == CreateRole:
+			c.roles[i.Value(RoleName)] = c.newID(i.Value(RoleName), "Role")
+		case i.Service == IAM && i.Command == CreatePolicy:
+			c.policies[i.Value(PolicyName)] = c.newID(i.Value(PolicyName), "Policy")
+		case i.Service == IAM && i.Command == AttachRolePolicy:
+			policyARN := i.Value(PolicyArn)
+			c.policyARNs[policyARN[strings.LastIndex(policyARN, "/")+1:]] = policyARN
+		case i.Service == IAM && i.Command == CreateOpenIdConnectProvider:
+		default:
+			continue
+		}
+		for key, value := range i.Tags() {
+			if values[key] == nil {
+				values[key] = map[string]bool{}
+			}
+			values[key][value] = true
+		}
+	}
+	c.tagValues = map[string]string{}
+	for key, keyValues := range values {
+		if len(keyValues) != 1 {
+			continue
+		}
+		for value := range keyValues {
+			c.tagValues[key] = value
+		}
+		c.tagParameters[key] = "Tag" + camelCase(key)
+	}
+}
+
+func (c *cloudFormationConverter) convert(i *Invocation) error {
+	switch {
+	case i.Service == "" && strings.HasPrefix(i.Raw, "rm "):
+		// The files are embedded in the template, so there is nothing to remove.
+	case i.Service == IAM && i.Command == CreateRole:
+		document, err := c.readDocument(i.Value(AssumeRolePolicyDocument))
+		if err != nil {
+			return err
+		}
+		properties := map[string]interface{}{
+			"RoleName":                 c.name(i.Value(RoleName)),
+			"AssumeRolePolicyDocument": document,
+			"Path":                     c.path(i.Value(Path)),
+			"PermissionsBoundary":      c.permissionsBoundary(i.Value(PermissionsBoundary)),
+		}
+		c.addTags(properties, i.Tags())
+		c.resources[c.roles[i.Value(RoleName)]] = &cfResource{
+			Type:       cfRole,
+			Properties: properties,
+		}
+	case i.Service == IAM && i.Command == CreatePolicy:
+		document, err := c.readDocument(i.Value(PolicyDocument))
+		if err != nil {
+			return err
+		}
+		c.resources[c.policies[i.Value(PolicyName)]] = &cfResource{
+			Type: cfPolicy,
+			Properties: map[string]interface{}{
+				"ManagedPolicyName": c.name(i.Value(PolicyName)),
+				"PolicyDocument":    document,
+				"Path":              c.path(i.Value(Path)),
+			},
+		}
+		// Managed policies can't be tagged by CloudFormation, but ROSA uses the tags to find and
+		// upgrade the policies. The ARN of a policy that isn't attached by the commands is looked up
+		// by name, as the account and partition aren't known here:
+		if len(i.Tags()) > 0 {
+			policyARN, ok := c.policyARNs[i.Value(PolicyName)]
+			if !ok {
+				policyARN = fmt.Sprintf(`"$(aws iam list-policies --scope Local `+
+					"--query 'Policies[?PolicyName==`%s`].Arn' --output text)\"", i.Value(PolicyName))
+			}
+			c.manual = append(c.manual, NewIAMCommandBuilder().
+				SetCommand(TagPolicy).
+				AddParam(PolicyArn, policyARN).
+				AddTags(i.Tags()).
+				Build())
+		}
+	case i.Service == IAM && i.Command == AttachRolePolicy:
+		return c.attach(i)
+	case i.Service == IAM && i.Command == CreateOpenIdConnectProvider:
+		properties := map[string]interface{}{
+			"Url":            i.Value(Url),
+			"ClientIdList":   i.Params[ClientIdList],
+			"ThumbprintList": i.Params[ThumbprintList],
+		}
+		c.addTags(properties, i.Tags())
+		c.resources[c.newID("", "OIDCProvider")] = &cfResource{
+			Type:       cfOIDCProvider,
+			Properties: properties,
+		}
+	default:
+		c.manual = append(c.manual, i.Raw)
+	}
+	return nil
+}
+
+// attach adds the policy to the managed policies of the role when the role is created by the
+// template, or the role to the roles of the policy when only the policy is.
+func (c *cloudFormationConverter) attach(i *Invocation) error {
+	roleName := i.Value(RoleName)
+	policyARN := i.Value(PolicyArn)
+	var policy interface{} = policyARN
+	policyID, policyCreated := c.policies[policyARN[strings.LastIndex(policyARN, "/")+1:]]
+	if policyCreated {
+		policy = map[string]interface{}{"Ref": policyID}
+	}
+	if roleID, ok := c.roles[roleName]; ok {
+		role := c.resources[roleID]
+		if role == nil {
+			return fmt.Errorf("Role '%s' must be created before attaching policies to it", roleName)
+		}
+		arns, _ := role.Properties["ManagedPolicyArns"].([]interface{})
+		role.Properties["ManagedPolicyArns"] = append(arns, policy)
+		return nil
+	}
+	if policyCreated && c.resources[policyID] != nil {
+		properties := c.resources[policyID].Properties
+		roles, _ := properties["Roles"].([]interface{})
+		properties["Roles"] = append(roles, roleName)
+		return nil
+	}
+	c.manual = append(c.manual, i.Raw)
+	return nil
+}
+
+// name returns the expression for the name of a resource, replacing the prefix with a reference
+// to the parameter.
+func (c *cloudFormationConverter) name(value string) interface{} {
+	prefix := c.parameters.Prefix
+	if prefix == "" || !strings.HasPrefix(value, prefix) {
+		return value
+	}
+	c.usedParameters[cfPrefix] = true
+	if value == prefix {
+		return map[string]interface{}{"Ref": cfPrefix}
+	}
+	rest := strings.ReplaceAll(strings.TrimPrefix(value, prefix), "${", "${!")
+	return map[string]interface{}{"Fn::Sub": "${" + cfPrefix + "}" + rest}
+}
+
+func (c *cloudFormationConverter) path(value string) interface{} {
+	if value != "" && value != c.parameters.Path {
+		return value
+	}
+	c.usedParameters[cfPath] = true
+	return map[string]interface{}{"Ref": cfPath}
+}
+
+func (c *cloudFormationConverter) permissionsBoundary(value string) interface{} {
+	if value != "" && value != c.parameters.PermissionsBoundary {
+		return value
+	}
+	c.usedParameters[cfPermissionsBoundary] = true
+	return map[string]interface{}{
+		"Fn::If": []interface{}{
+			cfHasPermissionsBoundary,
+			map[string]interface{}{"Ref": cfPermissionsBoundary},
+			map[string]interface{}{"Ref": "AWS::NoValue"},
+		},
+	}
+}
+
+func (c *cloudFormationConverter) addTags(properties map[string]interface{}, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		var value interface{} = tags[key]
+		if c.parameters.Prefix != "" && tags[key] == c.parameters.Prefix {
+			c.usedParameters[cfPrefix] = true
+			value = map[string]interface{}{"Ref": cfPrefix}
+		} else if parameter, ok := c.tagParameters[key]; ok {
+			c.usedParameters[parameter] = true
+			value = map[string]interface{}{"Ref": parameter}
+		}
+		list = append(list, map[string]interface{}{
+			"Key":   key,
+			"Value": value,
+		})
+	}
+	properties["Tags"] = list
+}
+
+// readDocument reads the JSON document referenced by a parameter like 'file://policy.json'.
+func (c *cloudFormationConverter) readDocument(value string) (json.RawMessage, error) {
+	if !strings.HasPrefix(value, "file://") {
+		if !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("Expected a valid JSON policy document, got '%s'", value)
+		}
+		return json.RawMessage(value), nil
+	}
+	name := fileName(value)
+	data, err := c.parameters.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read policy document '%s': %v", name, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("File '%s' doesn't contain a valid JSON policy document", name)
+	}
+	c.files = append(c.files, name)
+	return json.RawMessage(data), nil
+}
+
+var nonAlphanumericCharacters = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// newID returns a logical identifier derived from the given name without the prefix, ending with
+// the given kind, and that isn't used yet by another resource.
+func (c *cloudFormationConverter) newID(name string, kind string) string {
+	if c.parameters.Prefix != "" {
+		name = strings.TrimPrefix(name, c.parameters.Prefix)
+	}
+	base := camelCase(name)
+	if base == "" || !unicode.IsLetter(rune(base[0])) {
+		base = kind + base
+	} else if !strings.HasSuffix(base, kind) {
+		base += kind
+	}
+	id := base
+	for n := 2; c.usedIDs[id]; n++ {
+		id = fmt.Sprintf("%s%d", base, n)
+	}
+	c.usedIDs[id] = true
+	return id
+}
+
+func (c *cloudFormationConverter) template() (*CloudFormationTemplate, error) {
+	parameters := map[string]interface{}{}
+	if c.usedParameters[cfPrefix] {
+		parameters[cfPrefix] = map[string]interface{}{
+			"Type":        "String",
+			"Default":     c.parameters.Prefix,
+			"Description": "Prefix of the names of the roles and policies.",
+		}
+	}
+	if c.usedParameters[cfPath] {
+		path := c.parameters.Path
+		if path == "" {
+			path = "/"
+		}
+		parameters[cfPath] = map[string]interface{}{
+			"Type":        "String",
+			"Default":     path,
+			"Description": "Path of the roles and policies.",
+		}
+	}
+	conditions := map[string]interface{}{}
+	if c.usedParameters[cfPermissionsBoundary] {
+		parameters[cfPermissionsBoundary] = map[string]interface{}{
+			"Type":        "String",
+			"Default":     c.parameters.PermissionsBoundary,
+			"Description": "ARN of the policy used as permissions boundary of the roles, empty for none.",
+		}
+		conditions[cfHasPermissionsBoundary] = map[string]interface{}{
+			"Fn::Not": []interface{}{
+				map[string]interface{}{
+					"Fn::Equals": []interface{}{map[string]interface{}{"Ref": cfPermissionsBoundary}, ""},
+				},
+			},
+		}
+	}
+	for key, parameter := range c.tagParameters {
+		if !c.usedParameters[parameter] {
+			continue
+		}
+		parameters[parameter] = map[string]interface{}{
+			"Type":        "String",
+			"Default":     c.tagValues[key],
+			"Description": fmt.Sprintf("Value of the '%s' tag.", key),
+		}
+	}
+
+	outputs := map[string]interface{}{}
+	for id, resource := range c.resources {
+		var value interface{} = map[string]interface{}{"Ref": id}
+		if resource.Type == cfRole {
+			value = map[string]interface{}{"Fn::GetAtt": []string{id, "Arn"}}
+		}
+		outputs[id+"Arn"] = map[string]interface{}{"Value": value}
+	}
+
+	template := map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              "AWS resources for Red Hat OpenShift Service on AWS.",
+		"Resources":                c.resources,
+	}
+	if len(parameters) > 0 {
+		template["Parameters"] = parameters
+	}
+	if len(conditions) > 0 {
+		template["Conditions"] = conditions
+	}
+	if len(outputs) > 0 {
+		template["Outputs"] = outputs
+	}
+	var manual []string
+	for _, command := range c.manual {
+		manual = append(manual, strings.ReplaceAll(command, ParamNewLineSeparator+"\t", " "))
+	}
+	if len(manual) > 0 {
+		template["Metadata"] = map[string]interface{}{
+			"ManualCommands": manual,
+		}
+	}
+	body, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate CloudFormation template: %v", err)
+	}
+	return &CloudFormationTemplate{
+		Body:   string(body),
+		Manual: c.manual,
+		Files:  c.files,
+	}, nil
+}
+
+// camelCase converts a name like 'openshift-ingress-operator' to 'OpenshiftIngressOperator'.
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, word := range nonAlphanumericCharacters.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package commandbuilder_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+var _ = Describe("CloudFormation", func() {
+	files := map[string]string{
+		"sts_installer_trust_policy.json":      `{"Version": "2012-10-17", "Statement": []}`,
+		"sts_installer_permission_policy.json": `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow"}]}`,
+	}
+	readFile := func(name string) ([]byte, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("file '%s' not found", name)
+		}
+		return []byte(content), nil
+	}
+	roleTags := map[string]string{
+		"rosa_role_prefix":       "ManagedOpenShift",
+		"rosa_openshift_version": "4.13",
+		"rosa_role_type":         "installer",
+	}
+
+	It("converts roles and policies using parameters", func() {
+		commands := JoinCommands([]string{
+			NewIAMCommandBuilder().
+				SetCommand(CreateRole).
+				AddParam(RoleName, "ManagedOpenShift-Installer-Role").
+				AddParam(AssumeRolePolicyDocument, "file://sts_installer_trust_policy.json").
+				AddParam(Path, "/rosa/").
+				AddTags(roleTags).
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(CreatePolicy).
+				AddParam(PolicyName, "ManagedOpenShift-Installer-Role-Policy").
+				AddParam(PolicyDocument, "file://sts_installer_permission_policy.json").
+				AddParam(Path, "/rosa/").
+				AddTags(roleTags).
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(AttachRolePolicy).
+				AddParam(RoleName, "ManagedOpenShift-Installer-Role").
+				AddParam(PolicyArn, "arn:aws:iam::123:policy/rosa/ManagedOpenShift-Installer-Role-Policy").
+				Build(),
+			"rm sts_installer_trust_policy.json",
+		})
+		template, err := CloudFormation(commands, TemplateParameters{
+			Prefix:   "ManagedOpenShift",
+			Path:     "/rosa/",
+			ReadFile: readFile,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template.Files).To(ConsistOf("sts_installer_trust_policy.json",
+			"sts_installer_permission_policy.json"))
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal([]byte(template.Body), &body)).To(Succeed())
+		Expect(body["Parameters"]).To(HaveKey("Prefix"))
+		Expect(body["Parameters"]).To(HaveKey("Path"))
+		Expect(body["Parameters"]).To(HaveKey("PermissionsBoundary"))
+		Expect(body["Parameters"]).To(HaveKey("TagRosaOpenshiftVersion"))
+		Expect(body["Conditions"]).To(HaveKey("HasPermissionsBoundary"))
+
+		resources := body["Resources"].(map[string]interface{})
+		Expect(resources).To(HaveLen(2))
+		role := resources["InstallerRole"].(map[string]interface{})
+		Expect(role["Type"]).To(Equal("AWS::IAM::Role"))
+		properties := role["Properties"].(map[string]interface{})
+		Expect(properties["RoleName"]).To(Equal(map[string]interface{}{
+			"Fn::Sub": "${Prefix}-Installer-Role",
+		}))
+		Expect(properties["Path"]).To(Equal(map[string]interface{}{"Ref": "Path"}))
+		Expect(properties["AssumeRolePolicyDocument"]).To(HaveKeyWithValue("Version", "2012-10-17"))
+		Expect(properties["ManagedPolicyArns"]).To(Equal([]interface{}{
+			map[string]interface{}{"Ref": "InstallerRolePolicy"},
+		}))
+		Expect(properties["Tags"]).To(ContainElement(map[string]interface{}{
+			"Key":   "rosa_role_prefix",
+			"Value": map[string]interface{}{"Ref": "Prefix"},
+		}))
+		Expect(properties["Tags"]).To(ContainElement(map[string]interface{}{
+			"Key":   "rosa_openshift_version",
+			"Value": map[string]interface{}{"Ref": "TagRosaOpenshiftVersion"},
+		}))
+
+		policy := resources["InstallerRolePolicy"].(map[string]interface{})
+		Expect(policy["Type"]).To(Equal("AWS::IAM::ManagedPolicy"))
+		Expect(policy["Properties"]).ToNot(HaveKey("Tags"))
+
+		// Policies can't be tagged by CloudFormation:
+		Expect(template.Manual).To(HaveLen(1))
+		tagPolicy, err := Parse(template.Manual[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tagPolicy.Command).To(Equal(TagPolicy))
+		Expect(tagPolicy.Value(PolicyArn)).To(Equal(
+			"arn:aws:iam::123:policy/rosa/ManagedOpenShift-Installer-Role-Policy"))
+		Expect(tagPolicy.Tags()).To(Equal(roleTags))
+	})
+
+	It("tags the policies that the commands don't attach", func() {
+		commands := JoinCommands([]string{
+			NewIAMCommandBuilder().
+				SetCommand(CreatePolicy).
+				AddParam(PolicyName, "ManagedOpenShift-Installer-Role-Policy").
+				AddParam(PolicyDocument, "file://sts_installer_permission_policy.json").
+				AddTags(roleTags).
+				Build(),
+		})
+		template, err := CloudFormation(commands, TemplateParameters{ReadFile: readFile})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template.Manual).To(HaveLen(1))
+		tagPolicy, err := Parse(template.Manual[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tagPolicy.Command).To(Equal(TagPolicy))
+		Expect(tagPolicy.Value(PolicyArn)).To(ContainSubstring(
+			"aws iam list-policies --scope Local --query 'Policies[?PolicyName==`ManagedOpenShift-Installer-Role-Policy`]"))
+		Expect(tagPolicy.Tags()).To(Equal(roleTags))
+	})
+
+	It("converts OIDC providers and keeps unsupported commands", func() {
+		commands := JoinCommands([]string{
+			NewIAMCommandBuilder().
+				SetCommand(CreateOpenIdConnectProvider).
+				AddParam(Url, "https://oidc.example.com/abc").
+				AddParam(ClientIdList, "openshift sts.amazonaws.com").
+				AddParam(ThumbprintList, "a1b2").
+				AddTags(map[string]string{"red-hat-managed": "true"}).
+				Build(),
+			"rosa link ocm-role --role-arn arn:aws:iam::123:role/ocm",
+		})
+		template, err := CloudFormation(commands, TemplateParameters{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template.Manual).To(Equal([]string{"rosa link ocm-role --role-arn arn:aws:iam::123:role/ocm"}))
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal([]byte(template.Body), &body)).To(Succeed())
+		Expect(body["Metadata"]).To(HaveKey("ManualCommands"))
+		Expect(body["Parameters"]).To(HaveKeyWithValue("TagRedHatManaged", HaveKeyWithValue("Default", "true")))
+		provider := body["Resources"].(map[string]interface{})["OIDCProvider"].(map[string]interface{})
+		Expect(provider["Type"]).To(Equal("AWS::IAM::OIDCProvider"))
+		properties := provider["Properties"].(map[string]interface{})
+		Expect(properties["Url"]).To(Equal("https://oidc.example.com/abc"))
+		Expect(properties["ClientIdList"]).To(Equal([]interface{}{"openshift", "sts.amazonaws.com"}))
+		Expect(properties["ThumbprintList"]).To(Equal([]interface{}{"a1b2"}))
+	})
+
+	It("fails if a policy document is missing", func() {
+		commands := NewIAMCommandBuilder().
+			SetCommand(CreateRole).
+			AddParam(RoleName, "ocm-role").
+			AddParam(AssumeRolePolicyDocument, "file://missing.json").
+			Build()
+		_, err := CloudFormation(commands, TemplateParameters{ReadFile: readFile})
+		Expect(err).To(MatchError(ContainSubstring("missing.json")))
+	})
+})
@@ -26,14 +26,18 @@ const (
 	CreatePolicy                  Command = "create-policy"
 	DeletePolicy                  Command = "delete-policy"
 	CreatePolicyVersion           Command = "create-policy-version"
+	DeletePolicyVersion           Command = "delete-policy-version"
 	DeleteRolePolicy              Command = "delete-role-policy"
 	AttachRolePolicy              Command = "attach-role-policy"
 	DetachRolePolicy              Command = "detach-role-policy"
 	TagPolicy                     Command = "tag-policy"
+	UntagPolicy                   Command = "untag-policy"
 	TagRole                       Command = "tag-role"
 	CreateOpenIdConnectProvider   Command = "create-open-id-connect-provider"
 	DeleteOpenIdConnectProvider   Command = "delete-open-id-connect-provider"
 	DeleteRolePermissionsBoundary Command = "delete-role-permissions-boundary"
+	UpdateAssumeRolePolicy        Command = "update-assume-role-policy"
+	SetDefaultPolicyVersion       Command = "set-default-policy-version"
 	//S3Api
 	CreateBucket         Command = "create-bucket"
 	PutObject            Command = "put-object"
@@ -44,8 +48,10 @@ const (
 	Remove       Command = "rm"
 	RemoveBucket Command = "rb"
 	//SecretsManager
-	CreateSecret Command = "create-secret"
-	DeleteSecret Command = "delete-secret"
+	CreateSecret             Command = "create-secret"
+	DeleteSecret             Command = "delete-secret"
+	PutSecretValue           Command = "put-secret-value"
+	UpdateSecretVersionStage Command = "update-secret-version-stage"
 )
 
 type Param string
@@ -65,6 +71,10 @@ const (
 	ThumbprintList           Param = "thumbprint-list"
 	OpenIdConnectProviderArn Param = "open-id-connect-provider-arn"
 	SetAsDefault             Param = "set-as-default"
+	VersionId                Param = "version-id"
+	TagKeys                  Param = "tag-keys"
+	Query                    Param = "query"
+	Output                   Param = "output"
 
 	//S3
 	Bucket                         Param = "bucket"
@@ -78,11 +88,16 @@ const (
 	Policy                         Param = "policy"
 
 	//SecretsManager
-	Name         Param = "name"
-	SecretString Param = "secret-string"
-	Description  Param = "description"
-	SecretID     Param = "secret-id"
-	Recursive    Param = "recursive"
+	Name                Param = "name"
+	SecretString        Param = "secret-string"
+	Description         Param = "description"
+	SecretID            Param = "secret-id"
+	ClientRequestToken  Param = "client-request-token"
+	VersionStages       Param = "version-stages"
+	VersionStage        Param = "version-stage"
+	MoveToVersionId     Param = "move-to-version-id"
+	RemoveFromVersionId Param = "remove-from-version-id"
+	Recursive           Param = "recursive"
 )
 
 type Redirect string
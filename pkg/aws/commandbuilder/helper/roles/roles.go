@@ -2,6 +2,7 @@ package roles
 
 import (
 	"fmt"
+	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
@@ -59,6 +60,10 @@ type ManualCommandsForUpgradeOperatorRolePolicyInput struct {
 	PolicyName                               string
 	HasDetachPolicyCommandsForExpectedPolicy bool
 	OperatorRoleName                         string
+	// PrunedPolicyVersions are the versions of the existing policy deleted to make room for the new one.
+	PrunedPolicyVersions []string
+	// PolicyVersionTagValue is the value of the tag that records who created the new policy version.
+	PolicyVersionTagValue string
 }
 
 func ManualCommandsForUpgradeOperatorRolePolicy(input ManualCommandsForUpgradeOperatorRolePolicyInput) []string {
@@ -88,23 +93,9 @@ func ManualCommandsForUpgradeOperatorRolePolicy(input ManualCommandsForUpgradeOp
 				Build()
 			commands = append(commands, attachRolePolicy)
 		}
-		policyTags := map[string]string{
-			tags.OpenShiftVersion: input.DefaultPolicyVersion,
-		}
-
-		createPolicyVersion := awscb.NewIAMCommandBuilder().
-			SetCommand(awscb.CreatePolicyVersion).
-			AddParam(awscb.PolicyArn, input.PolicyARN).
-			AddParam(awscb.PolicyDocument, fmt.Sprintf("file://openshift_%s_policy.json", input.CredRequest)).
-			AddParamNoValue(awscb.SetAsDefault).
-			Build()
-
-		tagPolicy := awscb.NewIAMCommandBuilder().
-			SetCommand(awscb.TagPolicy).
-			AddTags(policyTags).
-			AddParam(awscb.PolicyArn, input.PolicyARN).
-			Build()
-		commands = append(commands, createPolicyVersion, tagPolicy)
+		commands = append(commands, ManualCommandsForPolicyVersion(input.PolicyARN,
+			fmt.Sprintf("file://openshift_%s_policy.json", input.CredRequest), input.DefaultPolicyVersion,
+			input.PrunedPolicyVersions, input.PolicyVersionTagValue)...)
 	}
 	return commands
 }
@@ -120,6 +111,10 @@ type ManualCommandsForUpgradeAccountRolePolicyInput struct {
 	PolicyARN                                string
 	HasInlinePolicy                          bool
 	HasDetachPolicyCommandsForExpectedPolicy bool
+	// PrunedPolicyVersions are the versions of the existing policy deleted to make room for the new one.
+	PrunedPolicyVersions []string
+	// PolicyVersionTagValue is the value of the tag that records who created the new policy version.
+	PolicyVersionTagValue string
 }
 
 func ManualCommandsForUpgradeAccountRolePolicy(input ManualCommandsForUpgradeAccountRolePolicyInput) []string {
@@ -167,23 +162,60 @@ func ManualCommandsForUpgradeAccountRolePolicy(input ManualCommandsForUpgradeAcc
 		if input.HasDetachPolicyCommandsForExpectedPolicy {
 			commands = append(commands, attachRolePolicy)
 		}
-		createPolicyVersion := awscb.NewIAMCommandBuilder().
-			SetCommand(awscb.CreatePolicyVersion).
-			AddParam(awscb.PolicyArn, input.PolicyARN).
-			AddParam(awscb.PolicyDocument, fmt.Sprintf("file://sts_%s_permission_policy.json", input.File)).
-			AddParamNoValue(awscb.SetAsDefault).
-			Build()
-
-		tagPolicies := awscb.NewIAMCommandBuilder().
-			SetCommand(awscb.TagPolicy).
-			AddTags(iamRoleTags).
-			AddParam(awscb.PolicyArn, input.PolicyARN).
-			Build()
-		commands = append(commands, createPolicyVersion, tagPolicies, tagRole)
+		commands = append(commands, ManualCommandsForPolicyVersion(input.PolicyARN,
+			fmt.Sprintf("file://sts_%s_permission_policy.json", input.File), input.DefaultPolicyVersion,
+			input.PrunedPolicyVersions, input.PolicyVersionTagValue)...)
+		commands = append(commands, tagRole)
 	}
 	return commands
 }
 
+// ManualCommandsForPolicyVersion returns the commands that make the document the default version of
+// the policy, like auto mode does: they delete the pruned versions and their tags, so that the IAM
+// limit of versions isn't exceeded, create the new version, and tag the policy with the OpenShift
+// version and, using the identifier of the new version, with the tag that records who created it.
+func ManualCommandsForPolicyVersion(policyARN string, document string, openShiftVersion string,
+	prunedVersions []string, versionTagValue string) []string {
+	commands := make([]string, 0)
+	prunedTags := make([]string, 0, len(prunedVersions))
+	for _, versionID := range prunedVersions {
+		commands = append(commands, awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.DeletePolicyVersion).
+			AddParam(awscb.PolicyArn, policyARN).
+			AddParam(awscb.VersionId, versionID).
+			Build())
+		prunedTags = append(prunedTags, tags.PolicyVersion+versionID)
+	}
+	if len(prunedTags) > 0 {
+		commands = append(commands, awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.UntagPolicy).
+			AddParam(awscb.PolicyArn, policyARN).
+			AddParam(awscb.TagKeys, strings.Join(prunedTags, " ")).
+			Build())
+	}
+
+	createPolicyVersion := awscb.NewIAMCommandBuilder().
+		SetCommand(awscb.CreatePolicyVersion).
+		AddParam(awscb.PolicyArn, policyARN).
+		AddParam(awscb.PolicyDocument, document).
+		AddParamNoValue(awscb.SetAsDefault).
+		AddParam(awscb.Query, "PolicyVersion.VersionId").
+		AddParam(awscb.Output, "text").
+		Build()
+	policyTags := map[string]string{
+		tags.OpenShiftVersion: openShiftVersion,
+	}
+	if versionTagValue != "" {
+		policyTags[tags.PolicyVersion+"${version_id}"] = versionTagValue
+	}
+	tagPolicy := awscb.NewIAMCommandBuilder().
+		SetCommand(awscb.TagPolicy).
+		AddTags(policyTags).
+		AddParam(awscb.PolicyArn, policyARN).
+		Build()
+	return append(commands, fmt.Sprintf("version_id=\"$(%s)\"", createPolicyVersion), tagPolicy)
+}
+
 type ManualCommandsForDetachRolePolicyInput struct {
 	RoleName  string
 	PolicyARN string
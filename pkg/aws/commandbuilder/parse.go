@@ -0,0 +1,143 @@
+package commandbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Invocation is a command generated by a CommandBuilder, parsed back so that it can be converted to
+// other formats. Commands that don't start with 'aws', like the 'rm' and 'rosa link' commands
+// mixed with the AWS ones in manual mode, have an empty service and keep their text in Raw.
+type Invocation struct {
+	Service Service
+	Command Command
+
+	// Params contains the values of the parameters. Parameters without value have an empty list.
+	Params map[Param][]string
+
+	// Values contains the values that aren't preceded by a parameter name.
+	Values []string
+
+	Redirect string
+	Raw      string
+}
+
+// paramsWithoutValue are the parameters added with AddParamNoValue, which must not take the next
+// word as their value.
+var paramsWithoutValue = map[Param]bool{
+	SetAsDefault: true,
+	Recursive:    true,
+}
+
+// Value returns the value of the given parameter, with multiple words separated by spaces.
+func (i *Invocation) Value(param Param) string {
+	return strings.Join(i.Params[param], " ")
+}
+
+// Has checks if the invocation contains the given parameter.
+func (i *Invocation) Has(param Param) bool {
+	_, ok := i.Params[param]
+	return ok
+}
+
+// Tags returns the tags of the invocation, as added with AddTags.
+func (i *Invocation) Tags() map[string]string {
+	result := map[string]string{}
+	for _, tag := range i.Params[Tags] {
+		key, value, found := strings.Cut(strings.TrimPrefix(tag, "Key="), ",Value=")
+		if found {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// SplitCommands splits text joined with JoinCommands into the individual commands, discarding
+// empty ones.
+func SplitCommands(text string) []string {
+	var result []string
+	for _, command := range strings.Split(text, "\n\n") {
+		command = strings.TrimSpace(command)
+		if command != "" {
+			result = append(result, command)
+		}
+	}
+	return result
+}
+
+// Parse parses a command generated by the Build method of a CommandBuilder.
+func Parse(command string) (*Invocation, error) {
+	words, err := splitWords(strings.ReplaceAll(command, ParamNewLineSeparator, " "))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse command '%s': %v", command, err)
+	}
+	result := &Invocation{
+		Params: map[Param][]string{},
+		Raw:    command,
+	}
+	if len(words) < 2 || words[0] != "aws" {
+		return result, nil
+	}
+	result.Service = Service(words[1])
+	words = words[2:]
+	if len(words) > 0 && !strings.HasPrefix(words[0], "--") {
+		result.Command = Command(words[0])
+		words = words[1:]
+	}
+	var current Param
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == string(FileRewrite) && i+1 < len(words):
+			result.Redirect = words[i+1]
+			i++
+		case strings.HasPrefix(word, "--"):
+			current = Param(strings.TrimPrefix(word, "--"))
+			result.Params[current] = []string{}
+			if paramsWithoutValue[current] {
+				current = ""
+			}
+		case current != "":
+			result.Params[current] = append(result.Params[current], word)
+		default:
+			result.Values = append(result.Values, word)
+		}
+	}
+	return result, nil
+}
+
+// splitWords splits the text in words separated by white space, like a shell does, removing the
+// single and double quotes that surround words containing spaces or special characters.
+func splitWords(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, c := range text {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
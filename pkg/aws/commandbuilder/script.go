@@ -0,0 +1,285 @@
+package commandbuilder
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Script converts commands joined with JoinCommands into a bash script that can be run again after
+// a failure: each command that creates a resource is skipped when the resource already exists. The
+// policy documents are embedded in the script and written to a temporary directory, and the script
+// contains a rollback function, run with the '--rollback' argument, that deletes the resources in
+// reverse order.
+func Script(text string, parameters TemplateParameters) (string, error) {
+	if parameters.ReadFile == nil {
+		parameters.ReadFile = os.ReadFile
+	}
+	converter := &scriptConverter{
+		parameters: parameters,
+		embedded:   map[string]bool{},
+	}
+	var invocations []*Invocation
+	for _, command := range SplitCommands(text) {
+		invocation, err := Parse(command)
+		if err != nil {
+			return "", err
+		}
+		invocations = append(invocations, invocation)
+	}
+	for _, invocation := range invocations {
+		err := converter.embed(invocation)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, invocation := range invocations {
+		converter.convert(invocation)
+	}
+	return converter.String(), nil
+}
+
+// documentParams are the parameters that reference policy documents, which are embedded in the
+// script instead of being read from the current directory.
+var documentParams = []Param{AssumeRolePolicyDocument, PolicyDocument, Policy}
+
+const scriptDocumentDelimiter = "ROSA_DOCUMENT"
+
+type scriptConverter struct {
+	parameters TemplateParameters
+
+	// documents contains the commands that write the embedded documents to the temporary
+	// directory, and embedded the names of the files.
+	documents []string
+	embedded  map[string]bool
+
+	main     []string
+	rollback []string
+
+	// callerIdentity indicates that the script needs the partition and the account identifier to
+	// calculate the ARNs of the resources.
+	callerIdentity bool
+}
+
+func (c *scriptConverter) embed(i *Invocation) error {
+	for _, param := range documentParams {
+		value := i.Value(param)
+		if !strings.HasPrefix(value, "file://") {
+			continue
+		}
+		name := fileName(value)
+		if c.embedded[name] {
+			continue
+		}
+		data, err := c.parameters.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("Failed to read policy document '%s': %v", name, err)
+		}
+		content := strings.TrimSuffix(string(data), "\n")
+		if strings.Contains(content, "\n"+scriptDocumentDelimiter+"\n") {
+			return fmt.Errorf("Policy document '%s' can't be embedded in a script", name)
+		}
+		c.embedded[name] = true
+		c.documents = append(c.documents, fmt.Sprintf("cat > \"${workdir}/%s\" <<'%s'\n%s\n%s",
+			name, scriptDocumentDelimiter, content, scriptDocumentDelimiter))
+	}
+	return nil
+}
+
+func (c *scriptConverter) convert(i *Invocation) {
+	command := c.command(i)
+	switch {
+	case i.Service == "" && strings.HasPrefix(i.Raw, "rm "):
+		files := strings.Fields(strings.TrimPrefix(i.Raw, "rm "))
+		var remaining []string
+		for _, file := range files {
+			if !c.embedded[fileName(file)] {
+				remaining = append(remaining, file)
+			}
+		}
+		// The embedded documents are removed with the temporary directory, and the rest of the
+		// files may have been removed by a previous run:
+		if len(remaining) > 0 {
+			c.main = append(c.main, fmt.Sprintf("rm -f %s", strings.Join(remaining, " ")))
+		}
+	case i.Service == IAM && i.Command == CreateRole:
+		check := fmt.Sprintf("aws iam get-role --role-name %s", shellQuote(i.Value(RoleName)))
+		resource := fmt.Sprintf("Role '%s'", i.Value(RoleName))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf("aws iam delete-role --role-name %s",
+			shellQuote(i.Value(RoleName))))
+	case i.Service == IAM && i.Command == CreatePolicy:
+		c.callerIdentity = true
+		path := i.Value(Path)
+		if path == "" {
+			path = "/"
+		}
+		arn := fmt.Sprintf("\"arn:${partition}:iam::${account_id}:policy%s%s\"", path, i.Value(PolicyName))
+		check := fmt.Sprintf("aws iam get-policy --policy-arn %s", arn)
+		resource := fmt.Sprintf("Policy '%s'", i.Value(PolicyName))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf("aws iam delete-policy --policy-arn %s", arn))
+	case i.Service == IAM && i.Command == AttachRolePolicy:
+		check := fmt.Sprintf("aws iam list-attached-role-policies --role-name %s "+
+			"--query \"AttachedPolicies[?PolicyArn=='%s'].PolicyArn\" --output text | grep -q .",
+			shellQuote(i.Value(RoleName)), i.Value(PolicyArn))
+		resource := fmt.Sprintf("Attachment of policy '%s' to role '%s'", i.Value(PolicyArn),
+			i.Value(RoleName))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf("aws iam detach-role-policy --role-name %s --policy-arn %s",
+			shellQuote(i.Value(RoleName)), shellQuote(i.Value(PolicyArn))))
+	case i.Service == IAM && i.Command == CreateOpenIdConnectProvider:
+		c.callerIdentity = true
+		arn := fmt.Sprintf("\"arn:${partition}:iam::${account_id}:oidc-provider/%s\"",
+			strings.TrimPrefix(i.Value(Url), "https://"))
+		check := fmt.Sprintf("aws iam get-open-id-connect-provider --open-id-connect-provider-arn %s", arn)
+		resource := fmt.Sprintf("OIDC provider '%s'", i.Value(Url))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf(
+			"aws iam delete-open-id-connect-provider --open-id-connect-provider-arn %s", arn))
+	case i.Service == S3Api && i.Command == CreateBucket:
+		check := fmt.Sprintf("aws s3api head-bucket --bucket %s", shellQuote(i.Value(Bucket)))
+		resource := fmt.Sprintf("Bucket '%s'", i.Value(Bucket))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf("aws s3api delete-bucket --bucket %s",
+			shellQuote(i.Value(Bucket))))
+	case i.Service == S3Api && i.Command == PutObject:
+		check := fmt.Sprintf("aws s3api head-object --bucket %s --key %s",
+			shellQuote(i.Value(Bucket)), shellQuote(i.Value(Key)))
+		resource := fmt.Sprintf("Object '%s' of bucket '%s'", i.Value(Key), i.Value(Bucket))
+		c.addGuarded(check, command, resource)
+		c.addRollback(resource, fmt.Sprintf("aws s3api delete-object --bucket %s --key %s",
+			shellQuote(i.Value(Bucket)), shellQuote(i.Value(Key))))
+	case i.Service == SM && i.Command == CreateSecret:
+		region := ""
+		if i.Has(Region) {
+			region = " --region " + shellQuote(i.Value(Region))
+		}
+		check := fmt.Sprintf("aws secretsmanager describe-secret --secret-id %s%s",
+			shellQuote(i.Value(Name)), region)
+		resource := fmt.Sprintf("Secret '%s'", i.Value(Name))
+		c.addGuarded(check, command, resource)
+		// Without a recovery window the secret is deleted immediately, otherwise the check would
+		// find it pending deletion and skip its creation when the script is run again:
+		c.addRollback(resource, fmt.Sprintf("aws secretsmanager delete-secret --secret-id %s "+
+			"--force-delete-without-recovery%s", shellQuote(i.Value(Name)), region))
+	case i.Service == "":
+		// Commands like 'rosa link ocm-role' can be run again, and aren't rolled back.
+		c.main = append(c.main, command)
+		c.rollback = append([]string{fmt.Sprintf("# Not rolled back: %s",
+			strings.ReplaceAll(command, ParamNewLineSeparator+"\t", " "))}, c.rollback...)
+	default:
+		// Commands that tag resources or configure buckets can be run again, and their effects
+		// are removed with the resources.
+		c.main = append(c.main, command)
+	}
+}
+
+// command returns the text of the command, reading the embedded documents from the temporary
+// directory.
+func (c *scriptConverter) command(i *Invocation) string {
+	command := i.Raw
+	for name := range c.embedded {
+		reference := regexp.MustCompile(`file://(\./)?` + regexp.QuoteMeta(name) + `(\s|$)`)
+		command = reference.ReplaceAllString(command, `"file://$${workdir}/`+name+`"${2}`)
+	}
+	return command
+}
+
+// addGuarded adds a command that is only run when the check fails, because the resource doesn't
+// exist yet, and that records the resource in the state file once it is created.
+func (c *scriptConverter) addGuarded(check string, command string, resource string) {
+	c.main = append(c.main, fmt.Sprintf("if ! %s >/dev/null 2>&1; then\n%s\n  record %s\nelse\n  echo %s\nfi",
+		check, indent(command), shellDoubleQuote(resource),
+		shellDoubleQuote(resource+" already exists, skipping")))
+}
+
+// addRollback adds a command that deletes a resource if the state file records that the script
+// created it, so that the resources that existed before aren't deleted. A failed command doesn't
+// stop the rollback of the rest of the resources. Rollback commands are run in the reverse order of
+// the commands that create the resources.
+func (c *scriptConverter) addRollback(resource string, command string) {
+	quoted := shellDoubleQuote(resource)
+	c.rollback = append([]string{fmt.Sprintf(
+		"if created %s; then\n  if %s; then\n    forget %s\n  else\n    failed=1\n  fi\nfi",
+		quoted, command, quoted)}, c.rollback...)
+}
+
+func (c *scriptConverter) String() string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n" +
+		"#\n" +
+		"# The commands of this script are skipped when the resources they create already exist, so it\n" +
+		"# can be run again after a failure. The resources that it creates are recorded in a state file,\n" +
+		"# '<script>.state' or the one given in the 'ROSA_SCRIPT_STATE' environment variable, and running\n" +
+		"# it with '--rollback' deletes only those, not the ones that already existed.\n\n" +
+		"set -euo pipefail\n\n" +
+		"state=\"${ROSA_SCRIPT_STATE:-${0}.state}\"\n" +
+		"workdir=\"$(mktemp -d)\"\n" +
+		"trap 'rm -rf \"${workdir}\"' EXIT\n\n" +
+		"record() {\n" +
+		"  echo \"$1\" >> \"${state}\"\n" +
+		"}\n\n" +
+		"created() {\n" +
+		"  [ -f \"${state}\" ] && grep -qxF \"$1\" \"${state}\"\n" +
+		"}\n\n" +
+		"forget() {\n" +
+		"  grep -vxF \"$1\" \"${state}\" > \"${state}.tmp\" || true\n" +
+		"  mv \"${state}.tmp\" \"${state}\"\n" +
+		"}\n")
+	if c.callerIdentity {
+		b.WriteString("\ncaller_arn=\"$(aws sts get-caller-identity --query Arn --output text)\"\n" +
+			"partition=\"$(echo \"${caller_arn}\" | cut -d: -f2)\"\n" +
+			"account_id=\"$(echo \"${caller_arn}\" | cut -d: -f5)\"\n")
+	}
+	for _, document := range c.documents {
+		fmt.Fprintf(&b, "\n%s\n", document)
+	}
+	b.WriteString("\nrollback() {\n" +
+		"  failed=0\n")
+	for _, command := range c.rollback {
+		fmt.Fprintf(&b, "\n%s\n", indent(command))
+	}
+	b.WriteString("\n" +
+		"  if [ \"${failed}\" -ne 0 ]; then\n" +
+		"    echo \"Some resources couldn't be deleted, run the rollback again to retry\" >&2\n" +
+		"    return 1\n" +
+		"  fi\n" +
+		"}\n\n" +
+		"if [ \"${1:-}\" = \"--rollback\" ]; then\n" +
+		"  rollback\n" +
+		"  exit 0\n" +
+		"fi\n")
+	for _, command := range c.main {
+		fmt.Fprintf(&b, "\n%s\n", command)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func indent(text string) string {
+	lines := strings.Split(text, "\n")
+	for n, line := range lines {
+		if line != "" {
+			lines[n] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes the value so that the shell passes it as a single word.
+func shellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shellDoubleQuote quotes the value with double quotes, which reads better than single quotes for
+// messages that contain them.
+func shellDoubleQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return `"` + replacer.Replace(value) + `"`
+}
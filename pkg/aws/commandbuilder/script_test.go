@@ -0,0 +1,100 @@
+package commandbuilder_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+var _ = Describe("Script", func() {
+	readFile := func(name string) ([]byte, error) {
+		return []byte(`{"Version": "2012-10-17"}` + "\n"), nil
+	}
+
+	It("guards the creation of resources and generates the rollback", func() {
+		commands := JoinCommands([]string{
+			NewIAMCommandBuilder().
+				SetCommand(CreateRole).
+				AddParam(RoleName, "Installer-Role").
+				AddParam(AssumeRolePolicyDocument, "file://sts_installer_trust_policy.json").
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(CreatePolicy).
+				AddParam(PolicyName, "Installer-Policy").
+				AddParam(PolicyDocument, "file://sts_installer_permission_policy.json").
+				AddParam(Path, "/rosa/").
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(AttachRolePolicy).
+				AddParam(RoleName, "Installer-Role").
+				AddParam(PolicyArn, "arn:aws:iam::123:policy/rosa/Installer-Policy").
+				Build(),
+			"rm sts_installer_trust_policy.json private.key",
+			"rosa link ocm-role --role-arn arn:aws:iam::123:role/ocm",
+		})
+		script, err := Script(commands, TemplateParameters{ReadFile: readFile})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(script).To(HavePrefix("#!/usr/bin/env bash\n"))
+		Expect(script).To(ContainSubstring("set -euo pipefail\n"))
+		Expect(script).To(ContainSubstring(
+			"cat > \"${workdir}/sts_installer_trust_policy.json\" <<'ROSA_DOCUMENT'\n" +
+				"{\"Version\": \"2012-10-17\"}\n" +
+				"ROSA_DOCUMENT\n"))
+		Expect(script).To(ContainSubstring(
+			"if ! aws iam get-role --role-name Installer-Role >/dev/null 2>&1; then\n" +
+				"  aws iam create-role \\\n" +
+				"  \t--assume-role-policy-document \"file://${workdir}/sts_installer_trust_policy.json\" \\\n" +
+				"  \t--role-name Installer-Role\n" +
+				"  record \"Role 'Installer-Role'\"\n" +
+				"else\n" +
+				"  echo \"Role 'Installer-Role' already exists, skipping\"\n" +
+				"fi\n"))
+		Expect(script).To(ContainSubstring(
+			"if ! aws iam get-policy --policy-arn " +
+				"\"arn:${partition}:iam::${account_id}:policy/rosa/Installer-Policy\" >/dev/null 2>&1; then\n"))
+		Expect(script).ToNot(ContainSubstring("file://sts_"))
+		Expect(script).To(ContainSubstring("\nrm -f private.key\n"))
+
+		// The resources are deleted in reverse order:
+		start := strings.Index(script, "rollback() {")
+		rollback := script[start : start+strings.Index(script[start:], "\n}\n")]
+		detach := strings.Index(rollback, "aws iam detach-role-policy --role-name Installer-Role")
+		deletePolicy := strings.Index(rollback, "aws iam delete-policy")
+		deleteRole := strings.Index(rollback, "aws iam delete-role --role-name Installer-Role")
+		Expect(detach).To(BeNumerically(">", 0))
+		Expect(detach).To(BeNumerically("<", deletePolicy))
+		Expect(deletePolicy).To(BeNumerically("<", deleteRole))
+		Expect(rollback).To(ContainSubstring("# Not rolled back: rosa link ocm-role"))
+
+		// Only the resources recorded in the state file are deleted, and failures don't stop the
+		// rollback:
+		Expect(rollback).To(ContainSubstring(
+			"  if created \"Role 'Installer-Role'\"; then\n" +
+				"    if aws iam delete-role --role-name Installer-Role; then\n" +
+				"      forget \"Role 'Installer-Role'\"\n" +
+				"    else\n" +
+				"      failed=1\n" +
+				"    fi\n" +
+				"  fi\n"))
+		Expect(script).To(ContainSubstring("\n  record \"Attachment of policy " +
+			"'arn:aws:iam::123:policy/rosa/Installer-Policy' to role 'Installer-Role'\"\n"))
+	})
+
+	It("deletes the secrets of the rollback without a recovery window", func() {
+		commands := NewSecretsManagerCommandBuilder().
+			SetCommand(CreateSecret).
+			AddParam(Name, "rosa-private-key-oidc-a1b2").
+			AddParam(SecretString, "file://rosa-private-key-oidc-a1b2.key").
+			AddParam(Region, "us-east-1").
+			Build()
+		script, err := Script(commands, TemplateParameters{ReadFile: readFile})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(script).To(ContainSubstring(
+			"if aws secretsmanager delete-secret --secret-id rosa-private-key-oidc-a1b2 " +
+				"--force-delete-without-recovery --region us-east-1; then\n"))
+	})
+})
@@ -0,0 +1,381 @@
+package commandbuilder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Terraform converts commands joined with JoinCommands into the equivalent Terraform resources.
+// Policy documents and other files referenced by the commands are read with the 'file' function
+// from the current directory, so the files saved by manual mode must be kept next to the generated
+// configuration. Commands that have no equivalent resource, like 'rosa link ocm-role', are added as
+// comments at the end, while commands that only remove temporary files are dropped.
+func Terraform(text string) (string, error) {
+	var invocations []*Invocation
+	for _, command := range SplitCommands(text) {
+		invocation, err := Parse(command)
+		if err != nil {
+			return "", err
+		}
+		invocations = append(invocations, invocation)
+	}
+
+	converter := &terraformConverter{
+		labels:     map[string]map[string]string{},
+		usedLabels: map[string]bool{},
+		bucketTags: map[string]map[string]string{},
+	}
+	converter.index(invocations)
+	for _, invocation := range invocations {
+		converter.convert(invocation)
+	}
+	return converter.String(), nil
+}
+
+const (
+	tfRole                    = "aws_iam_role"
+	tfPolicy                  = "aws_iam_policy"
+	tfRolePolicyAttachment    = "aws_iam_role_policy_attachment"
+	tfOpenIDConnectProvider   = "aws_iam_openid_connect_provider"
+	tfBucket                  = "aws_s3_bucket"
+	tfBucketPublicAccessBlock = "aws_s3_bucket_public_access_block"
+	tfBucketPolicy            = "aws_s3_bucket_policy"
+	tfObject                  = "aws_s3_object"
+	tfSecret                  = "aws_secretsmanager_secret"
+)
+
+type terraformConverter struct {
+	blocks  []*terraformBlock
+	regions []string
+	manual  []string
+
+	// secrets contains the commands that store the values of the secrets, which are kept out of
+	// the configuration so that they don't end up in the Terraform state.
+	secrets []string
+
+	// labels contains the labels of the resources created by the commands, indexed by resource
+	// type and name, so that other resources can reference them instead of repeating names.
+	labels     map[string]map[string]string
+	usedLabels map[string]bool
+
+	// bucketTags contains the tags added to buckets with separate 'put-bucket-tagging' commands.
+	bucketTags map[string]map[string]string
+}
+
+type terraformBlock struct {
+	kind       string
+	label      string
+	attributes [][2]string
+	tags       map[string]string
+}
+
+// index assigns labels to the resources created by the commands before they are converted, so
+// that references work regardless of the order of the commands.
+func (c *terraformConverter) index(invocations []*Invocation) {
+	for _, i := range invocations {
+		switch {
+		case i.Service == IAM && i.Command == CreateRole:
+			c.assignLabel(tfRole, i.Value(RoleName))
+		case i.Service == IAM && i.Command == CreatePolicy:
+			c.assignLabel(tfPolicy, i.Value(PolicyName))
+		case i.Service == S3Api && i.Command == CreateBucket:
+			c.assignLabel(tfBucket, i.Value(Bucket))
+		case i.Service == S3Api && i.Command == PutBucketTagging:
+			c.bucketTags[i.Value(Bucket)] = parseTagSet(i.Value(Tagging))
+		case i.Service == SM && i.Command == CreateSecret:
+			c.assignLabel(tfSecret, i.Value(Name))
+		}
+	}
+}
+
+func (c *terraformConverter) convert(i *Invocation) {
+	if i.Service != "" && i.Has(Region) {
+		c.addRegion(i.Value(Region))
+	}
+	switch {
+	case i.Service == "" && strings.HasPrefix(i.Raw, "rm "):
+		// The files are read by Terraform, so they must not be removed.
+	case i.Service == IAM && i.Command == CreateRole:
+		c.add(tfRole, c.labels[tfRole][i.Value(RoleName)], i.Tags(),
+			"name", quote(i.Value(RoleName)),
+			"path", optionalQuote(i.Value(Path)),
+			"assume_role_policy", fileFunction(i.Value(AssumeRolePolicyDocument)),
+			"permissions_boundary", optionalQuote(i.Value(PermissionsBoundary)),
+		)
+	case i.Service == IAM && i.Command == CreatePolicy:
+		c.add(tfPolicy, c.labels[tfPolicy][i.Value(PolicyName)], i.Tags(),
+			"name", quote(i.Value(PolicyName)),
+			"path", optionalQuote(i.Value(Path)),
+			"policy", fileFunction(i.Value(PolicyDocument)),
+		)
+	case i.Service == IAM && i.Command == AttachRolePolicy:
+		roleName := i.Value(RoleName)
+		policyARN := i.Value(PolicyArn)
+		role := quote(roleName)
+		if label, ok := c.labels[tfRole][roleName]; ok {
+			role = fmt.Sprintf("%s.%s.name", tfRole, label)
+		}
+		policyName := policyARN[strings.LastIndex(policyARN, "/")+1:]
+		policy := quote(policyARN)
+		if label, ok := c.labels[tfPolicy][policyName]; ok {
+			policy = fmt.Sprintf("%s.%s.arn", tfPolicy, label)
+		}
+		c.add(tfRolePolicyAttachment, c.newLabel(tfRolePolicyAttachment, roleName+"_"+policyName), nil,
+			"role", role,
+			"policy_arn", policy,
+		)
+	case i.Service == IAM && i.Command == CreateOpenIdConnectProvider:
+		url := i.Value(Url)
+		c.add(tfOpenIDConnectProvider, c.newLabel(tfOpenIDConnectProvider, strings.TrimPrefix(url, "https://")),
+			i.Tags(),
+			"url", quote(url),
+			"client_id_list", quoteList(i.Params[ClientIdList]),
+			"thumbprint_list", quoteList(i.Params[ThumbprintList]),
+		)
+	case i.Service == S3Api && i.Command == CreateBucket:
+		c.add(tfBucket, c.labels[tfBucket][i.Value(Bucket)], c.bucketTags[i.Value(Bucket)],
+			"bucket", quote(i.Value(Bucket)),
+		)
+	case i.Service == S3Api && i.Command == PutBucketTagging:
+		// The tags are added to the bucket resource.
+	case i.Service == S3Api && i.Command == PutPublicAccessBlock:
+		attributes := []string{"bucket", c.bucketReference(i.Value(Bucket))}
+		for _, setting := range strings.Split(i.Value(PublicAccessBlockConfiguration), ",") {
+			name, value, _ := strings.Cut(setting, "=")
+			attributes = append(attributes, snakeCase(name), value)
+		}
+		c.add(tfBucketPublicAccessBlock, c.newLabel(tfBucketPublicAccessBlock, i.Value(Bucket)), nil,
+			attributes...)
+	case i.Service == S3Api && i.Command == PutBucketPolicy:
+		c.add(tfBucketPolicy, c.newLabel(tfBucketPolicy, i.Value(Bucket)), nil,
+			"bucket", c.bucketReference(i.Value(Bucket)),
+			"policy", fileFunction(i.Value(Policy)),
+		)
+	case i.Service == S3Api && i.Command == PutObject:
+		key := i.Value(Key)
+		c.add(tfObject, c.newLabel(tfObject, i.Value(Bucket)+"_"+key), parseQueryTags(i.Value(Tagging)),
+			"bucket", c.bucketReference(i.Value(Bucket)),
+			"key", quote(key),
+			"source", quote(fileName(i.Value(Body))),
+		)
+	case i.Service == SM && i.Command == CreateSecret:
+		name := i.Value(Name)
+		label := c.labels[tfSecret][name]
+		c.add(tfSecret, label, i.Tags(),
+			"name", quote(name),
+			"description", optionalQuote(i.Value(Description)),
+		)
+		// The value is stored with a separate command, as a secret version resource would copy the
+		// private key into the Terraform state:
+		c.secrets = append(c.secrets, NewSecretsManagerCommandBuilder().
+			SetCommand(PutSecretValue).
+			AddParam(SecretID, name).
+			AddParam(SecretString, i.Value(SecretString)).
+			Build())
+	default:
+		c.manual = append(c.manual, i.Raw)
+	}
+}
+
+func (c *terraformConverter) bucketReference(bucket string) string {
+	if label, ok := c.labels[tfBucket][bucket]; ok {
+		return fmt.Sprintf("%s.%s.id", tfBucket, label)
+	}
+	return quote(bucket)
+}
+
+func (c *terraformConverter) addRegion(region string) {
+	for _, existing := range c.regions {
+		if existing == region {
+			return
+		}
+	}
+	c.regions = append(c.regions, region)
+}
+
+// add adds a resource block. The attributes are pairs of names and expressions, and attributes
+// with empty expressions are omitted.
+func (c *terraformConverter) add(kind string, label string, tags map[string]string, attributes ...string) {
+	block := &terraformBlock{
+		kind:  kind,
+		label: label,
+		tags:  tags,
+	}
+	for j := 0; j+1 < len(attributes); j += 2 {
+		if attributes[j+1] != "" {
+			block.attributes = append(block.attributes, [2]string{attributes[j], attributes[j+1]})
+		}
+	}
+	c.blocks = append(c.blocks, block)
+}
+
+func (c *terraformConverter) assignLabel(kind string, name string) {
+	if c.labels[kind] == nil {
+		c.labels[kind] = map[string]string{}
+	}
+	if _, ok := c.labels[kind][name]; !ok {
+		c.labels[kind][name] = c.newLabel(kind, name)
+	}
+}
+
+var invalidLabelCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// newLabel returns a label derived from the given name that isn't used yet by another resource of
+// the same type.
+func (c *terraformConverter) newLabel(kind string, name string) string {
+	base := strings.Trim(invalidLabelCharacters.ReplaceAllString(name, "_"), "_")
+	if base == "" || !unicode.IsLetter(rune(base[0])) {
+		base = "_" + base
+	}
+	label := base
+	for n := 2; c.usedLabels[kind+"."+label]; n++ {
+		label = fmt.Sprintf("%s_%d", base, n)
+	}
+	c.usedLabels[kind+"."+label] = true
+	return label
+}
+
+func (c *terraformConverter) String() string {
+	var b strings.Builder
+	for _, region := range c.regions {
+		fmt.Fprintf(&b, "# The AWS provider must use region '%s'.\n\n", region)
+	}
+	for n, block := range c.blocks {
+		if n > 0 {
+			b.WriteString("\n")
+		}
+		block.write(&b)
+	}
+	if len(c.secrets) > 0 {
+		b.WriteString("\n# The values of the secrets aren't part of the configuration, so that they aren't " +
+			"saved in the\n# Terraform state, run the following commands to store them after applying it:\n")
+		writeComment(&b, c.secrets)
+	}
+	if len(c.manual) > 0 {
+		b.WriteString("\n# The following commands have no Terraform equivalent, run them after applying " +
+			"the configuration:\n")
+		writeComment(&b, c.manual)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeComment(b *strings.Builder, commands []string) {
+	for _, command := range commands {
+		b.WriteString("#\n")
+		for _, line := range strings.Split(command, "\n") {
+			fmt.Fprintf(b, "#   %s\n", strings.TrimSpace(line))
+		}
+	}
+}
+
+// write writes the block with the attributes aligned like 'terraform fmt' does.
+func (t *terraformBlock) write(b *strings.Builder) {
+	fmt.Fprintf(b, "resource %s %s {\n", quote(t.kind), quote(t.label))
+	width := 0
+	for _, attribute := range t.attributes {
+		if len(attribute[0]) > width {
+			width = len(attribute[0])
+		}
+	}
+	for _, attribute := range t.attributes {
+		fmt.Fprintf(b, "  %-*s = %s\n", width, attribute[0], attribute[1])
+	}
+	if len(t.tags) > 0 {
+		if len(t.attributes) > 0 {
+			b.WriteString("\n")
+		}
+		keys := make([]string, 0, len(t.tags))
+		width = 0
+		for key := range t.tags {
+			keys = append(keys, key)
+			if len(quote(key)) > width {
+				width = len(quote(key))
+			}
+		}
+		sort.Strings(keys)
+		b.WriteString("  tags = {\n")
+		for _, key := range keys {
+			fmt.Fprintf(b, "    %-*s = %s\n", width, quote(key), quote(t.tags[key]))
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+}
+
+// quote returns the value as a Terraform string literal, escaping the template sequences.
+func quote(value string) string {
+	quoted := fmt.Sprintf("%q", value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+func optionalQuote(value string) string {
+	if value == "" {
+		return ""
+	}
+	return quote(value)
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// fileName returns the name of the file referenced by a parameter like 'file://policy.json'.
+func fileName(value string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(value, "file://"), "./")
+}
+
+func fileFunction(value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("file(%s)", quote(fileName(value)))
+}
+
+// parseTagSet parses the tags of a bucket in the 'TagSet=[{Key=k,Value=v},...]' syntax.
+func parseTagSet(value string) map[string]string {
+	result := map[string]string{}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "TagSet=["), "]")
+	for _, tag := range strings.Split(value, "},{") {
+		tag = strings.Trim(tag, "{}")
+		key, tagValue, found := strings.Cut(strings.TrimPrefix(tag, "Key="), ",Value=")
+		if found {
+			result[key] = tagValue
+		}
+	}
+	return result
+}
+
+// parseQueryTags parses the tags of an object in the 'k1=v1&k2=v2' syntax.
+func parseQueryTags(value string) map[string]string {
+	result := map[string]string{}
+	for _, tag := range strings.Split(value, "&") {
+		key, tagValue, found := strings.Cut(tag, "=")
+		if found {
+			result[key] = tagValue
+		}
+	}
+	return result
+}
+
+// snakeCase converts a name like 'BlockPublicAcls' to 'block_public_acls'.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, c := range name {
+		if unicode.IsUpper(c) {
+			if i > 0 {
+				b.WriteRune('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
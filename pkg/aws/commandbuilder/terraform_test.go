@@ -0,0 +1,138 @@
+package commandbuilder_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+var _ = Describe("Terraform", func() {
+	It("parses the commands generated by the builder", func() {
+		invocation, err := Parse(NewSecretsManagerCommandBuilder().
+			SetCommand(CreateSecret).
+			AddParam(Name, "rosa-private-key-oidc-a1b2").
+			AddParam(Description, "\"Secret for oidc-a1b2\"").
+			AddTags(map[string]string{"red-hat-managed": "true"}).
+			Build())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(invocation.Service).To(Equal(SM))
+		Expect(invocation.Command).To(Equal(CreateSecret))
+		Expect(invocation.Value(Description)).To(Equal("Secret for oidc-a1b2"))
+		Expect(invocation.Tags()).To(Equal(map[string]string{"red-hat-managed": "true"}))
+	})
+
+	It("converts roles, policies and attachments with references", func() {
+		commands := JoinCommands([]string{
+			NewIAMCommandBuilder().
+				SetCommand(CreateRole).
+				AddParam(RoleName, "Installer-Role").
+				AddParam(AssumeRolePolicyDocument, "file://sts_installer_trust_policy.json").
+				AddParam(Path, "/").
+				AddTags(map[string]string{"rosa_role_prefix": "ManagedOpenShift"}).
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(CreatePolicy).
+				AddParam(PolicyName, "Installer-Policy").
+				AddParam(PolicyDocument, "file://sts_installer_permission_policy.json").
+				Build(),
+			NewIAMCommandBuilder().
+				SetCommand(AttachRolePolicy).
+				AddParam(RoleName, "Installer-Role").
+				AddParam(PolicyArn, "arn:aws:iam::123456789012:policy/Installer-Policy").
+				Build(),
+			"rosa link ocm-role --role-arn arn:aws:iam::123456789012:role/ManagedOpenShift-OCM-Role",
+		})
+		text, err := Terraform(commands)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal(`resource "aws_iam_role" "Installer-Role" {
+  name               = "Installer-Role"
+  path               = "/"
+  assume_role_policy = file("sts_installer_trust_policy.json")
+
+  tags = {
+    "rosa_role_prefix" = "ManagedOpenShift"
+  }
+}
+
+resource "aws_iam_policy" "Installer-Policy" {
+  name   = "Installer-Policy"
+  policy = file("sts_installer_permission_policy.json")
+}
+
+resource "aws_iam_role_policy_attachment" "Installer-Role_Installer-Policy" {
+  role       = aws_iam_role.Installer-Role.name
+  policy_arn = aws_iam_policy.Installer-Policy.arn
+}
+
+# The following commands have no Terraform equivalent, run them after applying the configuration:
+#
+#   rosa link ocm-role --role-arn arn:aws:iam::123456789012:role/ManagedOpenShift-OCM-Role`))
+	})
+
+	It("converts the OIDC configuration bucket and secret", func() {
+		commands := JoinCommands([]string{
+			NewS3ApiCommandBuilder().
+				SetCommand(CreateBucket).
+				AddParam(Bucket, "oidc-a1b2").
+				AddParam(Region, "us-east-1").
+				Build(),
+			NewS3ApiCommandBuilder().
+				SetCommand(PutBucketTagging).
+				AddParam(Bucket, "oidc-a1b2").
+				AddParam(Tagging, "'TagSet=[{Key=red-hat-managed,Value=true}]'").
+				Build(),
+			NewS3ApiCommandBuilder().
+				SetCommand(PutPublicAccessBlock).
+				AddParam(Bucket, "oidc-a1b2").
+				AddParam(PublicAccessBlockConfiguration, "BlockPublicAcls=true,BlockPublicPolicy=false").
+				Build(),
+			NewS3ApiCommandBuilder().
+				SetCommand(PutObject).
+				AddParam(Body, "./jwks-oidc-a1b2.json").
+				AddParam(Bucket, "oidc-a1b2").
+				AddParam(Key, "keys.json").
+				Build(),
+			"rm jwks-oidc-a1b2.json",
+			NewSecretsManagerCommandBuilder().
+				SetCommand(CreateSecret).
+				AddParam(Name, "rosa-private-key-oidc-a1b2").
+				AddParam(SecretString, "file://rosa-private-key-oidc-a1b2.key").
+				Build(),
+		})
+		text, err := Terraform(commands)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal(`# The AWS provider must use region 'us-east-1'.
+
+resource "aws_s3_bucket" "oidc-a1b2" {
+  bucket = "oidc-a1b2"
+
+  tags = {
+    "red-hat-managed" = "true"
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "oidc-a1b2" {
+  bucket              = aws_s3_bucket.oidc-a1b2.id
+  block_public_acls   = true
+  block_public_policy = false
+}
+
+resource "aws_s3_object" "oidc-a1b2_keys_json" {
+  bucket = aws_s3_bucket.oidc-a1b2.id
+  key    = "keys.json"
+  source = "jwks-oidc-a1b2.json"
+}
+
+resource "aws_secretsmanager_secret" "rosa-private-key-oidc-a1b2" {
+  name = "rosa-private-key-oidc-a1b2"
+}
+
+# The values of the secrets aren't part of the configuration, so that they aren't saved in the
+# Terraform state, run the following commands to store them after applying it:
+#
+#   aws secretsmanager put-secret-value \
+#   --secret-id rosa-private-key-oidc-a1b2 \
+#   --secret-string file://rosa-private-key-oidc-a1b2.key`))
+	})
+})
@@ -18,19 +18,42 @@ package aws
 
 import (
 	"fmt"
-	"github.com/openshift/rosa/pkg/arguments"
+
 	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
 )
 
 var mode string
+var outputFormat string
 
 const (
 	ModeAuto   = "auto"
 	ModeManual = "manual"
+
+	// ModeCloudFormation creates or updates a CloudFormation stack with the resources instead of
+	// creating them directly. It is only accepted by the commands that call AddCloudFormationMode
+	// and pass it to GetMode.
+	ModeCloudFormation = "cloudformation"
 )
 
 var Modes = []string{ModeAuto, ModeManual}
 
+const (
+	OutputFormatCLI            = "cli"
+	OutputFormatTerraform      = "terraform"
+	OutputFormatCloudFormation = "cloudformation"
+	OutputFormatScript         = "script"
+)
+
+var OutputFormats = []string{
+	OutputFormatCLI,
+	OutputFormatTerraform,
+	OutputFormatCloudFormation,
+	OutputFormatScript,
+}
+
 func AddModeFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(
 		&mode,
@@ -44,20 +67,114 @@ func AddModeFlag(cmd *cobra.Command) {
 	cmd.RegisterFlagCompletionFunc("mode", modeCompletion)
 }
 
+// AddCloudFormationMode documents the CloudFormation mode in the mode flag, which must have already
+// been added with AddModeFlag.
+func AddCloudFormationMode(cmd *cobra.Command) {
+	flag := cmd.Flags().Lookup("mode")
+	flag.Usage += "\n\n" +
+		"cloudformation: A CloudFormation stack containing the resources will be created or updated " +
+		"using the current AWS account"
+	cmd.Flags().SetAnnotation("mode", cloudFormationAnnotation, []string{"true"})
+}
+
+const cloudFormationAnnotation = "cloudformation"
+
 func SetModeKey(key string) {
 	mode = key
 }
 
-func GetMode() (string, error) {
+// GetMode returns the mode selected with the mode flag, or an empty string if it wasn't used. The
+// extra modes are the modes that the command accepts in addition to the common ones. An output
+// format other than the AWS CLI commands implies the manual mode, so it isn't asked for.
+func GetMode(extraModes ...string) (string, error) {
+	if outputFormat != "" && !arguments.IsValidMode(OutputFormats, outputFormat) {
+		return "", fmt.Errorf("Invalid output format. Allowed values are %s", OutputFormats)
+	}
+	if outputFormat != "" && outputFormat != OutputFormatCLI {
+		if mode != "" && mode != ModeManual {
+			return "", fmt.Errorf("Output format '%s' can only be used with '--mode %s'", outputFormat,
+				ModeManual)
+		}
+		return ModeManual, nil
+	}
 	if mode == "" {
 		return "", nil
 	}
+	if arguments.IsValidMode(extraModes, mode) {
+		return mode, nil
+	}
 	if !arguments.IsValidMode(Modes, mode) {
-		return "", fmt.Errorf("Invalid mode. Allowed values are %s", Modes)
+		return "", fmt.Errorf("Invalid mode. Allowed values are %s", append(Modes, extraModes...))
 	}
 	return mode, nil
 }
 
+// AddOutputFormatFlag adds the flag that selects the format of the commands generated in manual
+// mode.
+func AddOutputFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&outputFormat,
+		"output-format",
+		OutputFormatCLI,
+		"Format of the output of manual mode, formats other than 'cli' imply '--mode manual'. "+
+			"Valid options are:\n"+
+			"cli: Commands of the AWS command line tool\n\n"+
+			"terraform: Terraform resources that create the same AWS resources, except for the values "+
+			"of secrets, which are stored with separate commands so that they aren't saved in the state\n\n"+
+			"cloudformation: CloudFormation template that creates the same AWS resources\n\n"+
+			"script: Shell script that skips the resources that already exist, so it can be run again, "+
+			"and that deletes the ones it created when run with '--rollback'",
+	)
+	cmd.RegisterFlagCompletionFunc("output-format", outputFormatCompletion)
+}
+
+func outputFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string,
+	cobra.ShellCompDirective) {
+	return OutputFormats, cobra.ShellCompDirectiveDefault
+}
+
+// FormatCommands converts the commands generated in manual mode, joined with JoinCommands, to the
+// output format selected with the '--output-format' flag. The parameters are the values that
+// CloudFormation templates expose as template parameters.
+func FormatCommands(commands string, parameters awscb.TemplateParameters) (string, error) {
+	switch outputFormat {
+	case "", OutputFormatCLI:
+		return commands, nil
+	case OutputFormatTerraform:
+		return awscb.Terraform(commands)
+	case OutputFormatCloudFormation:
+		template, err := awscb.CloudFormation(commands, parameters)
+		if err != nil {
+			return "", err
+		}
+		return template.Body, nil
+	case OutputFormatScript:
+		return awscb.Script(commands, parameters)
+	default:
+		return "", fmt.Errorf("Invalid output format '%s'. Allowed values are %s", outputFormat, OutputFormats)
+	}
+}
+
+// ManualInstructions returns the message printed before the output of manual mode, describing what
+// it is used for.
+func ManualInstructions(purpose string) string {
+	switch outputFormat {
+	case OutputFormatTerraform:
+		return fmt.Sprintf("Apply the following Terraform configuration to %s:\n", purpose)
+	case OutputFormatCloudFormation:
+		return fmt.Sprintf("Deploy the following CloudFormation template to %s, and then run the "+
+			"commands listed in its 'ManualCommands' metadata, if any:\n", purpose)
+	case OutputFormatScript:
+		return fmt.Sprintf("Save the following script and run it with bash to %s, it can be run again "+
+			"if it fails, or with '--rollback' to delete the resources that it created:\n", purpose)
+	}
+	return fmt.Sprintf("Run the following commands to %s:\n", purpose)
+}
+
 func modeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	flag := cmd.Flags().Lookup("mode")
+	if flag != nil && len(flag.Annotations[cloudFormationAnnotation]) > 0 {
+		return append(Modes, ModeCloudFormation), cobra.ShellCompDirectiveDefault
+	}
 	return Modes, cobra.ShellCompDirectiveDefault
 }
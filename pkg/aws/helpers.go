@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	awscbRoles "github.com/openshift/rosa/pkg/aws/commandbuilder/helper/roles"
 
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws/tags"
@@ -653,7 +654,7 @@ func GetFormattedFileName(filename string) string {
 }
 
 func BuildOperatorRolePolicies(prefix string, accountID string, awsClient Client, commands []string,
-	defaultPolicyVersion string, credRequests map[string]*cmv1.STSOperator, path string) []string {
+	defaultPolicyVersion string, credRequests map[string]*cmv1.STSOperator, path string) ([]string, error) {
 	for credrequest, operator := range credRequests {
 		policyARN := GetOperatorPolicyARN(accountID, prefix, operator.Namespace(), operator.Name(), path)
 		_, err := awsClient.IsPolicyExists(policyARN)
@@ -674,26 +675,16 @@ func BuildOperatorRolePolicies(prefix string, accountID string, awsClient Client
 				Build()
 			commands = append(commands, createPolicy)
 		} else {
-			policyTags := map[string]string{
-				tags.OpenShiftVersion: defaultPolicyVersion,
+			prunedVersions, err := PolicyVersionsToPrune(awsClient, policyARN)
+			if err != nil {
+				return nil, err
 			}
-
-			createPolicy := awscb.NewIAMCommandBuilder().
-				SetCommand(awscb.CreatePolicyVersion).
-				AddParam(awscb.PolicyArn, policyARN).
-				AddParam(awscb.PolicyDocument, fmt.Sprintf("file://openshift_%s_policy.json", credrequest)).
-				AddParamNoValue(awscb.SetAsDefault).
-				Build()
-
-			tagPolicy := awscb.NewIAMCommandBuilder().
-				SetCommand(awscb.TagPolicy).
-				AddTags(policyTags).
-				AddParam(awscb.PolicyArn, policyARN).
-				Build()
-			commands = append(commands, createPolicy, tagPolicy)
+			commands = append(commands, awscbRoles.ManualCommandsForPolicyVersion(policyARN,
+				fmt.Sprintf("file://openshift_%s_policy.json", credrequest), defaultPolicyVersion,
+				prunedVersions, PolicyVersionTagValue(defaultPolicyVersion))...)
 		}
 	}
-	return commands
+	return commands, nil
 }
 
 func FindAllAttachedPolicyDetails(policiesDetails []PolicyDetail) []PolicyDetail {
@@ -781,6 +772,16 @@ func GetResourceIdFromARN(stringARN string) (string, error) {
 	return parsedARN.Resource[index+1:], nil
 }
 
+// IsAWSManagedPolicy returns true if the ARN is the ARN of a policy managed by AWS, like the ROSA
+// managed policies, instead of a policy of the customer account.
+func IsAWSManagedPolicy(policyARN string) bool {
+	parsedARN, err := arn.Parse(policyARN)
+	if err != nil {
+		return false
+	}
+	return parsedARN.AccountID == "aws"
+}
+
 func GetResourceIdFromSecretArn(secretArn string) (string, error) {
 	parsedARN, err := arn.Parse(secretArn)
 
@@ -23,6 +23,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
+	errors "github.com/zgalor/weberr"
+
 	"github.com/openshift/rosa/pkg/aws/tags"
 )
 
@@ -88,6 +90,41 @@ func (c *awsClient) HasOpenIDConnectProvider(issuerURL string, accountID string)
 	return true, nil
 }
 
+// OpenIDConnectProvider is the configuration of an IAM OpenID Connect provider.
+type OpenIDConnectProvider struct {
+	ARN         string
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+}
+
+// GetOpenIDConnectProvider returns the OpenID Connect provider of the issuer URL, or an error of
+// type NotFound if it doesn't exist.
+func (c *awsClient) GetOpenIDConnectProvider(issuerURL string, accountID string) (*OpenIDConnectProvider, error) {
+	parsedIssuerURL, err := url.ParseRequestURI(issuerURL)
+	if err != nil {
+		return nil, err
+	}
+	providerURL := fmt.Sprintf("%s%s", parsedIssuerURL.Host, parsedIssuerURL.Path)
+
+	oidcProviderARN := GetOIDCProviderARN(accountID, providerURL)
+	output, err := c.iamClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(oidcProviderARN),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return nil, errors.NotFound.Errorf("OIDC provider '%s' doesn't exist", oidcProviderARN)
+		}
+		return nil, err
+	}
+	return &OpenIDConnectProvider{
+		ARN:         oidcProviderARN,
+		URL:         aws.StringValue(output.Url),
+		ClientIDs:   aws.StringValueSlice(output.ClientIDList),
+		Thumbprints: aws.StringValueSlice(output.ThumbprintList),
+	}, nil
+}
+
 func (c *awsClient) DeleteOpenIDConnectProvider(oidcProviderARN string) error {
 	_, err := c.iamClient.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
 		OpenIDConnectProviderArn: aws.String(oidcProviderARN),
@@ -2,9 +2,12 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/organizations"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
@@ -78,3 +81,197 @@ func (c *awsClient) ValidateSCP(target *string, policies map[string]*cmv1.AWSSTS
 
 	return true, nil
 }
+
+// DeniedAction is an action that a principal isn't allowed to perform, and the policies that deny it.
+type DeniedAction struct {
+	Action string
+	// Decision is the decision of the simulation, either 'implicitDeny' or 'explicitDeny'.
+	Decision string
+	DeniedBy []string
+}
+
+func (d DeniedAction) String() string {
+	return fmt.Sprintf("%s: %s by %s", d.Action, d.Decision, strings.Join(d.DeniedBy, ", "))
+}
+
+// SimulateRolePolicy simulates the actions that the policy document allows, without conditions, for
+// the role, and returns the actions that the role isn't allowed to perform together with the
+// organization SCPs, permissions boundary or policies that deny them.
+func (c *awsClient) SimulateRolePolicy(roleARN string, policyDocument string) ([]DeniedAction, error) {
+	document, err := ParsePolicyDocument(policyDocument)
+	if err != nil {
+		return nil, err
+	}
+	actions := document.getUnconditionalAllowedActions()
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	role, err := c.GetRoleByARN(roleARN)
+	if err != nil {
+		return nil, err
+	}
+	permissionsBoundary := ""
+	if role.PermissionsBoundary != nil {
+		permissionsBoundary = aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(roleARN),
+		ActionNames:     aws.StringSlice(actions),
+		ContextEntries:  []*iam.ContextEntry{},
+	}
+	region := c.GetRegion()
+	if region != "" {
+		input.ContextEntries = append(input.ContextEntries, &iam.ContextEntry{
+			ContextKeyName:   aws.String("aws:RequestedRegion"),
+			ContextKeyType:   aws.String("stringList"),
+			ContextKeyValues: []*string{aws.String(region)},
+		})
+	}
+	var results []*iam.EvaluationResult
+	err = c.iamClient.SimulatePrincipalPolicyPages(input,
+		func(response *iam.SimulatePolicyResponse, lastPage bool) bool {
+			results = append(results, response.EvaluationResults...)
+			return !lastPage
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Error simulating policy: %v", err)
+	}
+
+	deniedActions := []DeniedAction{}
+	var scps []serviceControlPolicy
+	scpsLoaded := false
+	for _, result := range results {
+		if aws.StringValue(result.EvalDecision) == iam.PolicyEvaluationDecisionTypeAllowed {
+			continue
+		}
+		deniedAction := DeniedAction{
+			Action:   aws.StringValue(result.EvalActionName),
+			Decision: aws.StringValue(result.EvalDecision),
+		}
+		if result.OrganizationsDecisionDetail != nil &&
+			!aws.BoolValue(result.OrganizationsDecisionDetail.AllowedByOrganizations) {
+			if !scpsLoaded {
+				scps, err = c.getServiceControlPolicies(aws.StringValue(role.Arn))
+				if err != nil {
+					// Only the management account of the organization can read the SCPs:
+					c.logger.Debugf("Failed to get the service control policies: %v", err)
+				}
+				scpsLoaded = true
+			}
+			deniedAction.DeniedBy = append(deniedAction.DeniedBy,
+				organizationDenials(deniedAction.Action, scps)...)
+		}
+		if result.PermissionsBoundaryDecisionDetail != nil &&
+			!aws.BoolValue(result.PermissionsBoundaryDecisionDetail.AllowedByPermissionsBoundary) {
+			deniedAction.DeniedBy = append(deniedAction.DeniedBy,
+				fmt.Sprintf("permissions boundary '%s'", permissionsBoundary))
+		}
+		if aws.StringValue(result.EvalDecision) == iam.PolicyEvaluationDecisionTypeExplicitDeny {
+			for _, statement := range result.MatchedStatements {
+				deniedAction.DeniedBy = append(deniedAction.DeniedBy,
+					fmt.Sprintf("policy '%s'", aws.StringValue(statement.SourcePolicyId)))
+			}
+		}
+		if len(deniedAction.DeniedBy) == 0 {
+			deniedAction.DeniedBy = []string{"the policies of the role, which don't allow it"}
+		}
+		deniedActions = append(deniedActions, deniedAction)
+	}
+	return deniedActions, nil
+}
+
+// serviceControlPolicy is an organization service control policy and its document.
+type serviceControlPolicy struct {
+	name     string
+	document *PolicyDocument
+}
+
+// getServiceControlPolicies returns the service control policies that apply to the account of the
+// principal, which are the ones attached to the account and to its parent organizational units.
+func (c *awsClient) getServiceControlPolicies(principalARN string) ([]serviceControlPolicy, error) {
+	parsedARN, err := arn.Parse(principalARN)
+	if err != nil {
+		return nil, err
+	}
+	targets := []string{parsedARN.AccountID}
+	child := parsedARN.AccountID
+	for {
+		output, err := c.orgClient.ListParents(&organizations.ListParentsInput{ChildId: aws.String(child)})
+		if err != nil {
+			return nil, err
+		}
+		if len(output.Parents) == 0 {
+			break
+		}
+		child = aws.StringValue(output.Parents[0].Id)
+		targets = append(targets, child)
+		if aws.StringValue(output.Parents[0].Type) == organizations.ParentTypeRoot {
+			break
+		}
+	}
+
+	scps := []serviceControlPolicy{}
+	found := map[string]bool{}
+	for _, target := range targets {
+		var summaries []*organizations.PolicySummary
+		err = c.orgClient.ListPoliciesForTargetPages(&organizations.ListPoliciesForTargetInput{
+			TargetId: aws.String(target),
+			Filter:   aws.String(organizations.PolicyTypeServiceControlPolicy),
+		}, func(output *organizations.ListPoliciesForTargetOutput, lastPage bool) bool {
+			summaries = append(summaries, output.Policies...)
+			return !lastPage
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			id := aws.StringValue(summary.Id)
+			if found[id] {
+				continue
+			}
+			found[id] = true
+			output, err := c.orgClient.DescribePolicy(&organizations.DescribePolicyInput{PolicyId: summary.Id})
+			if err != nil {
+				return nil, err
+			}
+			document, err := ParsePolicyDocument(aws.StringValue(output.Policy.Content))
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse service control policy '%s': %v", id, err)
+			}
+			scps = append(scps, serviceControlPolicy{
+				name:     fmt.Sprintf("%s (%s)", aws.StringValue(summary.Name), id),
+				document: document,
+			})
+		}
+	}
+	return scps, nil
+}
+
+// organizationDenials returns the service control policies that deny the action. When the SCPs
+// couldn't be read, or none of them denies the action explicitly, it describes the reason instead.
+func organizationDenials(action string, scps []serviceControlPolicy) []string {
+	if scps == nil {
+		return []string{"an organization SCP"}
+	}
+	denials := []string{}
+	for _, scp := range scps {
+		for _, statement := range scp.document.Statement {
+			if statement.Effect != "Deny" || !statement.MatchesAction(action) {
+				continue
+			}
+			denial := fmt.Sprintf("SCP '%s'", scp.name)
+			if statement.Sid != "" {
+				denial = fmt.Sprintf("SCP '%s' statement '%s'", scp.name, statement.Sid)
+			}
+			if len(statement.Condition) > 0 {
+				denial += " (with conditions)"
+			}
+			denials = append(denials, denial)
+		}
+	}
+	if len(denials) == 0 {
+		denials = append(denials, "the organization SCPs, which don't allow it")
+	}
+	return denials
+}
@@ -18,6 +18,7 @@ package aws
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -326,14 +327,22 @@ func (c *awsClient) ensurePolicyHelper(policyArn string, document string,
 	}
 
 	if !isCompatible {
-		// Since there is a limit to how many versions a policy can have, we delete all non-default
-		// policy versions from the list, thus making space for the new one.
-		err = c.deletePolicyVersions(policyArn)
+		// Versions created by older versions of rosa aren't recorded, so the current one is
+		// recorded before it stops being the default:
+		err = c.recordPolicyVersion(policyArn, aws.StringValue(output.Policy.DefaultVersionId))
 		if err != nil {
 			return policyArn, err
 		}
 
-		_, err = c.iamClient.CreatePolicyVersion(&iam.CreatePolicyVersionInput{
+		// Since there is a limit to how many versions a policy can have, we delete the oldest
+		// non-default policy versions, thus making space for the new one. The previous versions
+		// are kept so that the upgrade can be rolled back.
+		err = c.prunePolicyVersions(policyArn)
+		if err != nil {
+			return policyArn, err
+		}
+
+		versionOutput, err := c.iamClient.CreatePolicyVersion(&iam.CreatePolicyVersionInput{
 			PolicyArn:      aws.String(policyArn),
 			PolicyDocument: aws.String(document),
 			SetAsDefault:   aws.Bool(true),
@@ -344,7 +353,8 @@ func (c *awsClient) ensurePolicyHelper(policyArn string, document string,
 
 		_, err = c.iamClient.TagPolicy(&iam.TagPolicyInput{
 			PolicyArn: aws.String(policyArn),
-			Tags:      getTags(tagList),
+			Tags: getTags(withPolicyVersionTag(tagList,
+				aws.StringValue(versionOutput.PolicyVersion.VersionId))),
 		})
 		if err != nil {
 			return policyArn, err
@@ -375,10 +385,11 @@ func (c *awsClient) createPolicy(policyArn string, document string, tagList map[
 	if err != nil {
 		return "", err
 	}
+	// The first version of a policy is always 'v1':
 	createPolicyInput := &iam.CreatePolicyInput{
 		PolicyName:     aws.String(policyName),
 		PolicyDocument: aws.String(document),
-		Tags:           getTags(tagList),
+		Tags:           getTags(withPolicyVersionTag(tagList, "v1")),
 	}
 	if path != "" {
 		createPolicyInput.Path = aws.String(path)
@@ -758,6 +769,8 @@ func (c *awsClient) ListAccountRoles(version string) ([]Role, error) {
 				if aws.StringValue(tag.Value) == tags.True {
 					accountRole.ManagedPolicy = true
 				}
+			case tags.RolePrefix:
+				accountRole.RolePrefix = aws.StringValue(tag.Value)
 			}
 		}
 		if isTagged && !skip {
@@ -1076,6 +1089,24 @@ func (c *awsClient) deletePolicies(policies []string) (*iam.DeletePolicyOutput,
 	return output, nil
 }
 
+// DeletePolicy deletes the given policy and all its versions. It fails if the policy is still
+// attached to any role, user or group.
+func (c *awsClient) DeletePolicy(policyARN string) error {
+	isAttached, err := c.isPolicyAttachedToEntity(policyARN)
+	if err != nil {
+		return err
+	}
+	if isAttached {
+		return fmt.Errorf("policy '%s' is attached to other entities", policyARN)
+	}
+	err = c.deletePolicyVersions(policyARN)
+	if err != nil {
+		return err
+	}
+	_, err = c.iamClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: aws.String(policyARN)})
+	return err
+}
+
 func (c *awsClient) deletePolicyVersions(policyArn string) error {
 	policyVersionsOutput, err := c.iamClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{
 		PolicyArn: aws.String(policyArn),
@@ -1600,6 +1631,42 @@ func (c *awsClient) AddRoleTag(roleName string, key string, value string) error
 	return nil
 }
 
+// GetRoleTags returns the tags of the role, or an error of type NotFound if the role doesn't exist.
+func (c *awsClient) GetRoleTags(roleName string) (map[string]string, error) {
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return nil, errors.NotFound.Errorf("Role '%s' not found", roleName)
+		}
+		return nil, err
+	}
+	roleTags := map[string]string{}
+	for _, tag := range output.Role.Tags {
+		roleTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return roleTags, nil
+}
+
+// AddRoleTags adds the tags to the role, replacing the values of the tags that it already has.
+func (c *awsClient) AddRoleTags(roleName string, tagList map[string]string) error {
+	_, err := c.iamClient.TagRole(&iam.TagRoleInput{
+		RoleName: aws.String(roleName),
+		Tags:     getTags(tagList),
+	})
+	return err
+}
+
+// AddPolicyTags adds the tags to the policy, replacing the values of the tags that it already has.
+func (c *awsClient) AddPolicyTags(policyARN string, tagList map[string]string) error {
+	_, err := c.iamClient.TagPolicy(&iam.TagPolicyInput{
+		PolicyArn: aws.String(policyARN),
+		Tags:      getTags(tagList),
+	})
+	return err
+}
+
 func (c *awsClient) IsUpgradedNeededForOperatorRolePoliciesUsingCluster(
 	cluster *cmv1.Cluster,
 	accountID string,
@@ -1861,3 +1928,46 @@ func (c *awsClient) listRoleAttachedPolicies(roleName string) ([]*iam.AttachedPo
 
 	return attachedPoliciesOutput.AttachedPolicies, nil
 }
+
+// GetRoleTrustPolicy returns the trust policy document of the role as it is stored in AWS.
+func (c *awsClient) GetRoleTrustPolicy(roleName string) (string, error) {
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			if awsErr.Code() == iam.ErrCodeNoSuchEntityException {
+				return "", errors.NotFound.Errorf("Role with name '%s' not found", roleName)
+			}
+		}
+		return "", err
+	}
+	return url.QueryUnescape(aws.StringValue(output.Role.AssumeRolePolicyDocument))
+}
+
+// GetAttachedPolicyDocuments returns the documents of the default versions of the managed policies
+// attached to the role, indexed by the ARN of the policy.
+func (c *awsClient) GetAttachedPolicyDocuments(roleName string) (map[string]string, error) {
+	attachedPolicies, err := c.listRoleAttachedPolicies(roleName)
+	if err != nil {
+		return nil, err
+	}
+	documents := map[string]string{}
+	for _, attachedPolicy := range attachedPolicies {
+		policyOutput, err := c.iamClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: attachedPolicy.PolicyArn})
+		if err != nil {
+			return nil, err
+		}
+		versionOutput, err := c.iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+			PolicyArn: attachedPolicy.PolicyArn,
+			VersionId: policyOutput.Policy.DefaultVersionId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		document, err := url.QueryUnescape(aws.StringValue(versionOutput.PolicyVersion.Document))
+		if err != nil {
+			return nil, err
+		}
+		documents[aws.StringValue(attachedPolicy.PolicyArn)] = document
+	}
+	return documents, nil
+}
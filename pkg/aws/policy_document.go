@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -37,11 +39,17 @@ type PolicyStatement struct {
 	// Include a list of actions that the policy allows or denies.
 	// (i.e. ec2:StartInstances, iam:ChangePassword)
 	Action interface{} `json:"Action,omitempty"`
+	// Include a list of actions that the statement doesn't apply to. The statement applies to all
+	// the other actions.
+	NotAction interface{} `json:"NotAction,omitempty"`
 	// If you create an IAM permissions policy, you must specify a list of resources to which
 	// the actions apply. If you create a resource-based policy, this element is optional. If
 	// you do not include this element, then the resource to which the action applies is the
 	// resource to which the policy is attached.
 	Resource interface{} `json:"Resource,omitempty"`
+	// Specify the circumstances under which the policy grants permission, indexed by condition
+	// operator and then by condition key. (i.e. StringEquals, aws:RequestedRegion)
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"`
 }
 
 type PolicyStatementPrincipal struct {
@@ -139,6 +147,68 @@ func (p *PolicyDocument) GetAllowedActions() []string {
 	return actions
 }
 
+// getUnconditionalAllowedActions returns the actions, without wildcards, that the statements of the
+// document allow without conditions. Simulating the actions allowed with conditions would need the
+// context of each request.
+func (p *PolicyDocument) getUnconditionalAllowedActions() []string {
+	actions := []string{}
+	found := map[string]bool{}
+	for _, statement := range p.Statement {
+		if statement.Effect != "Allow" || len(statement.Condition) > 0 {
+			continue
+		}
+		for _, action := range policyElementValues(statement.Action) {
+			if strings.ContainsAny(action, "*?") || found[action] {
+				continue
+			}
+			found[action] = true
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// MatchesAction checks if the statement applies to the action, taking into account the wildcards of
+// the Action and NotAction elements. It does not take into account Resource or Condition elements.
+func (p *PolicyStatement) MatchesAction(action string) bool {
+	if p.NotAction != nil {
+		return !actionMatchesAny(action, policyElementValues(p.NotAction))
+	}
+	return actionMatchesAny(action, policyElementValues(p.Action))
+}
+
+func actionMatchesAny(action string, patterns []string) bool {
+	for _, pattern := range patterns {
+		// Action names aren't case sensitive, and they don't contain slashes, so the patterns can
+		// be matched as paths:
+		matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(action))
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// policyElementValues returns the values of a policy element that can contain either a single value
+// or a list of values, like Action or Resource.
+func policyElementValues(element interface{}) []string {
+	switch value := element.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		values := []string{}
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
 // checkPermissionsUsingQueryClient will use queryClient to query whether the credentials in targetClient can perform
 // the actions listed in the statementEntries. queryClient will need
 // sts:GetCallerIdentity and iam:SimulatePrincipalPolicy
@@ -312,6 +382,116 @@ func GenerateOperatorRolePolicyDoc(cluster *cmv1.Cluster, accountID string, oper
 		accountID, operator, policyDetails)
 }
 
+// ValidateOperatorRoleTrustPolicy checks that the trust policy of an operator role allows the
+// service accounts of the operator to assume the role with the web identities issued by the OIDC
+// endpoint, and returns a description of each mismatch.
+func ValidateOperatorRoleTrustPolicy(trustPolicy string, oidcEndpointURL string, accountID string,
+	operator *cmv1.STSOperator) ([]string, error) {
+	endpointURL, err := url.ParseRequestURI(oidcEndpointURL)
+	if err != nil {
+		return nil, err
+	}
+	issuerURL := fmt.Sprintf("%s%s", endpointURL.Host, endpointURL.Path)
+	oidcProviderARN := GetOIDCProviderARN(accountID, issuerURL)
+	document, err := ParsePolicyDocument(trustPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []PolicyStatement
+	for _, statement := range document.Statement {
+		if statement.Effect == "Allow" && statement.MatchesAction("sts:AssumeRoleWithWebIdentity") {
+			statements = append(statements, statement)
+		}
+	}
+	if len(statements) == 0 {
+		return []string{"no statement allows the 'sts:AssumeRoleWithWebIdentity' action"}, nil
+	}
+
+	mismatches := []string{}
+	var trusted *PolicyStatement
+	var principals []string
+	for i, statement := range statements {
+		if statement.Principal == nil || statement.Principal.Federated == "" {
+			continue
+		}
+		principals = append(principals, fmt.Sprintf("'%s'", statement.Principal.Federated))
+		if statement.Principal.Federated == oidcProviderARN {
+			trusted = &statements[i]
+			break
+		}
+	}
+	if trusted == nil {
+		if len(principals) == 0 {
+			principals = []string{"missing"}
+		}
+		mismatches = append(mismatches, fmt.Sprintf("the federated principal is %s instead of '%s'",
+			strings.Join(principals, ", "), oidcProviderARN))
+		trusted = &statements[0]
+	}
+
+	subKey := fmt.Sprintf("%s:sub", issuerURL)
+	var subjects []string
+	found := false
+	like := false
+	conditionOperators := make([]string, 0, len(trusted.Condition))
+	for conditionOperator := range trusted.Condition {
+		conditionOperators = append(conditionOperators, conditionOperator)
+	}
+	sort.Strings(conditionOperators)
+	for _, conditionOperator := range conditionOperators {
+		keys := make([]string, 0, len(trusted.Condition[conditionOperator]))
+		for key := range trusted.Condition[conditionOperator] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !strings.HasSuffix(key, ":sub") {
+				continue
+			}
+			if key != subKey {
+				mismatches = append(mismatches, fmt.Sprintf("the condition key '%s' doesn't match the "+
+					"issuer URL, it should be '%s'", key, subKey))
+				continue
+			}
+			found = true
+			subjects = append(subjects, policyElementValues(trusted.Condition[conditionOperator][key])...)
+			if strings.Contains(conditionOperator, "Like") {
+				like = true
+			}
+		}
+	}
+	if !found {
+		return append(mismatches, fmt.Sprintf("there is no '%s' condition restricting the service "+
+			"accounts that can assume the role", subKey)), nil
+	}
+	for _, serviceAccount := range operator.ServiceAccounts() {
+		subject := fmt.Sprintf("system:serviceaccount:%s:%s", operator.Namespace(), serviceAccount)
+		if !subjectMatchesAny(subject, subjects, like) {
+			mismatches = append(mismatches, fmt.Sprintf("service account '%s' is missing from the '%s' condition",
+				subject, subKey))
+		}
+	}
+	return mismatches, nil
+}
+
+func subjectMatchesAny(subject string, patterns []string, like bool) bool {
+	for _, pattern := range patterns {
+		if pattern == subject {
+			return true
+		}
+		if like {
+			// Subjects contain no slashes, so the patterns of the 'StringLike' operators can be
+			// matched as paths:
+			matched, err := path.Match(pattern, subject)
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func GenerateAddonPolicyDoc(cluster *cmv1.Cluster, accountID string, cr *cmv1.CredentialRequest,
 	policyDetails string) (string, error) {
 	service_accounts := fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace(), cr.ServiceAccount())
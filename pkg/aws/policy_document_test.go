@@ -0,0 +1,85 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("ValidateOperatorRoleTrustPolicy", func() {
+	var operator *cmv1.STSOperator
+
+	BeforeEach(func() {
+		var err error
+		operator, err = cmv1.NewSTSOperator().
+			Name("cloud-credentials").
+			Namespace("openshift-ingress-operator").
+			ServiceAccounts("ingress-operator", "ingress-canary").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Accepts a trust policy for the OIDC provider and the service accounts", func() {
+		mismatches, err := aws.ValidateOperatorRoleTrustPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"},
+			"Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {"StringEquals": {"oidc.example.com/abc:sub": [
+				"system:serviceaccount:openshift-ingress-operator:ingress-operator",
+				"system:serviceaccount:openshift-ingress-operator:ingress-canary"
+			]}}
+		}]}`, "https://oidc.example.com/abc", "123456789012", operator)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatches).To(BeEmpty())
+	})
+
+	It("Reports a wrong OIDC provider and missing service accounts", func() {
+		mismatches, err := aws.ValidateOperatorRoleTrustPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/xyz"},
+			"Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {"StringEquals": {
+				"oidc.example.com/abc:sub": "system:serviceaccount:openshift-ingress-operator:ingress-operator"
+			}}
+		}]}`, "https://oidc.example.com/abc", "123456789012", operator)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatches).To(Equal([]string{
+			"the federated principal is 'arn:aws:iam::123456789012:oidc-provider/oidc.example.com/xyz' " +
+				"instead of 'arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc'",
+			"service account 'system:serviceaccount:openshift-ingress-operator:ingress-canary' is missing " +
+				"from the 'oidc.example.com/abc:sub' condition",
+		}))
+	})
+
+	It("Accepts service accounts matched by wildcards", func() {
+		mismatches, err := aws.ValidateOperatorRoleTrustPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"},
+			"Action": ["sts:AssumeRoleWithWebIdentity"],
+			"Condition": {"StringLike": {
+				"oidc.example.com/abc:sub": "system:serviceaccount:openshift-ingress-operator:*"
+			}}
+		}]}`, "https://oidc.example.com/abc", "123456789012", operator)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatches).To(BeEmpty())
+	})
+
+	It("Reports a trust policy without a 'sub' condition", func() {
+		mismatches, err := aws.ValidateOperatorRoleTrustPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"},
+			"Action": "sts:AssumeRoleWithWebIdentity"
+		}]}`, "https://oidc.example.com/abc", "123456789012", operator)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatches).To(Equal([]string{
+			"there is no 'oidc.example.com/abc:sub' condition restricting the service accounts that can " +
+				"assume the role",
+		}))
+	})
+})
@@ -0,0 +1,214 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/info"
+)
+
+// maxPolicyVersions is the maximum number of versions that IAM keeps for a managed policy.
+const maxPolicyVersions = 5
+
+// PolicyVersion is a version of a customer managed policy, together with the versions of OpenShift
+// and rosa that created it. The versions are empty when the policy version wasn't created by rosa,
+// or was created by a version of rosa that didn't record them.
+type PolicyVersion struct {
+	ID               string
+	IsDefault        bool
+	CreateDate       time.Time
+	OpenShiftVersion string
+	RosaVersion      string
+}
+
+// policyVersionTag returns the tag that records the versions that created the policy version.
+func policyVersionTag(versionID string) string {
+	return tags.PolicyVersion + versionID
+}
+
+// PolicyVersionTagValue returns the value of the tag that records that the current version of rosa
+// created a policy version for the given OpenShift version. The fields are separated with a slash,
+// so that the value can be passed to the AWS command line tool without quotes.
+func PolicyVersionTagValue(openShiftVersion string) string {
+	return fmt.Sprintf("openshift=%s/rosa=%s", openShiftVersion, info.Version)
+}
+
+// withPolicyVersionTag returns a copy of the tags of a policy that also records the versions that
+// created the policy version. The tags aren't changed if they don't contain the OpenShift version.
+func withPolicyVersionTag(tagList map[string]string, versionID string) map[string]string {
+	result := map[string]string{}
+	for key, value := range tagList {
+		result[key] = value
+	}
+	if openShiftVersion, ok := tagList[tags.OpenShiftVersion]; ok {
+		result[policyVersionTag(versionID)] = PolicyVersionTagValue(openShiftVersion)
+	}
+	return result
+}
+
+// recordPolicyVersion records the OpenShift version of the policy as the one of the policy version,
+// unless it was already recorded when the policy version was created.
+func (c *awsClient) recordPolicyVersion(policyArn string, versionID string) error {
+	output, err := c.iamClient.ListPolicyTags(&iam.ListPolicyTagsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return err
+	}
+	openShiftVersion := ""
+	for _, tag := range output.Tags {
+		switch aws.StringValue(tag.Key) {
+		case policyVersionTag(versionID):
+			return nil
+		case tags.OpenShiftVersion:
+			openShiftVersion = aws.StringValue(tag.Value)
+		}
+	}
+	if openShiftVersion == "" {
+		return nil
+	}
+	// The version of rosa that created it is unknown:
+	_, err = c.iamClient.TagPolicy(&iam.TagPolicyInput{
+		PolicyArn: aws.String(policyArn),
+		Tags: []*iam.Tag{{
+			Key:   aws.String(policyVersionTag(versionID)),
+			Value: aws.String(fmt.Sprintf("openshift=%s", openShiftVersion)),
+		}},
+	})
+	return err
+}
+
+// prunePolicyVersions deletes the oldest non default versions of the policy, and the tags that
+// describe them, so that a new version can be created without exceeding the IAM limit. The rest of
+// the versions are kept so that the policy can be rolled back.
+func (c *awsClient) prunePolicyVersions(policyArn string) error {
+	output, err := c.iamClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return err
+	}
+	versions := make([]PolicyVersion, len(output.Versions))
+	for i, version := range output.Versions {
+		versions[i] = PolicyVersion{
+			ID:         aws.StringValue(version.VersionId),
+			IsDefault:  aws.BoolValue(version.IsDefaultVersion),
+			CreateDate: aws.TimeValue(version.CreateDate),
+		}
+	}
+
+	deletedTags := []*string{}
+	for _, versionID := range versionsToPrune(versions) {
+		_, err = c.iamClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+			PolicyArn: aws.String(policyArn),
+			VersionId: aws.String(versionID),
+		})
+		if err != nil {
+			return err
+		}
+		deletedTags = append(deletedTags, aws.String(policyVersionTag(versionID)))
+	}
+	if len(deletedTags) > 0 {
+		_, err = c.iamClient.UntagPolicy(&iam.UntagPolicyInput{
+			PolicyArn: aws.String(policyArn),
+			TagKeys:   deletedTags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionsToPrune returns the identifiers of the oldest non default versions that have to be deleted
+// so that a new version can be created without exceeding the IAM limit.
+func versionsToPrune(versions []PolicyVersion) []string {
+	sorted := append([]PolicyVersion{}, versions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreateDate.Before(sorted[j].CreateDate)
+	})
+	excess := len(sorted) - (maxPolicyVersions - 1)
+	result := []string{}
+	for _, version := range sorted {
+		if excess <= 0 {
+			break
+		}
+		if version.IsDefault {
+			continue
+		}
+		result = append(result, version.ID)
+		excess--
+	}
+	return result
+}
+
+// PolicyVersionsToPrune returns the identifiers of the versions of the policy that the commands of
+// manual mode have to delete before creating a new version, like auto mode does.
+func PolicyVersionsToPrune(client Client, policyArn string) ([]string, error) {
+	versions, err := client.ListPolicyVersions(policyArn)
+	if err != nil {
+		return nil, err
+	}
+	return versionsToPrune(versions), nil
+}
+
+// ListPolicyVersions returns the versions of the customer managed policy, the newest first.
+func (c *awsClient) ListPolicyVersions(policyArn string) ([]PolicyVersion, error) {
+	versionsOutput, err := c.iamClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tagsOutput, err := c.iamClient.ListPolicyTags(&iam.ListPolicyTagsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recorded := map[string]string{}
+	for _, tag := range tagsOutput.Tags {
+		recorded[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	versions := []PolicyVersion{}
+	for _, version := range versionsOutput.Versions {
+		policyVersion := PolicyVersion{
+			ID:         aws.StringValue(version.VersionId),
+			IsDefault:  aws.BoolValue(version.IsDefaultVersion),
+			CreateDate: aws.TimeValue(version.CreateDate),
+		}
+		fields := strings.FieldsFunc(recorded[policyVersionTag(policyVersion.ID)], func(r rune) bool {
+			return r == ' ' || r == '/'
+		})
+		for _, field := range fields {
+			name, value, _ := strings.Cut(field, "=")
+			switch name {
+			case "openshift":
+				policyVersion.OpenShiftVersion = value
+			case "rosa":
+				policyVersion.RosaVersion = value
+			}
+		}
+		versions = append(versions, policyVersion)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreateDate.After(versions[j].CreateDate)
+	})
+	return versions, nil
+}
+
+// SetDefaultPolicyVersion makes the policy version the one that is used.
+func (c *awsClient) SetDefaultPolicyVersion(policyArn string, versionID string) error {
+	_, err := c.iamClient.SetDefaultPolicyVersion(&iam.SetDefaultPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionID),
+	})
+	return err
+}
@@ -0,0 +1,385 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policydiff compares IAM policy documents statement by statement, so that changes made to
+// policies outside of rosa, for example in the AWS console, can be reported.
+package policydiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a difference between two policy documents.
+type Kind string
+
+const (
+	AddedAction         Kind = "added-action"
+	RemovedAction       Kind = "removed-action"
+	ChangedCondition    Kind = "changed-condition"
+	WrongPrincipal      Kind = "wrong-principal"
+	ChangedResource     Kind = "changed-resource"
+	ChangedEffect       Kind = "changed-effect"
+	MissingStatement    Kind = "missing-statement"
+	UnexpectedStatement Kind = "unexpected-statement"
+)
+
+// Difference is a difference between a statement of the expected document and the matching
+// statement of the actual one.
+type Difference struct {
+	// Statement is the Sid of the statement, or its position in the document when it has no Sid.
+	Statement string
+	Kind      Kind
+	// Detail is the action, or the operator and key of the condition, that changed.
+	Detail   string
+	Expected string
+	Actual   string
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case AddedAction:
+		return fmt.Sprintf("statement %s: action '%s' was added", d.Statement, d.Detail)
+	case RemovedAction:
+		return fmt.Sprintf("statement %s: action '%s' was removed", d.Statement, d.Detail)
+	case ChangedCondition:
+		switch {
+		case d.Actual == "":
+			return fmt.Sprintf("statement %s: condition '%s' %s was removed", d.Statement, d.Detail, d.Expected)
+		case d.Expected == "":
+			return fmt.Sprintf("statement %s: condition '%s' %s was added", d.Statement, d.Detail, d.Actual)
+		}
+		return fmt.Sprintf("statement %s: condition '%s' is %s instead of %s",
+			d.Statement, d.Detail, d.Actual, d.Expected)
+	case WrongPrincipal:
+		return fmt.Sprintf("statement %s: principal is %s instead of %s", d.Statement, d.Actual, d.Expected)
+	case ChangedResource:
+		return fmt.Sprintf("statement %s: resource is %s instead of %s", d.Statement, d.Actual, d.Expected)
+	case ChangedEffect:
+		return fmt.Sprintf("statement %s: effect is '%s' instead of '%s'", d.Statement, d.Actual, d.Expected)
+	case MissingStatement:
+		return fmt.Sprintf("statement %s is missing", d.Statement)
+	case UnexpectedStatement:
+		return fmt.Sprintf("statement %s was added", d.Statement)
+	}
+	return fmt.Sprintf("statement %s: %s", d.Statement, d.Kind)
+}
+
+// Format returns the differences one per line, indented so that they can be printed below a
+// message that describes the policy.
+func Format(differences []Difference) string {
+	lines := make([]string, len(differences))
+	for i, difference := range differences {
+		lines[i] = "  - " + difference.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Compare returns the semantic differences between the expected and the actual policy documents.
+// Statements are matched by Sid, and the statements without a matching Sid by the actions they
+// have in common. The order of statements, actions and values, and the case of actions, are
+// ignored.
+func Compare(expected string, actual string) ([]Difference, error) {
+	expectedStatements, err := parse(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected policy document: %v", err)
+	}
+	actualStatements, err := parse(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %v", err)
+	}
+
+	differences := []Difference{}
+	matched := map[*statement]bool{}
+	pairs := map[*statement]*statement{}
+	for _, e := range expectedStatements {
+		if e.sid == "" {
+			continue
+		}
+		for _, a := range actualStatements {
+			if !matched[a] && a.sid == e.sid {
+				pairs[e] = a
+				matched[a] = true
+				break
+			}
+		}
+	}
+	for _, e := range expectedStatements {
+		if pairs[e] != nil {
+			continue
+		}
+		var best *statement
+		bestScore := 0
+		for _, a := range actualStatements {
+			if matched[a] || a.effect != e.effect {
+				continue
+			}
+			score := e.score(a)
+			if score > bestScore {
+				best = a
+				bestScore = score
+			}
+		}
+		if best != nil {
+			pairs[e] = best
+			matched[best] = true
+		}
+	}
+
+	for _, e := range expectedStatements {
+		a := pairs[e]
+		if a == nil {
+			differences = append(differences, Difference{Statement: e.label, Kind: MissingStatement})
+			continue
+		}
+		differences = append(differences, e.compare(a)...)
+	}
+	for _, a := range actualStatements {
+		if !matched[a] {
+			differences = append(differences, Difference{Statement: a.label, Kind: UnexpectedStatement})
+		}
+	}
+	return differences, nil
+}
+
+// statement is a policy statement with its values normalized so that it can be compared.
+type statement struct {
+	label      string
+	sid        string
+	effect     string
+	actions    set
+	notActions set
+	resources  set
+	principals set
+	// conditions contains the values of each condition, indexed by operator and key.
+	conditions map[string]set
+}
+
+type set map[string]bool
+
+func (s set) sorted() []string {
+	values := make([]string, 0, len(s))
+	for value := range s {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func (s set) String() string {
+	if len(s) == 0 {
+		return "none"
+	}
+	return "[" + strings.Join(s.sorted(), ", ") + "]"
+}
+
+func (s set) equal(other set) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for value := range s {
+		if !other[value] {
+			return false
+		}
+	}
+	return true
+}
+
+func parse(document string) ([]*statement, error) {
+	var policy struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	err := json.Unmarshal([]byte(document), &policy)
+	if err != nil {
+		return nil, err
+	}
+	// A policy with a single statement may contain it without the list:
+	var raw []map[string]interface{}
+	if len(policy.Statement) > 0 && policy.Statement[0] == '{' {
+		var single map[string]interface{}
+		err = json.Unmarshal(policy.Statement, &single)
+		raw = append(raw, single)
+	} else if len(policy.Statement) > 0 {
+		err = json.Unmarshal(policy.Statement, &raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]*statement, len(raw))
+	for i, values := range raw {
+		s := &statement{
+			label:      fmt.Sprintf("#%d", i+1),
+			actions:    lowerSet(values["Action"]),
+			notActions: lowerSet(values["NotAction"]),
+			resources:  newSet(values["Resource"]),
+			principals: set{},
+			conditions: map[string]set{},
+		}
+		if sid, ok := values["Sid"].(string); ok && sid != "" {
+			s.sid = sid
+			s.label = fmt.Sprintf("'%s'", sid)
+		}
+		s.effect, _ = values["Effect"].(string)
+		switch principal := values["Principal"].(type) {
+		case string:
+			s.principals[principal] = true
+		case map[string]interface{}:
+			for kind, value := range principal {
+				for name := range newSet(value) {
+					s.principals[kind+":"+name] = true
+				}
+			}
+		}
+		if conditions, ok := values["Condition"].(map[string]interface{}); ok {
+			for operator, keys := range conditions {
+				keys, ok := keys.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for key, value := range keys {
+					s.conditions[operator+" "+key] = newSet(value)
+				}
+			}
+		}
+		statements[i] = s
+	}
+	return statements, nil
+}
+
+// newSet returns the values of an element that may contain a single value or a list of values.
+func newSet(value interface{}) set {
+	result := set{}
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			result[fmt.Sprint(item)] = true
+		}
+	case nil:
+	default:
+		result[fmt.Sprint(value)] = true
+	}
+	return result
+}
+
+// lowerSet returns the values of an element in lower case, as action names aren't case sensitive.
+func lowerSet(value interface{}) set {
+	result := set{}
+	for item := range newSet(value) {
+		result[strings.ToLower(item)] = true
+	}
+	return result
+}
+
+// score returns how likely it is that the other statement is a modified version of this one.
+func (s *statement) score(other *statement) int {
+	score := 0
+	for action := range s.actions {
+		if other.actions[action] {
+			score++
+		}
+	}
+	for action := range s.notActions {
+		if other.notActions[action] {
+			score++
+		}
+	}
+	for principal := range s.principals {
+		if other.principals[principal] {
+			score++
+		}
+	}
+	return score
+}
+
+func (s *statement) compare(actual *statement) []Difference {
+	differences := []Difference{}
+	label := s.label
+	if actual.label != s.label {
+		label = fmt.Sprintf("%s (%s in the policy)", s.label, actual.label)
+	}
+	if !strings.EqualFold(s.effect, actual.effect) {
+		differences = append(differences, Difference{
+			Statement: label,
+			Kind:      ChangedEffect,
+			Expected:  s.effect,
+			Actual:    actual.effect,
+		})
+	}
+	differences = append(differences, compareActions(label, s.actions, actual.actions)...)
+	differences = append(differences, compareActions(label, s.notActions, actual.notActions)...)
+	if !s.resources.equal(actual.resources) {
+		differences = append(differences, Difference{
+			Statement: label,
+			Kind:      ChangedResource,
+			Expected:  s.resources.String(),
+			Actual:    actual.resources.String(),
+		})
+	}
+	if !s.principals.equal(actual.principals) {
+		differences = append(differences, Difference{
+			Statement: label,
+			Kind:      WrongPrincipal,
+			Expected:  s.principals.String(),
+			Actual:    actual.principals.String(),
+		})
+	}
+
+	keys := set{}
+	for key := range s.conditions {
+		keys[key] = true
+	}
+	for key := range actual.conditions {
+		keys[key] = true
+	}
+	for _, key := range keys.sorted() {
+		expected, hasExpected := s.conditions[key]
+		value, hasActual := actual.conditions[key]
+		if hasExpected && hasActual && expected.equal(value) {
+			continue
+		}
+		difference := Difference{
+			Statement: label,
+			Kind:      ChangedCondition,
+			Detail:    key,
+		}
+		if hasExpected {
+			difference.Expected = expected.String()
+		}
+		if hasActual {
+			difference.Actual = value.String()
+		}
+		differences = append(differences, difference)
+	}
+	return differences
+}
+
+func compareActions(label string, expected set, actual set) []Difference {
+	differences := []Difference{}
+	for _, action := range actual.sorted() {
+		if !expected[action] {
+			differences = append(differences, Difference{Statement: label, Kind: AddedAction, Detail: action})
+		}
+	}
+	for _, action := range expected.sorted() {
+		if !actual[action] {
+			differences = append(differences, Difference{Statement: label, Kind: RemovedAction, Detail: action})
+		}
+	}
+	return differences
+}
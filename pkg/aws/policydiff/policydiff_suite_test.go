@@ -0,0 +1,13 @@
+package policydiff_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPolicydiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Policy diff Suite")
+}
@@ -0,0 +1,103 @@
+package policydiff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/rosa/pkg/aws/policydiff"
+)
+
+var _ = Describe("Compare", func() {
+	It("ignores the order of statements, actions and values", func() {
+		expected := `{
+			"Version": "2012-10-17",
+			"Statement": [
+				{"Effect": "Allow", "Action": ["ec2:DescribeInstances", "ec2:RunInstances"], "Resource": "*"},
+				{"Sid": "S3", "Effect": "Allow", "Action": "s3:GetObject", "Resource": ["a", "b"]}
+			]
+		}`
+		actual := `{
+			"Version": "2012-10-17",
+			"Statement": [
+				{"Sid": "S3", "Effect": "Allow", "Action": ["s3:getobject"], "Resource": ["b", "a"]},
+				{"Effect": "Allow", "Action": ["ec2:RunInstances", "ec2:DescribeInstances"], "Resource": ["*"]}
+			]
+		}`
+		differences, err := Compare(expected, actual)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("reports added and removed actions", func() {
+		expected := `{"Statement": [{"Effect": "Allow", "Action": ["ec2:DescribeInstances", "ec2:RunInstances"]}]}`
+		actual := `{"Statement": {"Effect": "Allow", "Action": ["ec2:DescribeInstances", "iam:PassRole"]}}`
+		differences, err := Compare(expected, actual)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(ConsistOf(
+			Difference{Statement: "#1", Kind: AddedAction, Detail: "iam:passrole"},
+			Difference{Statement: "#1", Kind: RemovedAction, Detail: "ec2:runinstances"},
+		))
+		Expect(Format(differences)).To(Equal(
+			"  - statement #1: action 'iam:passrole' was added\n" +
+				"  - statement #1: action 'ec2:runinstances' was removed"))
+	})
+
+	It("reports changed conditions and wrong principals", func() {
+		expected := `{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123:oidc-provider/oidc.example.com/abc"},
+			"Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {"StringEquals": {
+				"oidc.example.com/abc:sub": ["system:serviceaccount:ns:a", "system:serviceaccount:ns:b"]
+			}}
+		}]}`
+		actual := `{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123:oidc-provider/oidc.example.com/xyz"},
+			"Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {
+				"StringEquals": {"oidc.example.com/abc:sub": "system:serviceaccount:ns:a"},
+				"Bool": {"aws:SecureTransport": true}
+			}
+		}]}`
+		differences, err := Compare(expected, actual)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(ConsistOf(
+			Difference{
+				Statement: "#1",
+				Kind:      WrongPrincipal,
+				Expected:  "[Federated:arn:aws:iam::123:oidc-provider/oidc.example.com/abc]",
+				Actual:    "[Federated:arn:aws:iam::123:oidc-provider/oidc.example.com/xyz]",
+			},
+			Difference{
+				Statement: "#1",
+				Kind:      ChangedCondition,
+				Detail:    "Bool aws:SecureTransport",
+				Actual:    "[true]",
+			},
+			Difference{
+				Statement: "#1",
+				Kind:      ChangedCondition,
+				Detail:    "StringEquals oidc.example.com/abc:sub",
+				Expected:  "[system:serviceaccount:ns:a, system:serviceaccount:ns:b]",
+				Actual:    "[system:serviceaccount:ns:a]",
+			},
+		))
+	})
+
+	It("reports missing and unexpected statements", func() {
+		expected := `{"Statement": [{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject"}]}`
+		actual := `{"Statement": [{"Sid": "All", "Effect": "Allow", "Action": "s3:*"}]}`
+		differences, err := Compare(expected, actual)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(Equal([]Difference{
+			{Statement: "'Read'", Kind: MissingStatement},
+			{Statement: "'All'", Kind: UnexpectedStatement},
+		}))
+	})
+
+	It("fails if a document isn't valid", func() {
+		_, err := Compare(`{"Statement": []}`, `{`)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that list the IAM resources that ROSA creates for clusters,
+// regardless of the cluster they were created for.
+
+package aws
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/rosa/pkg/aws/tags"
+)
+
+// OperatorRoleResource is an operator role found in the account.
+type OperatorRoleResource struct {
+	Name      string
+	ARN       string
+	CreatedAt time.Time
+	Tags      map[string]string
+}
+
+// OperatorPolicyResource is an operator policy managed by the customer found in the account.
+type OperatorPolicyResource struct {
+	Name            string
+	ARN             string
+	CreatedAt       time.Time
+	AttachmentCount int
+	Tags            map[string]string
+}
+
+// OIDCProviderResource is an OpenID Connect provider found in the account.
+type OIDCProviderResource struct {
+	ARN       string
+	URL       string
+	CreatedAt time.Time
+	Tags      map[string]string
+}
+
+// operatorResourceRE matches the names of the operator roles and policies created by ROSA, which
+// end with the namespace and the name of the operator. It is used to avoid reading the tags of
+// resources that can't be operator roles or policies.
+var operatorResourceRE = regexp.MustCompile(`(?i)[\w+=,.@-]+-(openshift|kube-system)-[\w+=,.@-]+`)
+
+// ListOperatorRoleResources returns the roles of the account that are tagged with the namespace
+// of an operator.
+func (c *awsClient) ListOperatorRoleResources() ([]OperatorRoleResource, error) {
+	roles, err := c.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	result := []OperatorRoleResource{}
+	for _, role := range roles {
+		if !operatorResourceRE.MatchString(aws.StringValue(role.RoleName)) {
+			continue
+		}
+		listRoleTagsOutput, err := c.iamClient.ListRoleTags(&iam.ListRoleTagsInput{
+			RoleName: role.RoleName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		roleTags := tagsToMap(listRoleTagsOutput.Tags)
+		if _, ok := roleTags[tags.OperatorNamespace]; !ok {
+			continue
+		}
+		result = append(result, OperatorRoleResource{
+			Name:      aws.StringValue(role.RoleName),
+			ARN:       aws.StringValue(role.Arn),
+			CreatedAt: aws.TimeValue(role.CreateDate),
+			Tags:      roleTags,
+		})
+	}
+	return result, nil
+}
+
+// ListOperatorPolicyResources returns the policies managed by the customer that are tagged with
+// the namespace of an operator.
+func (c *awsClient) ListOperatorPolicyResources() ([]OperatorPolicyResource, error) {
+	policies := []*iam.Policy{}
+	err := c.iamClient.ListPoliciesPages(&iam.ListPoliciesInput{
+		Scope: aws.String(iam.PolicyScopeTypeLocal),
+	}, func(page *iam.ListPoliciesOutput, lastPage bool) bool {
+		policies = append(policies, page.Policies...)
+		return aws.BoolValue(page.IsTruncated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := []OperatorPolicyResource{}
+	for _, policy := range policies {
+		if !operatorResourceRE.MatchString(aws.StringValue(policy.PolicyName)) {
+			continue
+		}
+		listPolicyTagsOutput, err := c.iamClient.ListPolicyTags(&iam.ListPolicyTagsInput{
+			PolicyArn: policy.Arn,
+		})
+		if err != nil {
+			return nil, err
+		}
+		policyTags := tagsToMap(listPolicyTagsOutput.Tags)
+		if _, ok := policyTags[tags.OperatorNamespace]; !ok {
+			continue
+		}
+		result = append(result, OperatorPolicyResource{
+			Name:            aws.StringValue(policy.PolicyName),
+			ARN:             aws.StringValue(policy.Arn),
+			CreatedAt:       aws.TimeValue(policy.CreateDate),
+			AttachmentCount: int(aws.Int64Value(policy.AttachmentCount)),
+			Tags:            policyTags,
+		})
+	}
+	return result, nil
+}
+
+// ListOpenIDConnectProviderResources returns all the OpenID Connect providers of the account,
+// including the ones that weren't created by ROSA, so that the caller can decide which ones are
+// relevant.
+func (c *awsClient) ListOpenIDConnectProviderResources() ([]OIDCProviderResource, error) {
+	providers, err := c.iamClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, err
+	}
+	result := []OIDCProviderResource{}
+	for _, provider := range providers.OpenIDConnectProviderList {
+		output, err := c.iamClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: provider.Arn,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, OIDCProviderResource{
+			ARN:       aws.StringValue(provider.Arn),
+			URL:       aws.StringValue(output.Url),
+			CreatedAt: aws.TimeValue(output.CreateDate),
+			Tags:      tagsToMap(output.Tags),
+		})
+	}
+	return result, nil
+}
+
+func tagsToMap(iamTags []*iam.Tag) map[string]string {
+	result := map[string]string{}
+	for _, tag := range iamTags {
+		result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return result
+}
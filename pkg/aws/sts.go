@@ -171,7 +171,7 @@ func UpgradeOperatorPolicies(reporter *rprtr.Object, awsClient Client, accountID
 }
 
 func BuildOperatorRoleCommands(prefix string, accountID string, awsClient Client,
-	defaultPolicyVersion string, credRequests map[string]*cmv1.STSOperator, policyPath string) []string {
+	defaultPolicyVersion string, credRequests map[string]*cmv1.STSOperator, policyPath string) ([]string, error) {
 	commands := []string{}
 	for credrequest, operator := range credRequests {
 		policyARN := GetOperatorPolicyARN(
@@ -188,6 +188,13 @@ func BuildOperatorRoleCommands(prefix string, accountID string, awsClient Client
 		)
 		_, err := awsClient.IsPolicyExists(policyARN)
 		hasPolicy := err == nil
+		var prunedVersions []string
+		if hasPolicy {
+			prunedVersions, err = PolicyVersionsToPrune(awsClient, policyARN)
+			if err != nil {
+				return nil, err
+			}
+		}
 		upgradePoliciesCommands := awscbRoles.ManualCommandsForUpgradeOperatorRolePolicy(
 			awscbRoles.ManualCommandsForUpgradeOperatorRolePolicyInput{
 				HasPolicy:                hasPolicy,
@@ -198,9 +205,11 @@ func BuildOperatorRoleCommands(prefix string, accountID string, awsClient Client
 				PolicyARN:                policyARN,
 				DefaultPolicyVersion:     defaultPolicyVersion,
 				PolicyName:               policyName,
+				PrunedPolicyVersions:     prunedVersions,
+				PolicyVersionTagValue:    PolicyVersionTagValue(defaultPolicyVersion),
 			},
 		)
 		commands = append(commands, upgradePoliciesCommands...)
 	}
-	return commands
+	return commands, nil
 }
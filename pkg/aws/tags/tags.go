@@ -58,4 +58,8 @@ const OperatorNamespace = "operator_namespace"
 
 const OperatorName = "operator_name"
 
+// PolicyVersion is the prefix of the tags that contain, for each version of a policy, the versions
+// of OpenShift and rosa that created it. The identifier of the policy version is added to it.
+const PolicyVersion = prefix + "policy_version_"
+
 const True = "true"
@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cassette records the HTTP requests sent to OCM and AWS, and the responses received, to
+// cassette files, and serves them back instead of sending the requests to the network, so that the
+// commands can be tested without network access or accounts.
+//
+// The mode is selected with the ROSA_CASSETTE_MODE environment variable, and the directory that
+// contains the cassettes with the ROSA_CASSETTE_DIR environment variable. Each command line is
+// recorded to its own cassette, so a script that runs several commands can be replayed as long as
+// it runs the same commands with the same arguments. The security sensitive values of the requests
+// and the responses are redacted. The tokens of the responses of the authentication service are
+// replaced with placeholder tokens when they are replayed, as the clients need to parse them. For
+// the same reason the cassettes of the commands that use OCM can be replayed without logging in.
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	ModeEnv = "ROSA_CASSETTE_MODE"
+	DirEnv  = "ROSA_CASSETTE_DIR"
+)
+
+const (
+	// ModeRecord sends the requests to the network and records them and their responses.
+	ModeRecord = "record"
+	// ModeReplay serves the recorded responses, and fails the requests that weren't recorded.
+	ModeReplay = "replay"
+)
+
+// Cassette contains the interactions recorded for a command line.
+type Cassette struct {
+	Command      string         `json:"command"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response received for it.
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+	// used is true when the interaction has already been replayed.
+	used bool
+}
+
+// Request contains the parts of a request that are used to match it. The URL and the body are
+// normalized so that they don't depend on the order of the query parameters or the fields, and
+// the values of the security sensitive fields and idempotency tokens are removed.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+var mode string
+var file string
+var cassette *Cassette
+var lock = &sync.Mutex{}
+
+// Enabled returns true if the requests are recorded or replayed.
+func Enabled() bool {
+	return cassette != nil
+}
+
+// Mode returns the mode selected in the environment, or an empty string if the requests aren't
+// recorded or replayed.
+func Mode() string {
+	return mode
+}
+
+// Start selects the cassette of the command line, and loads it if it is replayed. It does nothing
+// if the mode isn't set in the environment.
+func Start(commandPath string, args []string) error {
+	mode = os.Getenv(ModeEnv)
+	if mode == "" {
+		return nil
+	}
+	if mode != ModeRecord && mode != ModeReplay {
+		return fmt.Errorf("Invalid cassette mode '%s'. Allowed values are %s", mode,
+			[]string{ModeRecord, ModeReplay})
+	}
+	dir := os.Getenv(DirEnv)
+	if dir == "" {
+		return fmt.Errorf("Environment variable '%s' is required with cassette mode '%s'", DirEnv, mode)
+	}
+
+	commandLine := strings.Join(args, " ")
+	file = filepath.Join(dir, fileName(commandPath, commandLine))
+	lock.Lock()
+	defer lock.Unlock()
+	if mode == ModeRecord {
+		err := os.MkdirAll(dir, 0700)
+		if err != nil {
+			return fmt.Errorf("Failed to create cassette directory '%s': %v", dir, err)
+		}
+		cassette = &Cassette{
+			Command:      commandLine,
+			Interactions: []*Interaction{},
+		}
+		err = save()
+		if err != nil {
+			return fmt.Errorf("Failed to create cassette '%s': %v", file, err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Failed to read the cassette of command '%s': %v", commandLine, err)
+	}
+	loaded := &Cassette{}
+	err = json.Unmarshal(data, loaded)
+	if err != nil {
+		return fmt.Errorf("Failed to parse cassette '%s': %v", file, err)
+	}
+	cassette = loaded
+	return nil
+}
+
+// fileName returns the name of the cassette of the command line. It starts with the command, so
+// that the cassettes are easy to find, followed by a hash of the complete command line.
+func fileName(commandPath string, commandLine string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(commandLine))
+	return fmt.Sprintf("%s-%08x.json", strings.ReplaceAll(commandPath, " ", "_"), hash.Sum32())
+}
+
+// record adds the interaction to the cassette and writes the cassette to the file, so that it is
+// complete even if the tool exits without returning from the command.
+func record(interaction *Interaction) error {
+	lock.Lock()
+	defer lock.Unlock()
+	cassette.Interactions = append(cassette.Interactions, interaction)
+	return save()
+}
+
+// replay returns the response of the first interaction recorded for the request that hasn't been
+// replayed yet, or nil if there is none.
+func replay(request *Request) *Response {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, interaction := range cassette.Interactions {
+		if interaction.used || *interaction.Request != *request {
+			continue
+		}
+		interaction.used = true
+		return interaction.Response
+	}
+	return nil
+}
+
+func save() error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
@@ -0,0 +1,145 @@
+package cassette_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/cassette"
+)
+
+var _ = Describe("Cassette", func() {
+	var (
+		dir    string
+		server *httptest.Server
+		calls  int
+		client *http.Client
+	)
+
+	args := []string{"describe", "cluster", "-c", "mycluster"}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+		}))
+		client = &http.Client{Transport: cassette.Transport(http.DefaultTransport)}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.Unsetenv(cassette.ModeEnv)
+		os.Unsetenv(cassette.DirEnv)
+	})
+
+	start := func(mode string) {
+		os.Setenv(cassette.ModeEnv, mode)
+		os.Setenv(cassette.DirEnv, dir)
+		Expect(cassette.Start("rosa describe cluster", args)).To(Succeed())
+	}
+
+	send := func(method string, path string, body string) (string, error) {
+		request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		response, err := client.Do(request)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+		data, err := io.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data), nil
+	}
+
+	It("Replays the recorded requests without sending them", func() {
+		start(cassette.ModeRecord)
+		_, err := send(http.MethodPost, "/token", "grant_type=refresh_token&refresh_token=first-token")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = send(http.MethodGet, "/api/clusters?search=name&page=1", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(2))
+
+		data, err := os.ReadFile(dir + "/" + mustFindCassette(dir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("first-token"))
+
+		start(cassette.ModeReplay)
+		body, err := send(http.MethodPost, "/token", "refresh_token=second-token&grant_type=refresh_token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(MatchJSON(`{"path": "/token"}`))
+		body, err = send(http.MethodGet, "/api/clusters?page=1&search=name", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(MatchJSON(`{"path": "/api/clusters"}`))
+		Expect(calls).To(Equal(2))
+
+		_, err = send(http.MethodGet, "/api/clusters?page=1&search=name", "")
+		Expect(err).To(MatchError(ContainSubstring("isn't recorded in cassette")))
+	})
+
+	It("Redacts the tokens of the responses and replays them as placeholders", func() {
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "my-access-token", "refresh_token": "my-refresh-token", ` +
+				`"expires_in": 900}`))
+		})
+		start(cassette.ModeRecord)
+		body, err := send(http.MethodPost, "/token", "grant_type=refresh_token&refresh_token=first-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(ContainSubstring("my-access-token"))
+
+		data, err := os.ReadFile(dir + "/" + mustFindCassette(dir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("my-access-token"))
+		Expect(string(data)).NotTo(ContainSubstring("my-refresh-token"))
+
+		start(cassette.ModeReplay)
+		body, err = send(http.MethodPost, "/token", "grant_type=refresh_token&refresh_token=second-token")
+		Expect(err).NotTo(HaveOccurred())
+		var tokens map[string]interface{}
+		Expect(json.Unmarshal([]byte(body), &tokens)).To(Succeed())
+		Expect(tokens).To(HaveKeyWithValue("expires_in", BeNumerically("==", 900)))
+		parts := strings.Split(tokens["access_token"].(string), ".")
+		Expect(parts).To(HaveLen(3))
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims).To(ContainSubstring(`"typ":"Bearer"`))
+		Expect(claims).To(ContainSubstring(`"exp":`))
+		Expect(tokens["refresh_token"]).To(ContainSubstring("."))
+	})
+
+	It("Fails to replay a command that wasn't recorded", func() {
+		os.Setenv(cassette.ModeEnv, cassette.ModeReplay)
+		os.Setenv(cassette.DirEnv, dir)
+
+		err := cassette.Start("rosa describe cluster", args)
+
+		Expect(err).To(MatchError(ContainSubstring("Failed to read the cassette of command")))
+	})
+
+	It("Rejects unknown modes", func() {
+		os.Setenv(cassette.ModeEnv, "rewind")
+
+		Expect(cassette.Start("rosa describe cluster", args)).To(
+			MatchError("Invalid cassette mode 'rewind'. Allowed values are [record replay]"))
+	})
+})
+
+func mustFindCassette(dir string) string {
+	entries, err := os.ReadDir(dir)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(entries).To(HaveLen(1))
+	Expect(entries[0].Name()).To(HavePrefix("rosa_describe_cluster-"))
+	return entries[0].Name()
+}
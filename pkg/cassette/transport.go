@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the http.RoundTripper interface that records and
+// replays the requests.
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/openshift/rosa/pkg/har"
+)
+
+// ignoredFields are the fields whose values are generated for each request, so they are ignored
+// when requests are matched.
+var ignoredFields = map[string]bool{
+	"ClientToken":        true,
+	"ClientRequestToken": true,
+	"IdempotencyToken":   true,
+}
+
+// placeholderTokens are the fields of the responses of the authentication service whose redacted
+// values are replaced with placeholder tokens when they are replayed, and the types of the tokens.
+var placeholderTokens = map[string]string{
+	"access_token":  "Bearer",
+	"refresh_token": "Refresh",
+	"id_token":      "ID",
+}
+
+// transport is a round tripper that records or replays the requests.
+type transport struct {
+	next http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &transport{}
+
+// Transport returns a round tripper that records the requests sent with the next round tripper and
+// their responses, or that replays them without calling the next round tripper. It has the
+// signature of the OCM SDK transport wrappers, so that it can be used directly as one.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{
+		next: next,
+	}
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		err = request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewBuffer(body))
+	}
+	normalized := normalizeRequest(request, body)
+
+	if mode == ModeReplay {
+		recorded := replay(normalized)
+		if recorded == nil {
+			return nil, fmt.Errorf("Request '%s %s' isn't recorded in cassette '%s'", normalized.Method,
+				normalized.URL, file)
+		}
+		responseBody := addPlaceholders(recorded.Header.Get("Content-Type"), []byte(recorded.Body))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+			StatusCode:    recorded.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewBuffer(responseBody)),
+			ContentLength: int64(len(responseBody)),
+			Request:       request,
+		}, nil
+	}
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	var responseBody []byte
+	if response.Body != nil {
+		responseBody, err = io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		err = response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = io.NopCloser(bytes.NewBuffer(responseBody))
+	}
+	header := response.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Content-Length")
+	err = record(&Interaction{
+		Request: normalized,
+		Response: &Response{
+			Status: response.StatusCode,
+			Header: header,
+			Body:   string(har.RedactBody(header.Get("Content-Type"), responseBody)),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write cassette '%s': %v", file, err)
+	}
+	return response, nil
+}
+
+// normalizeRequest returns the parts of the request that are used to match it.
+func normalizeRequest(request *http.Request, body []byte) *Request {
+	requestURL := *request.URL
+	requestURL.User = nil
+	requestURL.RawQuery = normalizeForm(requestURL.Query())
+	contentType := request.Header.Get("Content-Type")
+	body = har.RedactBody(contentType, body)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err == nil {
+			body = []byte(normalizeForm(form))
+		}
+	case "application/json", "application/x-amz-json-1.0", "application/x-amz-json-1.1":
+		body = normalizeJSON(body)
+	}
+	return &Request{
+		Method: request.Method,
+		URL:    requestURL.String(),
+		Body:   string(body),
+	}
+}
+
+// normalizeForm returns the query string or form sorted by name, and without the values of the
+// ignored fields. It also redacts the sensitive values of query strings, which aren't redacted
+// with the body.
+func normalizeForm(values url.Values) string {
+	for name := range values {
+		if ignoredFields[name] {
+			values.Set(name, "")
+		}
+	}
+	redacted := har.RedactBody("application/x-www-form-urlencoded", []byte(values.Encode()))
+	parsed, err := url.ParseQuery(string(redacted))
+	if err != nil {
+		return string(redacted)
+	}
+	return parsed.Encode()
+}
+
+// normalizeJSON returns the JSON document with the fields sorted by name, and without the values
+// of the ignored fields. The document is returned unchanged if it can't be parsed.
+func normalizeJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err != nil {
+		return body
+	}
+	if fields, ok := document.(map[string]interface{}); ok {
+		for name := range fields {
+			if ignoredFields[name] {
+				fields[name] = ""
+			}
+		}
+	}
+	result, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return result
+}
+
+// addPlaceholders returns the JSON document with the redacted tokens replaced with placeholder
+// tokens. The document is returned unchanged if it can't be parsed or if it doesn't contain
+// redacted tokens.
+func addPlaceholders(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	err := decoder.Decode(&fields)
+	if err != nil {
+		return body
+	}
+	changed := false
+	for name, tokenType := range placeholderTokens {
+		if fields[name] == har.Redacted {
+			fields[name] = PlaceholderToken(tokenType)
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return result
+}
+
+// PlaceholderToken returns an unsigned JSON web token of the given type that expires in a day, so
+// that the clients that parse the tokens to check when they expire don't request new ones.
+func PlaceholderToken(tokenType string) string {
+	now := time.Now()
+	claims, _ := json.Marshal(map[string]interface{}{
+		"typ": tokenType,
+		"iat": now.Unix(),
+		"exp": now.Add(24 * time.Hour).Unix(),
+	})
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + ".placeholder"
+}
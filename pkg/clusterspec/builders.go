@@ -0,0 +1,319 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// BuildMachinePool converts the spec of a machine pool into the object sent to OCM. When updating
+// only the fields that can be changed are included.
+func BuildMachinePool(machinePool MachinePool, update bool) (*cmv1.MachinePool, error) {
+	builder := cmv1.NewMachinePool().
+		ID(machinePool.Name)
+	// Labels and taints that the document omits are left as they are:
+	if machinePool.Labels != nil {
+		builder = builder.Labels(machinePool.Labels)
+	}
+	if machinePool.Taints != nil {
+		builder = builder.Taints(buildTaints(machinePool.Taints)...)
+	}
+	if !update {
+		builder = builder.InstanceType(machinePool.InstanceType)
+		if len(machinePool.AvailabilityZones) > 0 {
+			builder = builder.AvailabilityZones(machinePool.AvailabilityZones...)
+		}
+		if len(machinePool.Subnets) > 0 {
+			builder = builder.Subnets(machinePool.Subnets...)
+		}
+	}
+	if machinePool.Autoscaling != nil {
+		builder = builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+			MinReplicas(machinePool.Autoscaling.MinReplicas).
+			MaxReplicas(machinePool.Autoscaling.MaxReplicas))
+	} else if machinePool.Replicas != nil {
+		builder = builder.Replicas(*machinePool.Replicas)
+	}
+	return builder.Build()
+}
+
+// BuildNodePool converts the spec of a machine pool of a hosted control plane cluster into the node
+// pool sent to OCM. When updating only the fields that can be changed are included.
+func BuildNodePool(machinePool MachinePool, update bool) (*cmv1.NodePool, error) {
+	builder := cmv1.NewNodePool().
+		ID(machinePool.Name)
+	// Labels, taints and tuning configs that the document omits are left as they are:
+	if machinePool.Labels != nil {
+		builder = builder.Labels(machinePool.Labels)
+	}
+	if machinePool.Taints != nil {
+		builder = builder.Taints(buildTaints(machinePool.Taints)...)
+	}
+	if machinePool.TuningConfigs != nil {
+		builder = builder.TuningConfigs(machinePool.TuningConfigs...)
+	}
+	if !update {
+		builder = builder.AWSNodePool(cmv1.NewAWSNodePool().InstanceType(machinePool.InstanceType))
+		if len(machinePool.Subnets) > 1 {
+			return nil, fmt.Errorf("Machine pool '%s' of a hosted cluster accepts a single subnet",
+				machinePool.Name)
+		}
+		if len(machinePool.Subnets) == 1 {
+			builder = builder.Subnet(machinePool.Subnets[0])
+		}
+	}
+	if machinePool.AutoRepair != nil {
+		builder = builder.AutoRepair(*machinePool.AutoRepair)
+	}
+	if machinePool.Autoscaling != nil {
+		builder = builder.Autoscaling(cmv1.NewNodePoolAutoscaling().
+			MinReplica(machinePool.Autoscaling.MinReplicas).
+			MaxReplica(machinePool.Autoscaling.MaxReplicas))
+	} else if machinePool.Replicas != nil {
+		builder = builder.Replicas(*machinePool.Replicas)
+	}
+	return builder.Build()
+}
+
+func buildTaints(taints []Taint) []*cmv1.TaintBuilder {
+	builders := []*cmv1.TaintBuilder{}
+	for _, taint := range taints {
+		builders = append(builders, cmv1.NewTaint().Key(taint.Key).Value(taint.Value).Effect(taint.Effect))
+	}
+	return builders
+}
+
+// BuildIdentityProvider converts the spec of an identity provider into the object sent to OCM. Secrets
+// referencing environment variables are resolved, and must not be empty.
+func BuildIdentityProvider(idp IdentityProvider) (*cmv1.IdentityProvider, error) {
+	builder := cmv1.NewIdentityProvider().Name(idp.Name)
+	if idp.MappingMethod != "" {
+		builder = builder.MappingMethod(cmv1.IdentityProviderMappingMethod(idp.MappingMethod))
+	}
+	missing := func(field string) error {
+		return fmt.Errorf("Identity provider '%s' requires a value for '%s'", idp.Name, field)
+	}
+	switch idp.Type {
+	case "github":
+		if idp.GitHub == nil {
+			return nil, missing("github")
+		}
+		secret, err := ResolveSecret(idp.GitHub.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		if secret == "" {
+			return nil, missing("github.clientSecret")
+		}
+		github := cmv1.NewGithubIdentityProvider().
+			ClientID(idp.GitHub.ClientID).
+			ClientSecret(secret).
+			Organizations(idp.GitHub.Organizations...).
+			Teams(idp.GitHub.Teams...)
+		if idp.GitHub.Hostname != "" {
+			github = github.Hostname(idp.GitHub.Hostname)
+		}
+		if idp.GitHub.CA != "" {
+			github = github.CA(idp.GitHub.CA)
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeGithub).Github(github)
+	case "gitlab":
+		if idp.GitLab == nil {
+			return nil, missing("gitlab")
+		}
+		secret, err := ResolveSecret(idp.GitLab.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		if secret == "" {
+			return nil, missing("gitlab.clientSecret")
+		}
+		gitlab := cmv1.NewGitlabIdentityProvider().
+			URL(idp.GitLab.URL).
+			ClientID(idp.GitLab.ClientID).
+			ClientSecret(secret)
+		if idp.GitLab.CA != "" {
+			gitlab = gitlab.CA(idp.GitLab.CA)
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeGitlab).Gitlab(gitlab)
+	case "google":
+		if idp.Google == nil {
+			return nil, missing("google")
+		}
+		secret, err := ResolveSecret(idp.Google.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		if secret == "" {
+			return nil, missing("google.clientSecret")
+		}
+		google := cmv1.NewGoogleIdentityProvider().
+			ClientID(idp.Google.ClientID).
+			ClientSecret(secret)
+		if idp.Google.HostedDomain != "" {
+			google = google.HostedDomain(idp.Google.HostedDomain)
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeGoogle).Google(google)
+	case "openid":
+		if idp.OpenID == nil {
+			return nil, missing("openid")
+		}
+		secret, err := ResolveSecret(idp.OpenID.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		if secret == "" {
+			return nil, missing("openid.clientSecret")
+		}
+		openID := cmv1.NewOpenIDIdentityProvider().
+			Issuer(idp.OpenID.Issuer).
+			ClientID(idp.OpenID.ClientID).
+			ClientSecret(secret).
+			ExtraScopes(idp.OpenID.ExtraScopes...)
+		if idp.OpenID.CA != "" {
+			openID = openID.CA(idp.OpenID.CA)
+		}
+		if idp.OpenID.Claims != nil {
+			openID = openID.Claims(cmv1.NewOpenIDClaims().
+				Email(idp.OpenID.Claims.Email...).
+				Groups(idp.OpenID.Claims.Groups...).
+				Name(idp.OpenID.Claims.Name...).
+				PreferredUsername(idp.OpenID.Claims.PreferredUsername...))
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeOpenID).OpenID(openID)
+	case "ldap":
+		if idp.LDAP == nil {
+			return nil, missing("ldap")
+		}
+		ldap := cmv1.NewLDAPIdentityProvider().
+			URL(idp.LDAP.URL).
+			Insecure(isTrue(idp.LDAP.Insecure))
+		if idp.LDAP.BindDN != "" {
+			password, err := ResolveSecret(idp.LDAP.BindPassword)
+			if err != nil {
+				return nil, err
+			}
+			ldap = ldap.BindDN(idp.LDAP.BindDN).BindPassword(password)
+		}
+		if idp.LDAP.CA != "" {
+			ldap = ldap.CA(idp.LDAP.CA)
+		}
+		if idp.LDAP.Attributes != nil {
+			ldap = ldap.Attributes(cmv1.NewLDAPAttributes().
+				ID(idp.LDAP.Attributes.ID...).
+				Email(idp.LDAP.Attributes.Email...).
+				Name(idp.LDAP.Attributes.Name...).
+				PreferredUsername(idp.LDAP.Attributes.PreferredUsername...))
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeLDAP).LDAP(ldap)
+	case "htpasswd":
+		if idp.HTPasswd == nil || len(idp.HTPasswd.Users) == 0 {
+			return nil, missing("htpasswd.users")
+		}
+		users := []*cmv1.HTPasswdUserBuilder{}
+		for _, user := range idp.HTPasswd.Users {
+			password, err := ResolveSecret(user.Password)
+			if err != nil {
+				return nil, err
+			}
+			if password == "" {
+				return nil, fmt.Errorf("Identity provider '%s' requires a password for user '%s'",
+					idp.Name, user.Username)
+			}
+			users = append(users, cmv1.NewHTPasswdUser().Username(user.Username).Password(password))
+		}
+		builder = builder.Type(cmv1.IdentityProviderTypeHtpasswd).Htpasswd(
+			cmv1.NewHTPasswdIdentityProvider().Users(cmv1.NewHTPasswdUserList().Items(users...)))
+	default:
+		return nil, fmt.Errorf("Identity provider '%s' has an unsupported type '%s'", idp.Name, idp.Type)
+	}
+	return builder.Build()
+}
+
+// secretReferenceRE matches the references to environment variables in the values of secret fields.
+// Only the '${NAME}' syntax is supported, so that secrets can contain '$' characters.
+var secretReferenceRE = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ResolveSecret expands references to environment variables, using the '${NAME}' syntax, in the value
+// of a secret field. It fails if any of the variables isn't set.
+func ResolveSecret(value string) (string, error) {
+	var missing []string
+	result := secretReferenceRE.ReplaceAllStringFunc(value, func(reference string) string {
+		name := secretReferenceRE.FindStringSubmatch(reference)[1]
+		variable, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return variable
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("Environment variable '%s' referenced in the spec isn't set", missing[0])
+	}
+	return result, nil
+}
+
+// BuildIngress converts the spec of an ingress into the object sent to OCM.
+func BuildIngress(ingress Ingress, id string) (*cmv1.Ingress, error) {
+	builder := cmv1.NewIngress()
+	if id != "" {
+		builder = builder.ID(id)
+	}
+	// The listening method is left as it is when the document doesn't contain it:
+	if ingress.Private != nil {
+		if *ingress.Private {
+			builder = builder.Listening(cmv1.ListeningMethodInternal)
+		} else {
+			builder = builder.Listening(cmv1.ListeningMethodExternal)
+		}
+	}
+	if ingress.RouteSelectors != nil {
+		builder = builder.RouteSelectors(ingress.RouteSelectors)
+	}
+	return builder.Build()
+}
+
+// BuildTuningConfig converts the spec of a tuning config into the object sent to OCM.
+func BuildTuningConfig(tuningConfig TuningConfig, id string) (*cmv1.TuningConfig, error) {
+	builder := cmv1.NewTuningConfig().Spec(tuningConfig.Spec)
+	if id != "" {
+		builder = builder.ID(id)
+	} else {
+		builder = builder.Name(tuningConfig.Name)
+	}
+	return builder.Build()
+}
+
+// BuildAddOnParams converts the parameters of an add-on into the list used by the OCM client.
+func BuildAddOnParams(addOn AddOn) []ocm.AddOnParam {
+	keys := make([]string, 0, len(addOn.Parameters))
+	for key := range addOn.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := []ocm.AddOnParam{}
+	for _, key := range keys {
+		params = append(params, ocm.AddOnParam{Key: key, Val: addOn.Parameters[key]})
+	}
+	return params
+}
@@ -0,0 +1,32 @@
+package clusterspec
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveSecret", func() {
+	BeforeEach(func() {
+		os.Setenv("ROSA_TEST_SECRET", "s3cr3t")
+		DeferCleanup(os.Unsetenv, "ROSA_TEST_SECRET")
+	})
+
+	It("Expands the references to environment variables", func() {
+		value, err := ResolveSecret("${ROSA_TEST_SECRET}")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("s3cr3t"))
+	})
+
+	It("Keeps other dollar signs", func() {
+		value, err := ResolveSecret("pa$$word$ROSA_TEST_SECRET")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("pa$$word$ROSA_TEST_SECRET"))
+	})
+
+	It("Fails if a variable isn't set", func() {
+		_, err := ResolveSecret("${ROSA_TEST_MISSING_SECRET}")
+		Expect(err).To(MatchError(ContainSubstring("ROSA_TEST_MISSING_SECRET")))
+	})
+})
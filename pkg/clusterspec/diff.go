@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PatchOperation is a single operation of a JSON patch, as described in RFC 6902.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+
+	// from is the previous value, used only to describe the operation to humans.
+	from interface{}
+	// field is the human readable location of the value.
+	field string
+}
+
+// MarshalJSON writes the operation omitting the value only for 'remove' operations. The value of the
+// 'add' and 'replace' operations is required even when it is false, zero or empty.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type operation PatchOperation
+	return json.Marshal(operation(o))
+}
+
+func (o PatchOperation) String() string {
+	switch o.Op {
+	case "add":
+		return fmt.Sprintf("+ %s: %s", o.field, render(o.Value))
+	case "remove":
+		return fmt.Sprintf("- %s: %s", o.field, render(o.from))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", o.field, render(o.from), render(o.Value))
+	}
+}
+
+// DiffOptions controls which differences are reported by Diff.
+type DiffOptions struct {
+	// IgnoreMissing skips the fields that are present in the first document but not in the second
+	// one. It is used when comparing a cluster with a spec file, where omitted fields mean that the
+	// value doesn't matter.
+	IgnoreMissing bool
+}
+
+// Diff compares two documents and returns the JSON patch that transforms the first into the second.
+// Fields that are specific to each cluster, like the name, the tags or the prefix of the operator
+// roles, and secrets, which are never exported, aren't compared. Lists of named resources are matched
+// by name instead of by position.
+func Diff(from, to *Cluster, options DiffOptions) ([]PatchOperation, error) {
+	a, err := comparable(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := comparable(to)
+	if err != nil {
+		return nil, err
+	}
+	differ := &differ{options: options}
+	differ.compare("", "", a, b)
+	return differ.operations, nil
+}
+
+// comparable returns the generic JSON representation of the parts of the document that are compared.
+func comparable(c *Cluster) (interface{}, error) {
+	result := *c
+	result.Name = ""
+	result.Tags = nil
+	result.Properties = nil
+	if c.STS != nil {
+		sts := *c.STS
+		sts.OperatorRolesPrefix = ""
+		sts.OidcConfigID = ""
+		result.STS = &sts
+	}
+	result.IdentityProviders = nil
+	for _, idp := range c.IdentityProviders {
+		idp = withoutSecrets(idp)
+		if idp.HTPasswd != nil {
+			htpasswd := &HTPasswdIdentityProvider{}
+			for _, user := range idp.HTPasswd.Users {
+				htpasswd.Users = append(htpasswd.Users, HTPasswdUser{Username: user.Username})
+			}
+			idp.HTPasswd = htpasswd
+		}
+		result.IdentityProviders = append(result.IdentityProviders, idp)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// listKeys contains, for the lists of resources, the function that returns the identity of each
+// element, so that elements are matched regardless of their position.
+var listKeys = map[string]func(element map[string]interface{}) string{
+	"machinePools":      keyField("name"),
+	"identityProviders": keyField("name"),
+	"tuningConfigs":     keyField("name"),
+	"addons":            keyField("id"),
+	"users":             keyField("username"),
+	"ingresses": func(element map[string]interface{}) string {
+		if element["default"] == true {
+			return "default"
+		}
+		return render(element["routeSelectors"])
+	},
+}
+
+func keyField(name string) func(element map[string]interface{}) string {
+	return func(element map[string]interface{}) string {
+		return fmt.Sprintf("%v", element[name])
+	}
+}
+
+type differ struct {
+	options    DiffOptions
+	operations []PatchOperation
+}
+
+func (d *differ) add(op, path, field string, from, to interface{}) {
+	d.operations = append(d.operations, PatchOperation{
+		Op:    op,
+		Path:  path,
+		Value: to,
+		from:  from,
+		field: strings.TrimPrefix(field, "."),
+	})
+}
+
+func (d *differ) compare(path, field string, a, b interface{}) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			d.compareObjects(path, field, av, bv)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			name := field[strings.LastIndex(field, ".")+1:]
+			if key, ok := listKeys[name]; ok {
+				d.compareKeyedLists(path, field, av, bv, key)
+				return
+			}
+		}
+	}
+	if render(a) != render(b) {
+		d.add("replace", path, field, a, b)
+	}
+}
+
+func (d *differ) compareObjects(path, field string, a, b map[string]interface{}) {
+	names := map[string]bool{}
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		childPath := path + "/" + escapePointer(name)
+		childField := field + "." + name
+		av, inA := a[name]
+		bv, inB := b[name]
+		switch {
+		case inA && !inB:
+			if !d.options.IgnoreMissing {
+				d.add("remove", childPath, childField, av, nil)
+			}
+		case !inA && inB:
+			d.add("add", childPath, childField, nil, bv)
+		default:
+			d.compare(childPath, childField, av, bv)
+		}
+	}
+}
+
+// compareKeyedLists matches the elements of the lists by key. Changes to existing elements are
+// reported first, using their original position, then removals from the end of the list so that the
+// positions stay valid, and finally additions at the end of the list.
+func (d *differ) compareKeyedLists(path, field string, a, b []interface{},
+	key func(map[string]interface{}) string) {
+	keyOf := func(element interface{}) string {
+		if object, ok := element.(map[string]interface{}); ok {
+			return key(object)
+		}
+		return render(element)
+	}
+	indexA := map[string]int{}
+	for i, element := range a {
+		indexA[keyOf(element)] = i
+	}
+	indexB := map[string]bool{}
+	for _, element := range b {
+		k := keyOf(element)
+		indexB[k] = true
+		if i, ok := indexA[k]; ok {
+			d.compare(fmt.Sprintf("%s/%d", path, i), fmt.Sprintf("%s[%s]", field, k), a[i], element)
+		}
+	}
+	// Elements that the second list doesn't contain are ignored like the missing fields:
+	for i := len(a) - 1; i >= 0 && !d.options.IgnoreMissing; i-- {
+		k := keyOf(a[i])
+		if !indexB[k] {
+			d.add("remove", fmt.Sprintf("%s/%d", path, i), fmt.Sprintf("%s[%s]", field, k), a[i], nil)
+		}
+	}
+	for _, element := range b {
+		k := keyOf(element)
+		if _, ok := indexA[k]; !ok {
+			d.add("add", path+"/-", fmt.Sprintf("%s[%s]", field, k), nil, element)
+		}
+	}
+}
+
+// escapePointer escapes a name so that it can be used as a JSON pointer token, see RFC 6901.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,117 @@
+package clusterspec
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var current *Cluster
+
+	BeforeEach(func() {
+		current = &Cluster{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+			Name:       "prod",
+			Version:    "4.12.5",
+			Network:    &Network{MachineCIDR: "10.0.0.0/16", HostPrefix: 23},
+			STS:        &STS{RoleARN: "arn:aws:iam::123456789012:role/Installer", OperatorRolesPrefix: "prod-a"},
+			Tags:       map[string]string{"env": "prod"},
+			MachinePools: []MachinePool{
+				{Name: "infra", InstanceType: "r5.xlarge", Replicas: intPtr(3)},
+				{Name: "old", InstanceType: "m5.xlarge", Replicas: intPtr(2)},
+			},
+		}
+	})
+
+	It("Ignores fields that are specific to each cluster", func() {
+		other := *current
+		other.Name = "staging"
+		other.Tags = map[string]string{"env": "staging"}
+		other.STS = &STS{RoleARN: current.STS.RoleARN, OperatorRolesPrefix: "staging-b"}
+		patch, err := Diff(current, &other, DiffOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(BeEmpty())
+	})
+
+	It("Returns a JSON patch matching resources by name", func() {
+		other := *current
+		other.Version = "4.13.0"
+		other.MachinePools = []MachinePool{
+			{Name: "gpu", InstanceType: "g4dn.xlarge", Replicas: intPtr(1)},
+			{Name: "infra", InstanceType: "r5.xlarge", Replicas: intPtr(5)},
+		}
+		patch, err := Diff(current, &other, DiffOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(HaveLen(4))
+		Expect(patch[0].String()).To(Equal("~ machinePools[infra].replicas: 3 -> 5"))
+		Expect(patch[0].Path).To(Equal("/machinePools/0/replicas"))
+		Expect(patch[1].Op).To(Equal("remove"))
+		Expect(patch[1].Path).To(Equal("/machinePools/1"))
+		Expect(patch[2].Op).To(Equal("add"))
+		Expect(patch[2].Path).To(Equal("/machinePools/-"))
+		Expect(patch[3].String()).To(Equal(`~ version: "4.12.5" -> "4.13.0"`))
+
+		body, err := json.Marshal(patch[3])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"op":"replace","path":"/version","value":"4.13.0"}`))
+	})
+
+	It("Ignores fields omitted from a spec file", func() {
+		spec := &Cluster{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+			Version:    "4.12.5",
+		}
+		patch, err := Diff(current, spec, DiffOptions{IgnoreMissing: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(BeEmpty())
+	})
+
+	It("Ignores resources omitted from a spec file", func() {
+		spec := &Cluster{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+			MachinePools: []MachinePool{
+				{Name: "infra", Replicas: intPtr(5)},
+			},
+		}
+		patch, err := Diff(current, spec, DiffOptions{IgnoreMissing: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(HaveLen(1))
+		Expect(patch[0].String()).To(Equal("~ machinePools[infra].replicas: 3 -> 5"))
+	})
+
+	It("Writes false, zero and empty values of the operations", func() {
+		other := *current
+		other.Network = &Network{MachineCIDR: "10.0.0.0/16", HostPrefix: 23, Private: boolPtr(false)}
+		other.MachinePools = []MachinePool{
+			{Name: "infra", InstanceType: "r5.xlarge", Replicas: intPtr(0)},
+			{Name: "old", InstanceType: "m5.xlarge", Replicas: intPtr(2)},
+		}
+		patch, err := Diff(current, &other, DiffOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(HaveLen(2))
+
+		body, err := json.Marshal(patch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(`[` +
+			`{"op":"replace","path":"/machinePools/0/replicas","value":0},` +
+			`{"op":"add","path":"/network/private","value":false}]`))
+	})
+
+	It("Compares explicit false values of a spec file", func() {
+		current.Ingresses = []Ingress{{Default: boolPtr(true), Private: boolPtr(true)}}
+		spec := &Cluster{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+			Ingresses:  []Ingress{{Default: boolPtr(true), Private: boolPtr(false)}},
+		}
+		patch, err := Diff(current, spec, DiffOptions{IgnoreMissing: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch).To(HaveLen(1))
+		Expect(patch[0].String()).To(Equal("~ ingresses[default].private: true -> false"))
+	})
+})
@@ -0,0 +1,435 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Resources contains the day 2 resources of a cluster that are exported along with it.
+type Resources struct {
+	MachinePools      []*cmv1.MachinePool
+	NodePools         []*cmv1.NodePool
+	IdentityProviders []*cmv1.IdentityProvider
+	Ingresses         []*cmv1.Ingress
+	TuningConfigs     []*cmv1.TuningConfig
+	AddOns            []*cmv1.AddOnInstallation
+
+	// HTPasswdUsers contains the user names of each htpasswd identity provider, indexed by the
+	// identifier of the identity provider.
+	HTPasswdUsers map[string][]string
+}
+
+// FetchResources loads from OCM the day 2 resources of the given cluster.
+func FetchResources(client *ocm.Client, cluster *cmv1.Cluster) (*Resources, error) {
+	var err error
+	resources := &Resources{
+		HTPasswdUsers: map[string][]string{},
+	}
+	if cluster.Hypershift().Enabled() {
+		resources.NodePools, err = client.GetNodePools(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get node pools: %v", err)
+		}
+		resources.TuningConfigs, err = client.GetTuningConfigs(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get tuning configs: %v", err)
+		}
+	} else {
+		resources.MachinePools, err = client.GetMachinePools(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get machine pools: %v", err)
+		}
+	}
+	resources.IdentityProviders, err = client.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get identity providers: %v", err)
+	}
+	for _, idp := range resources.IdentityProviders {
+		if idp.Type() != cmv1.IdentityProviderTypeHtpasswd {
+			continue
+		}
+		users, err := client.GetHTPasswdUserList(cluster.ID(), idp.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get users of identity provider '%s': %v", idp.Name(), err)
+		}
+		users.Each(func(user *cmv1.HTPasswdUser) bool {
+			resources.HTPasswdUsers[idp.ID()] = append(resources.HTPasswdUsers[idp.ID()], user.Username())
+			return true
+		})
+	}
+	resources.Ingresses, err = client.GetIngresses(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get ingresses: %v", err)
+	}
+	addOns, err := client.GetClusterAddOns(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get add-ons: %v", err)
+	}
+	for _, addOn := range addOns {
+		if addOn.State == "not installed" || addOn.State == "unavailable" {
+			continue
+		}
+		installation, err := client.GetAddOnInstallation(cluster.ID(), addOn.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get add-on installation '%s': %v", addOn.ID, err)
+		}
+		resources.AddOns = append(resources.AddOns, installation)
+	}
+	return resources, nil
+}
+
+// Export converts an existing cluster, and optionally its day 2 resources, into a spec document that
+// can be used to create a new cluster with the same shape. Read-only fields such as identifiers, DNS
+// names, status and the properties managed by the tool are dropped, and so are secrets. The operator
+// roles prefix and the OIDC configuration are dropped too, so that the new cluster gets its own
+// operator roles instead of sharing those of the exported cluster. The OIDC configuration of hosted
+// control plane clusters is kept, as they can't be created without one.
+func Export(cluster *cmv1.Cluster, resources *Resources) *Cluster {
+	spec := &Cluster{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Name:       cluster.Name(),
+		Region:     cluster.Region().ID(),
+		Version:    cluster.Version().RawID(),
+	}
+	if channelGroup := cluster.Version().ChannelGroup(); channelGroup != ocm.DefaultChannelGroup {
+		spec.ChannelGroup = channelGroup
+	}
+	if cluster.MultiAZ() {
+		spec.MultiAZ = boolPtr(true)
+	}
+	if cluster.DisableUserWorkloadMonitoring() {
+		spec.DisableWorkloadMonitoring = boolPtr(true)
+	}
+	if cluster.CCS().DisableSCPChecks() {
+		spec.DisableSCPChecks = boolPtr(true)
+	}
+
+	awsSpec := cluster.AWS()
+	if cluster.FIPS() || cluster.EtcdEncryption() || awsSpec.KMSKeyArn() != "" ||
+		awsSpec.EtcdEncryption().KMSKeyARN() != "" {
+		spec.Encryption = &Encryption{
+			KMSKeyArn:            awsSpec.KMSKeyArn(),
+			EtcdEncryptionKMSArn: awsSpec.EtcdEncryption().KMSKeyARN(),
+		}
+		if cluster.FIPS() {
+			spec.Encryption.FIPS = boolPtr(true)
+		}
+		if cluster.EtcdEncryption() {
+			spec.Encryption.EtcdEncryption = boolPtr(true)
+		}
+	}
+
+	spec.Compute = exportCompute(cluster.Nodes())
+
+	// Availability zones are derived from the subnets for clusters that use an existing VPC, so
+	// they can only be given for the others:
+	if len(awsSpec.SubnetIDs()) == 0 && len(cluster.Nodes().AvailabilityZones()) > 0 {
+		spec.AvailabilityZones = cluster.Nodes().AvailabilityZones()
+	}
+
+	network := cluster.Network()
+	spec.Network = &Network{
+		Type:        network.Type(),
+		MachineCIDR: network.MachineCIDR(),
+		ServiceCIDR: network.ServiceCIDR(),
+		PodCIDR:     network.PodCIDR(),
+		HostPrefix:  network.HostPrefix(),
+		SubnetIDs:   awsSpec.SubnetIDs(),
+	}
+	if cluster.API().Listening() == cmv1.ListeningMethodInternal {
+		spec.Network.Private = boolPtr(true)
+	}
+	if awsSpec.PrivateLink() {
+		spec.Network.PrivateLink = boolPtr(true)
+	}
+
+	sts := awsSpec.STS()
+	if sts.RoleARN() != "" {
+		spec.STS = &STS{
+			Enabled:             boolPtr(true),
+			RoleARN:             sts.RoleARN(),
+			ExternalID:          sts.ExternalID(),
+			SupportRoleARN:      sts.SupportRoleARN(),
+			ControlPlaneRoleARN: sts.InstanceIAMRoles().MasterRoleARN(),
+			WorkerRoleARN:       sts.InstanceIAMRoles().WorkerRoleARN(),
+			PermissionsBoundary: sts.PermissionBoundary(),
+		}
+		if cluster.Hypershift().Enabled() {
+			spec.STS.OidcConfigID = sts.OidcConfig().ID()
+		}
+	} else {
+		spec.STS = &STS{
+			Enabled: boolPtr(false),
+		}
+	}
+
+	proxy := cluster.Proxy()
+	if proxy.HTTPProxy() != "" || proxy.HTTPSProxy() != "" || proxy.NoProxy() != "" {
+		spec.Proxy = &Proxy{
+			HTTPProxy:  proxy.HTTPProxy(),
+			HTTPSProxy: proxy.HTTPSProxy(),
+		}
+		if proxy.NoProxy() != "" {
+			spec.Proxy.NoProxy = strings.Split(proxy.NoProxy(), ",")
+		}
+	}
+
+	if cluster.Hypershift().Enabled() {
+		spec.Hypershift = &Hypershift{
+			Enabled:         boolPtr(true),
+			BillingAccount:  awsSpec.BillingAccountID(),
+			AuditLogRoleARN: awsSpec.AuditLog().RoleArn(),
+		}
+	}
+
+	// Tags starting with 'red-hat-' are added by the service and can't be set by users:
+	for key, value := range awsSpec.Tags() {
+		if strings.HasPrefix(key, "red-hat-") {
+			continue
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[key] = value
+	}
+
+	if resources != nil {
+		exportResources(spec, resources)
+	}
+
+	return spec
+}
+
+func exportCompute(nodes *cmv1.ClusterNodes) *Compute {
+	if nodes.Empty() {
+		return nil
+	}
+	compute := &Compute{
+		MachineType: nodes.ComputeMachineType().ID(),
+		Labels:      nodes.ComputeLabels(),
+	}
+	if autoscaling, ok := nodes.GetAutoscaleCompute(); ok {
+		compute.Autoscaling = &Autoscaling{
+			MinReplicas: autoscaling.MinReplicas(),
+			MaxReplicas: autoscaling.MaxReplicas(),
+		}
+	} else if replicas, ok := nodes.GetCompute(); ok {
+		compute.Replicas = intPtr(replicas)
+	}
+	return compute
+}
+
+func exportResources(spec *Cluster, resources *Resources) {
+	for _, machinePool := range resources.MachinePools {
+		spec.MachinePools = append(spec.MachinePools, ExportMachinePool(machinePool))
+	}
+	for _, nodePool := range resources.NodePools {
+		spec.MachinePools = append(spec.MachinePools, ExportNodePool(nodePool))
+	}
+	for _, idp := range resources.IdentityProviders {
+		spec.IdentityProviders = append(spec.IdentityProviders,
+			ExportIdentityProvider(idp, resources.HTPasswdUsers[idp.ID()]))
+	}
+	for _, ingress := range resources.Ingresses {
+		spec.Ingresses = append(spec.Ingresses, ExportIngress(ingress))
+	}
+	for _, tuningConfig := range resources.TuningConfigs {
+		spec.TuningConfigs = append(spec.TuningConfigs, TuningConfig{
+			Name: tuningConfig.Name(),
+			Spec: tuningConfig.Spec(),
+		})
+	}
+	for _, addOn := range resources.AddOns {
+		spec.AddOns = append(spec.AddOns, ExportAddOn(addOn))
+	}
+}
+
+// ExportAddOn converts an add-on installation into its spec representation.
+func ExportAddOn(installation *cmv1.AddOnInstallation) AddOn {
+	result := AddOn{
+		ID: installation.Addon().ID(),
+	}
+	installation.Parameters().Each(func(parameter *cmv1.AddOnInstallationParameter) bool {
+		if result.Parameters == nil {
+			result.Parameters = map[string]string{}
+		}
+		result.Parameters[parameter.ID()] = parameter.Value()
+		return true
+	})
+	return result
+}
+
+// ExportMachinePool converts a machine pool of a classic cluster into its spec representation.
+func ExportMachinePool(machinePool *cmv1.MachinePool) MachinePool {
+	result := MachinePool{
+		Name:              machinePool.ID(),
+		InstanceType:      machinePool.InstanceType(),
+		Labels:            machinePool.Labels(),
+		Taints:            exportTaints(machinePool.Taints()),
+		AvailabilityZones: machinePool.AvailabilityZones(),
+		Subnets:           machinePool.Subnets(),
+	}
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		result.Autoscaling = &Autoscaling{
+			MinReplicas: autoscaling.MinReplicas(),
+			MaxReplicas: autoscaling.MaxReplicas(),
+		}
+	} else {
+		result.Replicas = intPtr(machinePool.Replicas())
+	}
+	return result
+}
+
+// ExportNodePool converts a node pool of a hosted control plane cluster into its spec representation.
+func ExportNodePool(nodePool *cmv1.NodePool) MachinePool {
+	result := MachinePool{
+		Name:          nodePool.ID(),
+		InstanceType:  nodePool.AWSNodePool().InstanceType(),
+		Labels:        nodePool.Labels(),
+		Taints:        exportTaints(nodePool.Taints()),
+		AutoRepair:    boolPtr(nodePool.AutoRepair()),
+		TuningConfigs: nodePool.TuningConfigs(),
+	}
+	if nodePool.Subnet() != "" {
+		result.Subnets = []string{nodePool.Subnet()}
+	}
+	if autoscaling, ok := nodePool.GetAutoscaling(); ok {
+		result.Autoscaling = &Autoscaling{
+			MinReplicas: autoscaling.MinReplica(),
+			MaxReplicas: autoscaling.MaxReplica(),
+		}
+	} else {
+		result.Replicas = intPtr(nodePool.Replicas())
+	}
+	return result
+}
+
+func exportTaints(taints []*cmv1.Taint) []Taint {
+	var result []Taint
+	for _, taint := range taints {
+		result = append(result, Taint{
+			Key:    taint.Key(),
+			Value:  taint.Value(),
+			Effect: taint.Effect(),
+		})
+	}
+	return result
+}
+
+// ExportIdentityProvider converts an identity provider into its spec representation. Secrets aren't
+// returned by OCM, so they are always left empty.
+func ExportIdentityProvider(idp *cmv1.IdentityProvider, htpasswdUsers []string) IdentityProvider {
+	result := IdentityProvider{
+		Name:          idp.Name(),
+		Type:          strings.ToLower(ocm.IdentityProviderType(idp)),
+		MappingMethod: string(idp.MappingMethod()),
+	}
+	switch idp.Type() {
+	case cmv1.IdentityProviderTypeGithub:
+		github := idp.Github()
+		result.GitHub = &GitHubIdentityProvider{
+			ClientID:      github.ClientID(),
+			Hostname:      github.Hostname(),
+			Organizations: github.Organizations(),
+			Teams:         github.Teams(),
+			CA:            github.CA(),
+		}
+	case cmv1.IdentityProviderTypeGitlab:
+		gitlab := idp.Gitlab()
+		result.GitLab = &GitLabIdentityProvider{
+			URL:      gitlab.URL(),
+			ClientID: gitlab.ClientID(),
+			CA:       gitlab.CA(),
+		}
+	case cmv1.IdentityProviderTypeGoogle:
+		google := idp.Google()
+		result.Google = &GoogleIdentityProvider{
+			ClientID:     google.ClientID(),
+			HostedDomain: google.HostedDomain(),
+		}
+	case cmv1.IdentityProviderTypeOpenID:
+		openID := idp.OpenID()
+		result.OpenID = &OpenIDIdentityProvider{
+			Issuer:      openID.Issuer(),
+			ClientID:    openID.ClientID(),
+			CA:          openID.CA(),
+			ExtraScopes: openID.ExtraScopes(),
+		}
+		if claims, ok := openID.GetClaims(); ok {
+			result.OpenID.Claims = &OpenIDClaims{
+				Email:             claims.Email(),
+				Groups:            claims.Groups(),
+				Name:              claims.Name(),
+				PreferredUsername: claims.PreferredUsername(),
+			}
+		}
+	case cmv1.IdentityProviderTypeLDAP:
+		ldap := idp.LDAP()
+		result.LDAP = &LDAPIdentityProvider{
+			URL:      ldap.URL(),
+			BindDN:   ldap.BindDN(),
+			Insecure: boolPtr(ldap.Insecure()),
+			CA:       ldap.CA(),
+		}
+		if attributes, ok := ldap.GetAttributes(); ok {
+			result.LDAP.Attributes = &LDAPAttributes{
+				ID:                attributes.ID(),
+				Email:             attributes.Email(),
+				Name:              attributes.Name(),
+				PreferredUsername: attributes.PreferredUsername(),
+			}
+		}
+	case cmv1.IdentityProviderTypeHtpasswd:
+		sorted := append([]string{}, htpasswdUsers...)
+		sort.Strings(sorted)
+		result.HTPasswd = &HTPasswdIdentityProvider{}
+		for _, username := range sorted {
+			result.HTPasswd.Users = append(result.HTPasswd.Users, HTPasswdUser{Username: username})
+		}
+	}
+	return result
+}
+
+// ExportIngress converts an ingress into its spec representation.
+func ExportIngress(ingress *cmv1.Ingress) Ingress {
+	return Ingress{
+		Default:        boolPtr(ingress.Default()),
+		Private:        boolPtr(ingress.Listening() == cmv1.ListeningMethodInternal),
+		RouteSelectors: ingress.RouteSelectors(),
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+// isTrue returns true when the optional value is present and true.
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
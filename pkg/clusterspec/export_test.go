@@ -0,0 +1,101 @@
+package clusterspec
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Export", func() {
+	It("Exports a cluster without read-only fields", func() {
+		cluster, err := cmv1.NewCluster().
+			ID("24vf9fp5ce0ldsnqqqa8ko4b8jq0lt1n").
+			Name("prod").
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			Version(cmv1.NewVersion().ID("openshift-v4.12.5").RawID("4.12.5").ChannelGroup("stable")).
+			MultiAZ(true).
+			DNS(cmv1.NewDNS().BaseDomain("example.com")).
+			State(cmv1.ClusterStateReady).
+			Nodes(cmv1.NewClusterNodes().
+				ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge")).
+				AutoscaleCompute(cmv1.NewMachinePoolAutoscaling().MinReplicas(3).MaxReplicas(6))).
+			Network(cmv1.NewNetwork().MachineCIDR("10.0.0.0/16").HostPrefix(23)).
+			AWS(cmv1.NewAWS().
+				SubnetIDs("subnet-1", "subnet-2").
+				Tags(map[string]string{"owner": "me", "red-hat-managed": "true"}).
+				STS(cmv1.NewSTS().
+					RoleARN("arn:aws:iam::123456789012:role/prefix-Installer-Role").
+					OperatorRolePrefix("prod-abcd").
+					OidcConfig(cmv1.NewOidcConfig().ID("2345abcd").Reusable(true)).
+					InstanceIAMRoles(cmv1.NewInstanceIAMRoles().
+						WorkerRoleARN("arn:aws:iam::123456789012:role/prefix-Worker-Role")))).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		machinePool, err := cmv1.NewMachinePool().ID("infra").InstanceType("r5.xlarge").Replicas(3).
+			Taints(cmv1.NewTaint().Key("infra").Value("true").Effect("NoSchedule")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		idp, err := cmv1.NewIdentityProvider().ID("idp-1").Name("github").
+			Type(cmv1.IdentityProviderTypeGithub).
+			Github(cmv1.NewGithubIdentityProvider().ClientID("abc").Organizations("org")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		spec := Export(cluster, &Resources{
+			MachinePools:      []*cmv1.MachinePool{machinePool},
+			IdentityProviders: []*cmv1.IdentityProvider{idp},
+		})
+		Expect(spec.APIVersion).To(Equal(APIVersion))
+		Expect(spec.Name).To(Equal("prod"))
+		Expect(spec.Version).To(Equal("4.12.5"))
+		Expect(spec.ChannelGroup).To(BeEmpty())
+		Expect(spec.AvailabilityZones).To(BeEmpty())
+		Expect(spec.Compute.Autoscaling).To(Equal(&Autoscaling{MinReplicas: 3, MaxReplicas: 6}))
+		Expect(spec.Network.SubnetIDs).To(Equal([]string{"subnet-1", "subnet-2"}))
+		Expect(*spec.STS.Enabled).To(BeTrue())
+		Expect(spec.STS.OperatorRolesPrefix).To(BeEmpty())
+		Expect(spec.STS.OidcConfigID).To(BeEmpty())
+		Expect(spec.Tags).To(Equal(map[string]string{"owner": "me"}))
+		Expect(spec.MachinePools).To(HaveLen(1))
+		Expect(spec.MachinePools[0].Name).To(Equal("infra"))
+		Expect(*spec.MachinePools[0].Replicas).To(Equal(3))
+		Expect(spec.IdentityProviders[0].Type).To(Equal("github"))
+		Expect(spec.IdentityProviders[0].GitHub.ClientSecret).To(BeEmpty())
+
+		// The exported document must be accepted by the loader:
+		body, err := Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).ToNot(ContainSubstring("24vf9fp5ce0ldsnqqqa8ko4b8jq0lt1n"))
+		Expect(string(body)).ToNot(ContainSubstring("prod-abcd"))
+		Expect(string(body)).ToNot(ContainSubstring("2345abcd"))
+		Expect(string(body)).ToNot(ContainSubstring("example.com"))
+		_, err = Parse(body)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Exports the options of hosted control plane clusters", func() {
+		cluster, err := cmv1.NewCluster().
+			Name("hosted").
+			Hypershift(cmv1.NewHypershift().Enabled(true)).
+			AWS(cmv1.NewAWS().
+				BillingAccountID("123456789012").
+				STS(cmv1.NewSTS().
+					RoleARN("arn:aws:iam::123456789012:role/prefix-HCP-ROSA-Installer-Role").
+					OperatorRolePrefix("hosted-abcd").
+					OidcConfig(cmv1.NewOidcConfig().ID("2345abcd").Reusable(true))).
+				AuditLog(cmv1.NewAuditLog().RoleArn("arn:aws:iam::123456789012:role/audit"))).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		spec := Export(cluster, &Resources{})
+		Expect(*spec.Hypershift.Enabled).To(BeTrue())
+		Expect(spec.Hypershift.BillingAccount).To(Equal("123456789012"))
+		Expect(spec.Hypershift.AuditLogRoleARN).To(Equal("arn:aws:iam::123456789012:role/audit"))
+		Expect(spec.STS.OperatorRolesPrefix).To(BeEmpty())
+		Expect(spec.STS.OidcConfigID).To(Equal("2345abcd"))
+		Expect(spec.Flags()).To(ContainElement(FlagValue{
+			Name:  "audit-log-arn",
+			Value: "arn:aws:iam::123456789012:role/audit",
+		}))
+	})
+})
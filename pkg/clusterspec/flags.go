@@ -0,0 +1,203 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// FlagValue is a value of a 'rosa create cluster' command line flag derived from a spec document.
+// Flags that accept lists appear once per element.
+type FlagValue struct {
+	Name  string
+	Value string
+}
+
+// Flags translates the document into the equivalent 'rosa create cluster' flags, in a stable order.
+// Fields that aren't set in the document don't produce any flag.
+func (c *Cluster) Flags() []FlagValue {
+	f := &flagList{}
+	f.str("cluster-name", c.Name)
+	f.str("region", c.Region)
+	f.boolean("multi-az", c.MultiAZ)
+	f.list("availability-zones", c.AvailabilityZones)
+	f.str("version", c.Version)
+	f.str("channel-group", c.ChannelGroup)
+	f.boolean("disable-workload-monitoring", c.DisableWorkloadMonitoring)
+	f.boolean("disable-scp-checks", c.DisableSCPChecks)
+
+	if c.Encryption != nil {
+		f.boolean("fips", c.Encryption.FIPS)
+		f.boolean("etcd-encryption", c.Encryption.EtcdEncryption)
+		f.str("kms-key-arn", c.Encryption.KMSKeyArn)
+		f.str("etcd-encryption-kms-arn", c.Encryption.EtcdEncryptionKMSArn)
+	}
+
+	if c.Compute != nil {
+		f.str("compute-machine-type", c.Compute.MachineType)
+		if c.Compute.Replicas != nil {
+			f.add("replicas", strconv.Itoa(*c.Compute.Replicas))
+		}
+		if c.Compute.Autoscaling != nil {
+			f.add("enable-autoscaling", "true")
+			f.add("min-replicas", strconv.Itoa(c.Compute.Autoscaling.MinReplicas))
+			f.add("max-replicas", strconv.Itoa(c.Compute.Autoscaling.MaxReplicas))
+		}
+		if len(c.Compute.Labels) > 0 {
+			f.add("default-mp-labels", strings.Join(joinMap(c.Compute.Labels, "="), ","))
+		}
+	}
+
+	if c.Network != nil {
+		f.str("network-type", c.Network.Type)
+		f.str("machine-cidr", c.Network.MachineCIDR)
+		f.str("service-cidr", c.Network.ServiceCIDR)
+		f.str("pod-cidr", c.Network.PodCIDR)
+		if c.Network.HostPrefix != 0 {
+			f.add("host-prefix", strconv.Itoa(c.Network.HostPrefix))
+		}
+		f.boolean("private", c.Network.Private)
+		f.boolean("private-link", c.Network.PrivateLink)
+		f.list("subnet-ids", c.Network.SubnetIDs)
+	}
+
+	if c.STS != nil {
+		if c.STS.Enabled != nil {
+			if *c.STS.Enabled {
+				f.add("sts", "true")
+			} else {
+				f.add("non-sts", "true")
+			}
+		}
+		f.str("role-arn", c.STS.RoleARN)
+		f.str("external-id", c.STS.ExternalID)
+		f.str("support-role-arn", c.STS.SupportRoleARN)
+		f.str("controlplane-iam-role", c.STS.ControlPlaneRoleARN)
+		f.str("worker-iam-role", c.STS.WorkerRoleARN)
+		f.str("operator-roles-prefix", c.STS.OperatorRolesPrefix)
+		f.str("permissions-boundary", c.STS.PermissionsBoundary)
+		f.str("oidc-config-id", c.STS.OidcConfigID)
+		f.str("mode", c.STS.Mode)
+	}
+
+	if c.Proxy != nil {
+		f.str("http-proxy", c.Proxy.HTTPProxy)
+		f.str("https-proxy", c.Proxy.HTTPSProxy)
+		f.list("no-proxy", c.Proxy.NoProxy)
+		f.str("additional-trust-bundle-file", c.Proxy.AdditionalTrustBundleFile)
+	}
+
+	if c.Hypershift != nil {
+		f.boolean("hosted-cp", c.Hypershift.Enabled)
+		f.str("billing-account", c.Hypershift.BillingAccount)
+		f.str("audit-log-arn", c.Hypershift.AuditLogRoleARN)
+	}
+
+	f.list("tags", joinMap(c.Tags, ":"))
+	f.list("properties", joinMap(c.Properties, ":"))
+
+	return f.values
+}
+
+// ApplyFlags sets the flags derived from the document on the given flag set. Flags that were already
+// given explicitly on the command line are left untouched, so that they override the file.
+func (c *Cluster) ApplyFlags(flags *pflag.FlagSet) error {
+	explicit := map[string]bool{}
+	flags.Visit(func(flag *pflag.Flag) {
+		explicit[flag.Name] = true
+	})
+	for _, value := range c.Flags() {
+		if explicit[value.Name] || overridden(value.Name, explicit) {
+			continue
+		}
+		if flags.Lookup(value.Name) == nil {
+			return fmt.Errorf("Field for flag '--%s' is not supported by this command", value.Name)
+		}
+		err := flags.Set(value.Name, value.Value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for '--%s': %v", value.Value, value.Name, err)
+		}
+	}
+	return nil
+}
+
+// conflictingFlags lists, for each flag, other flags that configure the same option. When any of them
+// is given on the command line the value from the file is ignored.
+var conflictingFlags = map[string][]string{
+	"cluster-name":          {"name"},
+	"controlplane-iam-role": {"master-iam-role"},
+	"replicas":              {"compute-nodes", "enable-autoscaling", "min-replicas", "max-replicas"},
+	"enable-autoscaling":    {"replicas", "compute-nodes"},
+	"min-replicas":          {"replicas", "compute-nodes"},
+	"max-replicas":          {"replicas", "compute-nodes"},
+	"sts":                   {"non-sts", "mint-mode"},
+	"non-sts":               {"sts", "mint-mode"},
+}
+
+func overridden(name string, explicit map[string]bool) bool {
+	for _, other := range conflictingFlags[name] {
+		if explicit[other] {
+			return true
+		}
+	}
+	return false
+}
+
+type flagList struct {
+	values []FlagValue
+}
+
+func (f *flagList) add(name, value string) {
+	f.values = append(f.values, FlagValue{Name: name, Value: value})
+}
+
+func (f *flagList) str(name, value string) {
+	if value != "" {
+		f.add(name, value)
+	}
+}
+
+func (f *flagList) boolean(name string, value *bool) {
+	if value != nil {
+		f.add(name, strconv.FormatBool(*value))
+	}
+}
+
+func (f *flagList) list(name string, values []string) {
+	for _, value := range values {
+		f.add(name, value)
+	}
+}
+
+// joinMap returns the 'key<sep>value' pairs of the map sorted by key.
+func joinMap(m map[string]string, sep string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]string, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, fmt.Sprintf("%s%s%s", k, sep, m[k]))
+	}
+	return result
+}
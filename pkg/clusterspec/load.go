@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// Load reads a cluster spec document from the given YAML or JSON file.
+func Load(path string) (*Cluster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read cluster spec file '%s': %v", path, err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster spec file '%s': %v", path, err)
+	}
+	return spec, nil
+}
+
+// Parse decodes a YAML or JSON cluster spec document and validates it. Unknown fields are rejected so
+// that typos don't silently fall back to defaults.
+func Parse(data []byte) (*Cluster, error) {
+	// JSON is a subset of YAML, so converting first lets us use the strict JSON decoder for both:
+	body, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	spec := &Cluster{}
+	err = decoder.Decode(spec)
+	if err != nil {
+		return nil, err
+	}
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate checks that the document has a supported version and that the values that can be checked
+// without contacting OCM or AWS are well formed.
+func (c *Cluster) Validate() error {
+	if c.APIVersion != APIVersion {
+		return fmt.Errorf("Unsupported apiVersion '%s', expected '%s'", c.APIVersion, APIVersion)
+	}
+	if c.Kind != Kind {
+		return fmt.Errorf("Unsupported kind '%s', expected '%s'", c.Kind, Kind)
+	}
+	if c.Network != nil {
+		for name, cidr := range map[string]string{
+			"machineCIDR": c.Network.MachineCIDR,
+			"serviceCIDR": c.Network.ServiceCIDR,
+			"podCIDR":     c.Network.PodCIDR,
+		} {
+			if cidr == "" {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("Invalid network.%s '%s': %v", name, cidr, err)
+			}
+		}
+	}
+	if c.Compute != nil && c.Compute.Autoscaling != nil {
+		if c.Compute.Replicas != nil {
+			return fmt.Errorf("Only one of compute.replicas or compute.autoscaling may be set")
+		}
+		if c.Compute.Autoscaling.MinReplicas > c.Compute.Autoscaling.MaxReplicas {
+			return fmt.Errorf("compute.autoscaling.maxReplicas must be greater or equal to minReplicas")
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the document as YAML, which is the format used for files kept under version control.
+func Marshal(c *Cluster) ([]byte, error) {
+	return yaml.Marshal(c)
+}
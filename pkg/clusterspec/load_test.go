@@ -0,0 +1,141 @@
+package clusterspec
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+const specYAML = `
+apiVersion: rosa.openshift.io/v1alpha1
+kind: Cluster
+name: mycluster
+region: us-east-2
+version: 4.12.5
+multiAZ: true
+compute:
+  machineType: m5.xlarge
+  autoscaling:
+    minReplicas: 3
+    maxReplicas: 6
+network:
+  machineCIDR: 10.0.0.0/16
+  subnetIDs:
+  - subnet-1
+  - subnet-2
+sts:
+  enabled: true
+  roleARN: arn:aws:iam::123456789012:role/prefix-Installer-Role
+tags:
+  owner: me
+  env: staging
+`
+
+var _ = Describe("Cluster spec", func() {
+	Context("Parse", func() {
+		It("Parses a YAML document", func() {
+			spec, err := Parse([]byte(specYAML))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Name).To(Equal("mycluster"))
+			Expect(*spec.MultiAZ).To(BeTrue())
+			Expect(spec.Compute.Autoscaling.MaxReplicas).To(Equal(6))
+			Expect(spec.Network.SubnetIDs).To(Equal([]string{"subnet-1", "subnet-2"}))
+		})
+
+		It("Parses a JSON document", func() {
+			spec, err := Parse([]byte(`{"apiVersion":"rosa.openshift.io/v1alpha1","kind":"Cluster","name":"foo"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Name).To(Equal("foo"))
+		})
+
+		DescribeTable("Rejects invalid documents",
+			func(document string, expectedError string) {
+				_, err := Parse([]byte(document))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			Entry("Unsupported version",
+				"apiVersion: v2\nkind: Cluster\nname: foo", "Unsupported apiVersion"),
+			Entry("Unknown field",
+				"apiVersion: rosa.openshift.io/v1alpha1\nkind: Cluster\nname: foo\nregoin: us-east-1",
+				"unknown field"),
+			Entry("Invalid CIDR",
+				"apiVersion: rosa.openshift.io/v1alpha1\nkind: Cluster\nname: foo\nnetwork:\n  podCIDR: 10.0.0.0",
+				"Invalid network.podCIDR"),
+			Entry("Replicas and autoscaling",
+				"apiVersion: rosa.openshift.io/v1alpha1\nkind: Cluster\nname: foo\n"+
+					"compute:\n  replicas: 3\n  autoscaling:\n    minReplicas: 3\n    maxReplicas: 3",
+				"Only one of compute.replicas or compute.autoscaling"),
+		)
+	})
+
+	Context("ApplyFlags", func() {
+		var flags *pflag.FlagSet
+		var name, region, machineCIDR string
+		var subnets, tags []string
+		var multiAZ, sts, autoscaling bool
+
+		BeforeEach(func() {
+			flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.StringVar(&name, "cluster-name", "", "")
+			flags.StringVar(&region, "region", "", "")
+			flags.StringVar(&machineCIDR, "machine-cidr", "", "")
+			flags.StringSliceVar(&subnets, "subnet-ids", nil, "")
+			flags.StringSliceVar(&tags, "tags", nil, "")
+			flags.BoolVar(&multiAZ, "multi-az", false, "")
+			flags.BoolVar(&sts, "sts", false, "")
+			flags.BoolVar(&autoscaling, "enable-autoscaling", false, "")
+			var ignored string
+			for _, name := range []string{"version", "compute-machine-type", "replicas", "min-replicas",
+				"max-replicas", "role-arn"} {
+				flags.StringVar(&ignored, name, "", "")
+			}
+		})
+
+		It("Sets the flags from the file", func() {
+			spec, err := Parse([]byte(specYAML))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flags.Parse([]string{})).To(Succeed())
+			Expect(spec.ApplyFlags(flags)).To(Succeed())
+			Expect(name).To(Equal("mycluster"))
+			Expect(region).To(Equal("us-east-2"))
+			Expect(machineCIDR).To(Equal("10.0.0.0/16"))
+			Expect(subnets).To(Equal([]string{"subnet-1", "subnet-2"}))
+			Expect(tags).To(Equal([]string{"env:staging", "owner:me"}))
+			Expect(multiAZ).To(BeTrue())
+			Expect(sts).To(BeTrue())
+			Expect(autoscaling).To(BeTrue())
+			Expect(flags.Changed("subnet-ids")).To(BeTrue())
+		})
+
+		It("Lets command line flags override the file", func() {
+			spec, err := Parse([]byte(specYAML))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flags.Parse([]string{"--cluster-name=other", "--subnet-ids=subnet-3"})).To(Succeed())
+			Expect(spec.ApplyFlags(flags)).To(Succeed())
+			Expect(name).To(Equal("other"))
+			Expect(subnets).To(Equal([]string{"subnet-3"}))
+			Expect(region).To(Equal("us-east-2"))
+		})
+
+		It("Ignores the autoscaling of the file when the replicas are given", func() {
+			spec, err := Parse([]byte(specYAML))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flags.Parse([]string{"--replicas=2"})).To(Succeed())
+			Expect(spec.ApplyFlags(flags)).To(Succeed())
+			Expect(autoscaling).To(BeFalse())
+			Expect(flags.Changed("min-replicas")).To(BeFalse())
+			Expect(flags.Changed("max-replicas")).To(BeFalse())
+		})
+
+		It("Fails on fields the command doesn't support", func() {
+			spec, err := Parse([]byte("apiVersion: rosa.openshift.io/v1alpha1\nkind: Cluster\nname: foo\n" +
+				"hypershift:\n  enabled: true"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(flags.Parse([]string{})).To(Succeed())
+			err = spec.ApplyFlags(flags)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--hosted-cp"))
+		})
+	})
+})
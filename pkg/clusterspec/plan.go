@@ -0,0 +1,823 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Client contains the OCM operations used to apply a plan. It is implemented by *ocm.Client.
+type Client interface {
+	CreateMachinePool(clusterID string, machinePool *cmv1.MachinePool) (*cmv1.MachinePool, error)
+	UpdateMachinePool(clusterID string, machinePool *cmv1.MachinePool) (*cmv1.MachinePool, error)
+	DeleteMachinePool(clusterID string, machinePoolID string) error
+	CreateNodePool(clusterID string, nodePool *cmv1.NodePool) (*cmv1.NodePool, error)
+	UpdateNodePool(clusterID string, nodePool *cmv1.NodePool) (*cmv1.NodePool, error)
+	DeleteNodePool(clusterID string, nodePoolID string) error
+	CreateIdentityProvider(clusterID string, idp *cmv1.IdentityProvider) (*cmv1.IdentityProvider, error)
+	DeleteIdentityProvider(clusterID string, idpID string) error
+	AddHTPasswdUser(username, password, clusterID, idpID string) error
+	DeleteHTPasswdUser(username, clusterID string, htpasswdIDP *cmv1.IdentityProvider) error
+	CreateIngress(clusterID string, ingress *cmv1.Ingress) (*cmv1.Ingress, error)
+	UpdateIngress(clusterID string, ingress *cmv1.Ingress) (*cmv1.Ingress, error)
+	DeleteIngress(clusterID string, ingressID string) error
+	CreateTuningConfig(clusterID string, tuningConfig *cmv1.TuningConfig) (*cmv1.TuningConfig, error)
+	UpdateTuningConfig(clusterID string, tuningConfig *cmv1.TuningConfig) (*cmv1.TuningConfig, error)
+	DeleteTuningConfig(clusterID string, tuningConfigID string) error
+	InstallAddOn(clusterID, addOnID string, params []ocm.AddOnParam, billing ocm.AddOnBilling) error
+	UpdateAddOnInstallation(clusterID, addOnID string, params []ocm.AddOnParam) error
+	UninstallAddOn(clusterID, addOnID string) error
+}
+
+var _ Client = &ocm.Client{}
+
+// Action is the kind of change that is applied to a resource.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionReplace Action = "replace"
+	ActionDelete  Action = "delete"
+)
+
+var actionSymbols = map[Action]string{
+	ActionCreate:  "+",
+	ActionUpdate:  "~",
+	ActionReplace: "-/+",
+	ActionDelete:  "-",
+}
+
+// Resource kinds, in the order in which they are created:
+const (
+	KindTuningConfig     = "tuning config"
+	KindMachinePool      = "machine pool"
+	KindIdentityProvider = "identity provider"
+	KindIngress          = "ingress"
+	KindAddOn            = "add-on"
+)
+
+var kindOrder = []string{
+	KindTuningConfig,
+	KindMachinePool,
+	KindIdentityProvider,
+	KindIngress,
+	KindAddOn,
+}
+
+// Change is a single operation needed to bring a resource to the state described in the document.
+type Change struct {
+	Action      Action
+	Kind        string
+	Name        string
+	Differences []string
+
+	apply func(client Client, clusterID string) error
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s %s '%s'", actionSymbols[c.Action], c.Kind, c.Name)
+}
+
+// Plan is the ordered list of changes needed to reconcile the day 2 resources of a cluster.
+type Plan struct {
+	Changes []*Change
+}
+
+// Empty returns true if the cluster already matches the document.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns a human readable summary of the plan, one change per line followed by the fields that
+// differ.
+func (p *Plan) String() string {
+	buffer := &bytes.Buffer{}
+	counts := map[Action]int{}
+	for _, change := range p.Changes {
+		counts[change.Action]++
+		fmt.Fprintf(buffer, "  %s\n", change)
+		for _, difference := range change.Differences {
+			fmt.Fprintf(buffer, "      %s\n", difference)
+		}
+	}
+	fmt.Fprintf(buffer, "Plan: %d to create, %d to update, %d to replace, %d to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionReplace], counts[ActionDelete])
+	return buffer.String()
+}
+
+// Apply executes the changes in order, calling the progress function before each of them. It stops at
+// the first error.
+func (p *Plan) Apply(client Client, clusterID string, progress func(change *Change)) error {
+	for _, change := range p.Changes {
+		if progress != nil {
+			progress(change)
+		}
+		err := change.apply(client, clusterID)
+		if err != nil {
+			return fmt.Errorf("Failed to %s %s '%s': %v", change.Action, change.Kind, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// BuildPlan compares the current resources of the cluster with the ones described in the document and
+// returns the changes needed to reconcile them. Resources that exist in the cluster but not in the
+// document are only deleted when prune is true, and only for the sections present in the document, so an
+// omitted section leaves that kind of resource untouched while an empty list removes all of them.
+func BuildPlan(cluster *cmv1.Cluster, current *Resources, desired *Cluster, prune bool) (*Plan, error) {
+	planner := &planner{}
+	var err error
+	if cluster.Hypershift().Enabled() {
+		planner.prune = prune && desired.TuningConfigs != nil
+		err = planner.tuningConfigs(current.TuningConfigs, desired.TuningConfigs)
+		if err != nil {
+			return nil, err
+		}
+		planner.prune = prune && desired.MachinePools != nil
+		err = planner.nodePools(current.NodePools, desired.MachinePools)
+	} else {
+		if len(desired.TuningConfigs) > 0 {
+			return nil, fmt.Errorf("Tuning configs are only supported for hosted control plane clusters")
+		}
+		planner.prune = prune && desired.MachinePools != nil
+		err = planner.machinePools(current.MachinePools, desired.MachinePools)
+	}
+	if err != nil {
+		return nil, err
+	}
+	planner.prune = prune && desired.IdentityProviders != nil
+	err = planner.identityProviders(current.IdentityProviders, current.HTPasswdUsers, desired.IdentityProviders)
+	if err != nil {
+		return nil, err
+	}
+	planner.prune = prune && desired.Ingresses != nil
+	err = planner.ingresses(current.Ingresses, desired.Ingresses)
+	if err != nil {
+		return nil, err
+	}
+	planner.prune = prune && desired.AddOns != nil
+	planner.addOns(current.AddOns, desired.AddOns)
+	return planner.plan(), nil
+}
+
+type planner struct {
+	prune   bool
+	changes []*Change
+}
+
+func (p *planner) add(change *Change) {
+	p.changes = append(p.changes, change)
+}
+
+// plan orders the changes so that resources are created and updated before the ones that may depend on
+// them, and deleted in the opposite order.
+func (p *planner) plan() *Plan {
+	rank := func(change *Change) int {
+		for i, kind := range kindOrder {
+			if kind == change.Kind {
+				if change.Action == ActionDelete {
+					return 2*len(kindOrder) - i
+				}
+				return i
+			}
+		}
+		return len(kindOrder)
+	}
+	sort.SliceStable(p.changes, func(i, j int) bool {
+		return rank(p.changes[i]) < rank(p.changes[j])
+	})
+	return &Plan{Changes: p.changes}
+}
+
+// defaultMachinePool is the ID of the machine pool created with classic clusters.
+const defaultMachinePool = "worker"
+
+func (p *planner) machinePools(current []*cmv1.MachinePool, desired []MachinePool) error {
+	existing := map[string]*cmv1.MachinePool{}
+	for _, machinePool := range current {
+		existing[machinePool.ID()] = machinePool
+	}
+	wanted := map[string]bool{}
+	for _, machinePool := range desired {
+		machinePool := machinePool
+		wanted[machinePool.Name] = true
+		old, ok := existing[machinePool.Name]
+		if !ok {
+			object, err := BuildMachinePool(machinePool, false)
+			if err != nil {
+				return err
+			}
+			p.add(&Change{
+				Action: ActionCreate,
+				Kind:   KindMachinePool,
+				Name:   machinePool.Name,
+				apply: func(client Client, clusterID string) error {
+					_, err := client.CreateMachinePool(clusterID, object)
+					return err
+				},
+			})
+			continue
+		}
+		differences, err := machinePoolDifferences(ExportMachinePool(old), machinePool)
+		if err != nil {
+			return err
+		}
+		if len(differences) == 0 {
+			continue
+		}
+		object, err := BuildMachinePool(machinePool, true)
+		if err != nil {
+			return err
+		}
+		p.add(&Change{
+			Action:      ActionUpdate,
+			Kind:        KindMachinePool,
+			Name:        machinePool.Name,
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				_, err := client.UpdateMachinePool(clusterID, object)
+				return err
+			},
+		})
+	}
+	if p.prune {
+		for _, machinePool := range current {
+			id := machinePool.ID()
+			// The default machine pool of classic clusters can't be deleted:
+			if wanted[id] || id == defaultMachinePool {
+				continue
+			}
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindMachinePool,
+				Name:   id,
+				apply: func(client Client, clusterID string) error {
+					return client.DeleteMachinePool(clusterID, id)
+				},
+			})
+		}
+	}
+	return nil
+}
+
+func (p *planner) nodePools(current []*cmv1.NodePool, desired []MachinePool) error {
+	existing := map[string]*cmv1.NodePool{}
+	for _, nodePool := range current {
+		existing[nodePool.ID()] = nodePool
+	}
+	wanted := map[string]bool{}
+	for _, machinePool := range desired {
+		machinePool := machinePool
+		wanted[machinePool.Name] = true
+		old, ok := existing[machinePool.Name]
+		if !ok {
+			object, err := BuildNodePool(machinePool, false)
+			if err != nil {
+				return err
+			}
+			p.add(&Change{
+				Action: ActionCreate,
+				Kind:   KindMachinePool,
+				Name:   machinePool.Name,
+				apply: func(client Client, clusterID string) error {
+					_, err := client.CreateNodePool(clusterID, object)
+					return err
+				},
+			})
+			continue
+		}
+		differences, err := machinePoolDifferences(ExportNodePool(old), machinePool)
+		if err != nil {
+			return err
+		}
+		if len(differences) == 0 {
+			continue
+		}
+		object, err := BuildNodePool(machinePool, true)
+		if err != nil {
+			return err
+		}
+		p.add(&Change{
+			Action:      ActionUpdate,
+			Kind:        KindMachinePool,
+			Name:        machinePool.Name,
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				_, err := client.UpdateNodePool(clusterID, object)
+				return err
+			},
+		})
+	}
+	if p.prune {
+		for _, nodePool := range current {
+			id := nodePool.ID()
+			if wanted[id] {
+				continue
+			}
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindMachinePool,
+				Name:   id,
+				apply: func(client Client, clusterID string) error {
+					return client.DeleteNodePool(clusterID, id)
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// machinePoolDifferences returns the fields that differ between the current and the desired state of a
+// machine pool. Fields that can't be changed after creation result in an error.
+func machinePoolDifferences(current, desired MachinePool) ([]string, error) {
+	var immutable []string
+	// The instance type, zones and subnets are chosen by the service when they aren't given, so they
+	// are only compared when the document contains them:
+	if desired.InstanceType != "" {
+		immutable = diff(immutable, field{"instanceType", current.InstanceType, desired.InstanceType})
+	}
+	if len(desired.AvailabilityZones) > 0 {
+		immutable = diff(immutable, field{"availabilityZones", current.AvailabilityZones, desired.AvailabilityZones})
+	}
+	if len(desired.Subnets) > 0 {
+		immutable = diff(immutable, field{"subnets", current.Subnets, desired.Subnets})
+	}
+	if len(immutable) > 0 {
+		return nil, fmt.Errorf("Machine pool '%s' can't be updated in place, it must be deleted and "+
+			"created again to change: %s", desired.Name, strings.Join(immutable, ", "))
+	}
+	var differences []string
+	if desired.Replicas != nil || desired.Autoscaling != nil {
+		differences = diff(differences,
+			field{"replicas", current.Replicas, desired.Replicas},
+			field{"autoscaling", current.Autoscaling, desired.Autoscaling},
+		)
+	}
+	// Labels, taints and tuning configs are only changed when the document contains them, an empty
+	// list or map removes them:
+	if desired.Labels != nil {
+		differences = diff(differences, field{"labels", current.Labels, desired.Labels})
+	}
+	if desired.Taints != nil {
+		differences = diff(differences, field{"taints", current.Taints, desired.Taints})
+	}
+	if desired.AutoRepair != nil {
+		differences = diff(differences, field{"autoRepair", current.AutoRepair, desired.AutoRepair})
+	}
+	if desired.TuningConfigs != nil {
+		differences = diff(differences, field{"tuningConfigs", current.TuningConfigs, desired.TuningConfigs})
+	}
+	return differences, nil
+}
+
+func (p *planner) identityProviders(current []*cmv1.IdentityProvider, htpasswdUsers map[string][]string,
+	desired []IdentityProvider) error {
+	existing := map[string]*cmv1.IdentityProvider{}
+	for _, idp := range current {
+		existing[idp.Name()] = idp
+	}
+	wanted := map[string]bool{}
+	for _, idp := range desired {
+		idp := idp
+		wanted[idp.Name] = true
+		old, ok := existing[idp.Name]
+		if !ok {
+			object, err := BuildIdentityProvider(idp)
+			if err != nil {
+				return err
+			}
+			p.add(&Change{
+				Action: ActionCreate,
+				Kind:   KindIdentityProvider,
+				Name:   idp.Name,
+				apply: func(client Client, clusterID string) error {
+					_, err := client.CreateIdentityProvider(clusterID, object)
+					return err
+				},
+			})
+			continue
+		}
+		exported := ExportIdentityProvider(old, htpasswdUsers[old.ID()])
+		if idp.Type == "htpasswd" && exported.Type == "htpasswd" {
+			err := p.htpasswdUsers(old, exported, idp)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		// Secrets aren't returned by OCM, so they can't be compared. Any other difference requires
+		// creating the identity provider again, as OCM doesn't support updating them:
+		differences := diff(nil,
+			field{"type", exported.Type, idp.Type},
+			field{"mappingMethod", exported.MappingMethod, idp.MappingMethod},
+			field{"github", exported.GitHub, withoutSecrets(idp).GitHub},
+			field{"gitlab", exported.GitLab, withoutSecrets(idp).GitLab},
+			field{"google", exported.Google, withoutSecrets(idp).Google},
+			field{"openid", exported.OpenID, withoutSecrets(idp).OpenID},
+			field{"ldap", exported.LDAP, withoutSecrets(idp).LDAP},
+		)
+		if len(differences) == 0 {
+			continue
+		}
+		object, err := BuildIdentityProvider(idp)
+		if err != nil {
+			return err
+		}
+		id := old.ID()
+		p.add(&Change{
+			Action:      ActionReplace,
+			Kind:        KindIdentityProvider,
+			Name:        idp.Name,
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				err := client.DeleteIdentityProvider(clusterID, id)
+				if err != nil {
+					return err
+				}
+				_, err = client.CreateIdentityProvider(clusterID, object)
+				return err
+			},
+		})
+	}
+	if p.prune {
+		for _, idp := range current {
+			id := idp.ID()
+			if wanted[idp.Name()] {
+				continue
+			}
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindIdentityProvider,
+				Name:   idp.Name(),
+				apply: func(client Client, clusterID string) error {
+					return client.DeleteIdentityProvider(clusterID, id)
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// htpasswdUsers adds the users of an htpasswd identity provider that are missing from the cluster, and
+// removes the ones that aren't in the document when pruning. Passwords of existing users can't be
+// compared, so they are never changed.
+func (p *planner) htpasswdUsers(old *cmv1.IdentityProvider, current, desired IdentityProvider) error {
+	existing := map[string]bool{}
+	for _, user := range current.HTPasswd.Users {
+		existing[user.Username] = true
+	}
+	wanted := map[string]bool{}
+	var added []HTPasswdUser
+	var differences []string
+	if desired.HTPasswd != nil {
+		for _, user := range desired.HTPasswd.Users {
+			wanted[user.Username] = true
+			if existing[user.Username] {
+				continue
+			}
+			password, err := ResolveSecret(user.Password)
+			if err != nil {
+				return err
+			}
+			if password == "" {
+				return fmt.Errorf("Identity provider '%s' requires a password for user '%s'",
+					desired.Name, user.Username)
+			}
+			added = append(added, HTPasswdUser{Username: user.Username, Password: password})
+			differences = append(differences, fmt.Sprintf("+ user '%s'", user.Username))
+		}
+	}
+	var removed []string
+	if p.prune {
+		for _, user := range current.HTPasswd.Users {
+			if !wanted[user.Username] {
+				removed = append(removed, user.Username)
+				differences = append(differences, fmt.Sprintf("- user '%s'", user.Username))
+			}
+		}
+	}
+	if len(differences) == 0 {
+		return nil
+	}
+	p.add(&Change{
+		Action:      ActionUpdate,
+		Kind:        KindIdentityProvider,
+		Name:        desired.Name,
+		Differences: differences,
+		apply: func(client Client, clusterID string) error {
+			for _, user := range added {
+				err := client.AddHTPasswdUser(user.Username, user.Password, clusterID, old.ID())
+				if err != nil {
+					return err
+				}
+			}
+			for _, username := range removed {
+				err := client.DeleteHTPasswdUser(username, clusterID, old)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	return nil
+}
+
+// withoutSecrets returns a copy of the identity provider without the secret fields, so that it can be
+// compared with the exported one.
+func withoutSecrets(idp IdentityProvider) IdentityProvider {
+	result := idp
+	if idp.GitHub != nil {
+		github := *idp.GitHub
+		github.ClientSecret = ""
+		result.GitHub = &github
+	}
+	if idp.GitLab != nil {
+		gitlab := *idp.GitLab
+		gitlab.ClientSecret = ""
+		result.GitLab = &gitlab
+	}
+	if idp.Google != nil {
+		google := *idp.Google
+		google.ClientSecret = ""
+		result.Google = &google
+	}
+	if idp.OpenID != nil {
+		openID := *idp.OpenID
+		openID.ClientSecret = ""
+		result.OpenID = &openID
+	}
+	if idp.LDAP != nil {
+		ldap := *idp.LDAP
+		ldap.BindPassword = ""
+		result.LDAP = &ldap
+	}
+	return result
+}
+
+func (p *planner) ingresses(current []*cmv1.Ingress, desired []Ingress) error {
+	matched := map[string]bool{}
+	for _, ingress := range desired {
+		ingress := ingress
+		var old *cmv1.Ingress
+		for _, candidate := range current {
+			if matched[candidate.ID()] {
+				continue
+			}
+			if isTrue(ingress.Default) && candidate.Default() ||
+				!isTrue(ingress.Default) && !candidate.Default() &&
+					equal(candidate.RouteSelectors(), ingress.RouteSelectors) {
+				old = candidate
+				break
+			}
+		}
+		if old == nil {
+			if isTrue(ingress.Default) {
+				return fmt.Errorf("Cluster has no default ingress")
+			}
+			object, err := BuildIngress(ingress, "")
+			if err != nil {
+				return err
+			}
+			p.add(&Change{
+				Action: ActionCreate,
+				Kind:   KindIngress,
+				Name:   ingressName(ingress),
+				apply: func(client Client, clusterID string) error {
+					_, err := client.CreateIngress(clusterID, object)
+					return err
+				},
+			})
+			continue
+		}
+		matched[old.ID()] = true
+		exported := ExportIngress(old)
+		var differences []string
+		if ingress.Private != nil {
+			differences = diff(differences, field{"private", exported.Private, ingress.Private})
+		}
+		if isTrue(ingress.Default) {
+			differences = diff(differences, field{"routeSelectors", exported.RouteSelectors, ingress.RouteSelectors})
+		}
+		if len(differences) == 0 {
+			continue
+		}
+		object, err := BuildIngress(ingress, old.ID())
+		if err != nil {
+			return err
+		}
+		p.add(&Change{
+			Action:      ActionUpdate,
+			Kind:        KindIngress,
+			Name:        ingressName(ingress),
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				_, err := client.UpdateIngress(clusterID, object)
+				return err
+			},
+		})
+	}
+	if p.prune {
+		for _, ingress := range current {
+			// The default ingress can't be deleted:
+			if matched[ingress.ID()] || ingress.Default() {
+				continue
+			}
+			id := ingress.ID()
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindIngress,
+				Name:   ingressName(ExportIngress(ingress)),
+				apply: func(client Client, clusterID string) error {
+					return client.DeleteIngress(clusterID, id)
+				},
+			})
+		}
+	}
+	return nil
+}
+
+func ingressName(ingress Ingress) string {
+	if isTrue(ingress.Default) {
+		return "default"
+	}
+	return strings.Join(joinMap(ingress.RouteSelectors, "="), ",")
+}
+
+func (p *planner) tuningConfigs(current []*cmv1.TuningConfig, desired []TuningConfig) error {
+	existing := map[string]*cmv1.TuningConfig{}
+	for _, tuningConfig := range current {
+		existing[tuningConfig.Name()] = tuningConfig
+	}
+	wanted := map[string]bool{}
+	for _, tuningConfig := range desired {
+		wanted[tuningConfig.Name] = true
+		old, ok := existing[tuningConfig.Name]
+		id := ""
+		action := ActionCreate
+		var differences []string
+		if ok {
+			differences = diff(nil, field{"spec", old.Spec(), tuningConfig.Spec})
+			if len(differences) == 0 {
+				continue
+			}
+			id = old.ID()
+			action = ActionUpdate
+		}
+		object, err := BuildTuningConfig(tuningConfig, id)
+		if err != nil {
+			return err
+		}
+		p.add(&Change{
+			Action:      action,
+			Kind:        KindTuningConfig,
+			Name:        tuningConfig.Name,
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				if action == ActionCreate {
+					_, err := client.CreateTuningConfig(clusterID, object)
+					return err
+				}
+				_, err := client.UpdateTuningConfig(clusterID, object)
+				return err
+			},
+		})
+	}
+	if p.prune {
+		for _, tuningConfig := range current {
+			id := tuningConfig.ID()
+			if wanted[tuningConfig.Name()] {
+				continue
+			}
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindTuningConfig,
+				Name:   tuningConfig.Name(),
+				apply: func(client Client, clusterID string) error {
+					return client.DeleteTuningConfig(clusterID, id)
+				},
+			})
+		}
+	}
+	return nil
+}
+
+func (p *planner) addOns(current []*cmv1.AddOnInstallation, desired []AddOn) {
+	existing := map[string]AddOn{}
+	for _, installation := range current {
+		addOn := ExportAddOn(installation)
+		existing[addOn.ID] = addOn
+	}
+	wanted := map[string]bool{}
+	for _, addOn := range desired {
+		wanted[addOn.ID] = true
+		params := BuildAddOnParams(addOn)
+		id := addOn.ID
+		old, ok := existing[addOn.ID]
+		if !ok {
+			p.add(&Change{
+				Action: ActionCreate,
+				Kind:   KindAddOn,
+				Name:   id,
+				apply: func(client Client, clusterID string) error {
+					return client.InstallAddOn(clusterID, id, params, ocm.AddOnBilling{
+						BillingModel: string(cmv1.BillingModelStandard),
+					})
+				},
+			})
+			continue
+		}
+		// Parameters that aren't in the document are left as they are:
+		if addOn.Parameters == nil {
+			continue
+		}
+		differences := diff(nil, field{"parameters", old.Parameters, addOn.Parameters})
+		if len(differences) == 0 {
+			continue
+		}
+		p.add(&Change{
+			Action:      ActionUpdate,
+			Kind:        KindAddOn,
+			Name:        id,
+			Differences: differences,
+			apply: func(client Client, clusterID string) error {
+				return client.UpdateAddOnInstallation(clusterID, id, params)
+			},
+		})
+	}
+	if p.prune {
+		for _, installation := range current {
+			id := installation.Addon().ID()
+			if wanted[id] {
+				continue
+			}
+			p.add(&Change{
+				Action: ActionDelete,
+				Kind:   KindAddOn,
+				Name:   id,
+				apply: func(client Client, clusterID string) error {
+					return client.UninstallAddOn(clusterID, id)
+				},
+			})
+		}
+	}
+}
+
+type field struct {
+	name    string
+	current interface{}
+	desired interface{}
+}
+
+// diff appends to the given list a line for each field whose current and desired values differ.
+func diff(differences []string, fields ...field) []string {
+	for _, f := range fields {
+		if equal(f.current, f.desired) {
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("%s: %s -> %s", f.name, render(f.current),
+			render(f.desired)))
+	}
+	return differences
+}
+
+// equal compares two values by their JSON representation, so that nil and empty values are the same
+// and values decoded from the document compare equal to the ones returned by OCM.
+func equal(a, b interface{}) bool {
+	return render(a) == render(b)
+}
+
+func render(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	switch string(data) {
+	case "null", "{}", "[]", `""`:
+		return "<none>"
+	}
+	return string(data)
+}
@@ -0,0 +1,143 @@
+package clusterspec
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Plan", func() {
+	var cluster *cmv1.Cluster
+	var current *Resources
+
+	BeforeEach(func() {
+		var err error
+		cluster, err = cmv1.NewCluster().ID("24vf9fp5ce0ldsnqqqa8ko4b8jq0lt1n").Name("prod").Build()
+		Expect(err).ToNot(HaveOccurred())
+		infra, err := cmv1.NewMachinePool().ID("infra").InstanceType("r5.xlarge").Replicas(3).
+			Labels(map[string]string{"role": "infra"}).
+			Taints(cmv1.NewTaint().Key("infra").Value("true").Effect("NoSchedule")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		old, err := cmv1.NewMachinePool().ID("old").InstanceType("m5.xlarge").Replicas(2).Build()
+		Expect(err).ToNot(HaveOccurred())
+		ingress, err := cmv1.NewIngress().ID("abcd").Default(true).
+			Listening(cmv1.ListeningMethodExternal).Build()
+		Expect(err).ToNot(HaveOccurred())
+		current = &Resources{
+			MachinePools: []*cmv1.MachinePool{infra, old},
+			Ingresses:    []*cmv1.Ingress{ingress},
+		}
+	})
+
+	It("Is empty when the cluster matches the document", func() {
+		plan, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{
+				{
+					Name:         "infra",
+					InstanceType: "r5.xlarge",
+					Replicas:     intPtr(3),
+					Labels:       map[string]string{"role": "infra"},
+					Taints:       []Taint{{Key: "infra", Value: "true", Effect: "NoSchedule"}},
+				},
+				{Name: "old", InstanceType: "m5.xlarge"},
+			},
+			Ingresses: []Ingress{{Default: boolPtr(true)}},
+		}, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+	})
+
+	It("Creates, updates and prunes resources", func() {
+		plan, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{
+				{Name: "infra", InstanceType: "r5.xlarge", Replicas: intPtr(5)},
+				{Name: "gpu", InstanceType: "g4dn.xlarge", Replicas: intPtr(1)},
+			},
+			Ingresses: []Ingress{{Default: boolPtr(true), Private: boolPtr(true)}},
+		}, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Changes).To(HaveLen(4))
+		Expect(plan.Changes[0].String()).To(Equal("~ machine pool 'infra'"))
+		Expect(plan.Changes[0].Differences).To(Equal([]string{"replicas: 3 -> 5"}))
+		Expect(plan.Changes[1].String()).To(Equal("+ machine pool 'gpu'"))
+		Expect(plan.Changes[2].String()).To(Equal("~ ingress 'default'"))
+		Expect(plan.Changes[3].String()).To(Equal("- machine pool 'old'"))
+	})
+
+	It("Doesn't delete resources without prune or of omitted sections", func() {
+		plan, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{{Name: "infra", InstanceType: "r5.xlarge"}},
+		}, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+
+		plan, err = BuildPlan(cluster, current, &Cluster{}, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+	})
+
+	It("Fails to change the instance type of a machine pool", func() {
+		_, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{{Name: "infra", InstanceType: "m5.xlarge"}},
+		}, false)
+		Expect(err).To(MatchError(ContainSubstring("instanceType")))
+	})
+
+	It("Requires secrets of new identity providers", func() {
+		_, err := BuildPlan(cluster, current, &Cluster{
+			IdentityProviders: []IdentityProvider{{
+				Name:   "github",
+				Type:   "github",
+				GitHub: &GitHubIdentityProvider{ClientID: "abc", ClientSecret: "${ROSA_TEST_MISSING_SECRET}"},
+			}},
+		}, false)
+		Expect(err).To(MatchError(ContainSubstring("ROSA_TEST_MISSING_SECRET")))
+
+		_, err = BuildPlan(cluster, current, &Cluster{
+			IdentityProviders: []IdentityProvider{{
+				Name:   "github",
+				Type:   "github",
+				GitHub: &GitHubIdentityProvider{ClientID: "abc"},
+			}},
+		}, false)
+		Expect(err).To(MatchError(ContainSubstring("github.clientSecret")))
+	})
+
+	It("Leaves the fields that the document omits as they are", func() {
+		worker, err := cmv1.NewMachinePool().ID("worker").InstanceType("m5.xlarge").Replicas(2).Build()
+		Expect(err).ToNot(HaveOccurred())
+		addOn, err := cmv1.NewAddOnInstallation().
+			Addon(cmv1.NewAddOn().ID("logging")).
+			Parameters(cmv1.NewAddOnInstallationParameterList().Items(
+				cmv1.NewAddOnInstallationParameter().ID("retention").Value("7d"))).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		current.MachinePools = append(current.MachinePools, worker)
+		current.AddOns = []*cmv1.AddOnInstallation{addOn}
+
+		plan, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{
+				{Name: "infra", Replicas: intPtr(3)},
+				{Name: "old"},
+			},
+			Ingresses: []Ingress{{Default: boolPtr(true)}},
+			AddOns:    []AddOn{{ID: "logging"}},
+		}, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+	})
+
+	It("Removes the labels and taints when the document contains empty ones", func() {
+		plan, err := BuildPlan(cluster, current, &Cluster{
+			MachinePools: []MachinePool{
+				{Name: "infra", Labels: map[string]string{}, Taints: []Taint{}},
+			},
+		}, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Changes).To(HaveLen(1))
+		Expect(plan.Changes[0].Differences).To(HaveLen(2))
+		Expect(plan.Changes[0].Differences[0]).To(HavePrefix("labels: "))
+		Expect(plan.Changes[0].Differences[1]).To(HavePrefix("taints: "))
+	})
+})
@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterspec contains the versioned, declarative description of a ROSA cluster that can be
+// stored in a file, reviewed like any other piece of infrastructure and fed back into
+// 'rosa create cluster --from-file'.
+package clusterspec
+
+const (
+	// APIVersion is the only version of the schema currently understood by the tool.
+	APIVersion = "rosa.openshift.io/v1alpha1"
+
+	// Kind is the kind of document that describes a cluster.
+	Kind = "Cluster"
+)
+
+// Cluster is the root of a cluster spec document. The fields of the cluster map onto fields of
+// ocm.Spec, so that loading a document is equivalent to passing the corresponding command line flags,
+// and the day 2 resources are created with 'rosa apply' once the cluster is ready.
+type Cluster struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Basic configs
+	Name                      string   `json:"name"`
+	Region                    string   `json:"region,omitempty"`
+	MultiAZ                   *bool    `json:"multiAZ,omitempty"`
+	AvailabilityZones         []string `json:"availabilityZones,omitempty"`
+	Version                   string   `json:"version,omitempty"`
+	ChannelGroup              string   `json:"channelGroup,omitempty"`
+	DisableWorkloadMonitoring *bool    `json:"disableWorkloadMonitoring,omitempty"`
+	DisableSCPChecks          *bool    `json:"disableSCPChecks,omitempty"`
+
+	Encryption *Encryption       `json:"encryption,omitempty"`
+	Compute    *Compute          `json:"compute,omitempty"`
+	Network    *Network          `json:"network,omitempty"`
+	STS        *STS              `json:"sts,omitempty"`
+	Proxy      *Proxy            `json:"proxy,omitempty"`
+	Hypershift *Hypershift       `json:"hypershift,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// Day 2 resources, which aren't part of the cluster creation request
+	MachinePools      []MachinePool      `json:"machinePools,omitempty"`
+	IdentityProviders []IdentityProvider `json:"identityProviders,omitempty"`
+	Ingresses         []Ingress          `json:"ingresses,omitempty"`
+	TuningConfigs     []TuningConfig     `json:"tuningConfigs,omitempty"`
+	AddOns            []AddOn            `json:"addons,omitempty"`
+}
+
+// Encryption maps onto the FIPS, EtcdEncryption, KMSKeyArn and EtcdEncryptionKMSArn fields of ocm.Spec.
+type Encryption struct {
+	FIPS                 *bool  `json:"fips,omitempty"`
+	EtcdEncryption       *bool  `json:"etcdEncryption,omitempty"`
+	KMSKeyArn            string `json:"kmsKeyArn,omitempty"`
+	EtcdEncryptionKMSArn string `json:"etcdEncryptionKmsArn,omitempty"`
+}
+
+// Compute describes the default machine pool of the cluster.
+type Compute struct {
+	MachineType string            `json:"machineType,omitempty"`
+	Replicas    *int              `json:"replicas,omitempty"`
+	Autoscaling *Autoscaling      `json:"autoscaling,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// Autoscaling enables autoscaling of a set of nodes between the given bounds.
+type Autoscaling struct {
+	MinReplicas int `json:"minReplicas"`
+	MaxReplicas int `json:"maxReplicas"`
+}
+
+// Network contains the networking options of the cluster.
+type Network struct {
+	Type        string   `json:"type,omitempty"`
+	MachineCIDR string   `json:"machineCIDR,omitempty"`
+	ServiceCIDR string   `json:"serviceCIDR,omitempty"`
+	PodCIDR     string   `json:"podCIDR,omitempty"`
+	HostPrefix  int      `json:"hostPrefix,omitempty"`
+	Private     *bool    `json:"private,omitempty"`
+	PrivateLink *bool    `json:"privateLink,omitempty"`
+	SubnetIDs   []string `json:"subnetIDs,omitempty"`
+}
+
+// STS contains the account roles, operator roles and OIDC configuration of an STS cluster.
+type STS struct {
+	Enabled             *bool  `json:"enabled,omitempty"`
+	RoleARN             string `json:"roleARN,omitempty"`
+	ExternalID          string `json:"externalID,omitempty"`
+	SupportRoleARN      string `json:"supportRoleARN,omitempty"`
+	ControlPlaneRoleARN string `json:"controlPlaneRoleARN,omitempty"`
+	WorkerRoleARN       string `json:"workerRoleARN,omitempty"`
+	OperatorRolesPrefix string `json:"operatorRolesPrefix,omitempty"`
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
+	OidcConfigID        string `json:"oidcConfigID,omitempty"`
+	Mode                string `json:"mode,omitempty"`
+}
+
+// Proxy contains the cluster-wide proxy configuration.
+type Proxy struct {
+	HTTPProxy                 string   `json:"httpProxy,omitempty"`
+	HTTPSProxy                string   `json:"httpsProxy,omitempty"`
+	NoProxy                   []string `json:"noProxy,omitempty"`
+	AdditionalTrustBundleFile string   `json:"additionalTrustBundleFile,omitempty"`
+}
+
+// Hypershift contains the options that only apply to hosted control plane clusters.
+type Hypershift struct {
+	Enabled         *bool  `json:"enabled,omitempty"`
+	BillingAccount  string `json:"billingAccount,omitempty"`
+	AuditLogRoleARN string `json:"auditLogRoleARN,omitempty"`
+}
+
+// HasResources returns true if the document describes any day 2 resource.
+func (c *Cluster) HasResources() bool {
+	return len(c.MachinePools) > 0 || len(c.IdentityProviders) > 0 || len(c.Ingresses) > 0 ||
+		len(c.TuningConfigs) > 0 || len(c.AddOns) > 0
+}
+
+// MachinePool describes an additional machine pool, or a node pool for hosted control plane clusters.
+type MachinePool struct {
+	Name              string            `json:"name"`
+	InstanceType      string            `json:"instanceType,omitempty"`
+	Replicas          *int              `json:"replicas,omitempty"`
+	Autoscaling       *Autoscaling      `json:"autoscaling,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Taints            []Taint           `json:"taints,omitempty"`
+	AvailabilityZones []string          `json:"availabilityZones,omitempty"`
+	Subnets           []string          `json:"subnets,omitempty"`
+
+	// Node pool only options
+	AutoRepair    *bool    `json:"autoRepair,omitempty"`
+	TuningConfigs []string `json:"tuningConfigs,omitempty"`
+}
+
+// Taint is a Kubernetes taint applied to the nodes of a machine pool.
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// IdentityProvider describes an identity provider of the cluster. Exactly one of the type specific
+// sections must be present, matching the type. Secrets are never exported; when applying a document,
+// secret fields may reference environment variables using the '${NAME}' syntax.
+type IdentityProvider struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	MappingMethod string `json:"mappingMethod,omitempty"`
+
+	GitHub   *GitHubIdentityProvider   `json:"github,omitempty"`
+	GitLab   *GitLabIdentityProvider   `json:"gitlab,omitempty"`
+	Google   *GoogleIdentityProvider   `json:"google,omitempty"`
+	OpenID   *OpenIDIdentityProvider   `json:"openid,omitempty"`
+	LDAP     *LDAPIdentityProvider     `json:"ldap,omitempty"`
+	HTPasswd *HTPasswdIdentityProvider `json:"htpasswd,omitempty"`
+}
+
+type GitHubIdentityProvider struct {
+	ClientID      string   `json:"clientID"`
+	ClientSecret  string   `json:"clientSecret,omitempty"`
+	Hostname      string   `json:"hostname,omitempty"`
+	Organizations []string `json:"organizations,omitempty"`
+	Teams         []string `json:"teams,omitempty"`
+	CA            string   `json:"ca,omitempty"`
+}
+
+type GitLabIdentityProvider struct {
+	URL          string `json:"url"`
+	ClientID     string `json:"clientID"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	CA           string `json:"ca,omitempty"`
+}
+
+type GoogleIdentityProvider struct {
+	ClientID     string `json:"clientID"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	HostedDomain string `json:"hostedDomain,omitempty"`
+}
+
+type OpenIDIdentityProvider struct {
+	Issuer       string        `json:"issuer"`
+	ClientID     string        `json:"clientID"`
+	ClientSecret string        `json:"clientSecret,omitempty"`
+	CA           string        `json:"ca,omitempty"`
+	ExtraScopes  []string      `json:"extraScopes,omitempty"`
+	Claims       *OpenIDClaims `json:"claims,omitempty"`
+}
+
+type OpenIDClaims struct {
+	Email             []string `json:"email,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	Name              []string `json:"name,omitempty"`
+	PreferredUsername []string `json:"preferredUsername,omitempty"`
+}
+
+type LDAPIdentityProvider struct {
+	URL          string          `json:"url"`
+	BindDN       string          `json:"bindDN,omitempty"`
+	BindPassword string          `json:"bindPassword,omitempty"`
+	Insecure     *bool           `json:"insecure,omitempty"`
+	CA           string          `json:"ca,omitempty"`
+	Attributes   *LDAPAttributes `json:"attributes,omitempty"`
+}
+
+type LDAPAttributes struct {
+	ID                []string `json:"id,omitempty"`
+	Email             []string `json:"email,omitempty"`
+	Name              []string `json:"name,omitempty"`
+	PreferredUsername []string `json:"preferredUsername,omitempty"`
+}
+
+type HTPasswdIdentityProvider struct {
+	Users []HTPasswdUser `json:"users,omitempty"`
+}
+
+type HTPasswdUser struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+// Ingress describes an application router. The default router is matched by the 'default' field,
+// additional routers are matched by their route selectors.
+type Ingress struct {
+	Default        *bool             `json:"default,omitempty"`
+	Private        *bool             `json:"private,omitempty"`
+	RouteSelectors map[string]string `json:"routeSelectors,omitempty"`
+}
+
+// TuningConfig is a node tuning configuration. The spec is passed verbatim to OCM.
+type TuningConfig struct {
+	Name string      `json:"name"`
+	Spec interface{} `json:"spec"`
+}
+
+// AddOn is an add-on installed in the cluster, with the values of its parameters.
+type AddOn struct {
+	ID         string            `json:"id"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/golang/glog"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 
+	"github.com/openshift/rosa/pkg/cassette"
 	"github.com/openshift/rosa/pkg/debug"
+	"github.com/openshift/rosa/pkg/har"
 )
 
 // Config is the type used to store the configuration of the client.
@@ -275,6 +277,14 @@ func (c *Config) Connection() (connection *sdk.Connection, err error) {
 		builder.Tokens(tokens...)
 	}
 	builder.Insecure(c.Insecure)
+	if har.Enabled() {
+		builder.TransportWrapper(har.Transport)
+	}
+	// The transport wrappers are called in the order they are added, so the cassette is added
+	// last in order to record the requests as they are sent to the network:
+	if cassette.Enabled() {
+		builder.TransportWrapper(cassette.Transport)
+	}
 
 	// Create the connection:
 	connection, err = builder.Build()
@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package har records the HTTP requests sent to OCM and AWS, and the responses received, to a file
+// in the HTTP archive (HAR) format, so that they can be attached to support cases and inspected
+// with the developer tools of browsers. Security sensitive values are redacted before they are
+// written.
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/info"
+)
+
+// AddFlag adds the record flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&file,
+		"record",
+		"",
+		"Record the requests sent to OCM and AWS, and their responses, to the given HAR file. "+
+			"Tokens, secrets, passwords and private keys are redacted.",
+	)
+}
+
+// Enabled returns true if the requests are recorded.
+func Enabled() bool {
+	return file != ""
+}
+
+// file is the name of the file where the requests are recorded.
+var file string
+
+// archive contains the header of the archive, without the entries. The entries are streamed to the
+// file as the requests complete, see add.
+var archive = &Log{
+	Version: "1.2",
+	Creator: Creator{
+		Name:    info.UserAgent,
+		Version: info.Version,
+	},
+}
+
+// trailer closes the list of entries and the archive. It is written after the last entry, so that
+// the file is a complete archive even if the tool exits without returning from the command, and it
+// is overwritten by the next entry.
+const trailer = "\n]}}\n"
+
+// writer is the file where the requests are recorded, offset is the position of the trailer in it
+// and entries is the number of entries written so far.
+var (
+	writer  *os.File
+	offset  int64
+	entries int
+)
+
+var lock = &sync.Mutex{}
+
+// Start creates the file where the requests are recorded, with an empty archive, so that problems
+// writing it are detected before sending any request. It does nothing if the requests aren't
+// recorded.
+func Start() error {
+	if !Enabled() {
+		return nil
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	err := create()
+	if err != nil {
+		return fmt.Errorf("Failed to create HAR file '%s': %v", file, err)
+	}
+	return nil
+}
+
+func create() error {
+	if writer != nil {
+		_ = writer.Close()
+		writer = nil
+	}
+	header, err := json.Marshal(struct {
+		Version string  `json:"version"`
+		Creator Creator `json:"creator"`
+	}{archive.Version, archive.Creator})
+	if err != nil {
+		return err
+	}
+	data := fmt.Sprintf(`{"log":%s,"entries":[`, bytes.TrimSuffix(header, []byte("}")))
+	writer, err = os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = writer.WriteString(data + trailer)
+	if err != nil {
+		return err
+	}
+	offset = int64(len(data))
+	entries = 0
+	return nil
+}
+
+// add writes the entry to the file, replacing the trailer, and then writes the trailer again, so
+// each request only costs writing its own entry. Errors writing the file are ignored, as they
+// shouldn't make the requests fail.
+func add(entry *Entry) {
+	lock.Lock()
+	defer lock.Unlock()
+	if writer == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	separator := "\n"
+	if entries > 0 {
+		separator = ",\n"
+	}
+	data = append([]byte(separator), data...)
+	_, err = writer.WriteAt(append(data, trailer...), offset)
+	if err != nil {
+		return
+	}
+	offset += int64(len(data))
+	entries++
+}
+
+// The following types are the parts of the HAR format that are used by the tool. See
+// http://www.softwareishard.com/blog/har-12-spec for the complete specification.
+
+type Log struct {
+	Version string   `json:"version"`
+	Creator Creator  `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total time of the request in milliseconds.
+	Time     float64   `json:"time"`
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+	Cache    struct{}  `json:"cache"`
+	Timings  Timings   `json:"timings"`
+	// Error is the error returned when the request fails without a response.
+	Error string `json:"_error,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings contains the time, in milliseconds, spent in each phase of the request. The phases that
+// aren't measured are -1.
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
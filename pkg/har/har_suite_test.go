@@ -0,0 +1,13 @@
+package har_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HAR Suite")
+}
@@ -0,0 +1,194 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that remove security sensitive values from the requests and
+// responses before they are recorded.
+
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces the security sensitive values.
+const Redacted = "***"
+
+// sensitiveHeaders are the headers whose values are always redacted.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+}
+
+// sensitiveFields are the names of the query parameters, form fields and JSON fields whose values
+// are redacted, in lower case and without separators. The 'X-Amz-*' ones are the credentials of
+// the AWS presigned URLs.
+var sensitiveFields = map[string]bool{
+	"accesstoken":       true,
+	"refreshtoken":      true,
+	"idtoken":           true,
+	"token":             true,
+	"clientsecret":      true,
+	"password":          true,
+	"hashedpassword":    true,
+	"bindpassword":      true,
+	"privatekey":        true,
+	"secretaccesskey":   true,
+	"sessiontoken":      true,
+	"secretstring":      true,
+	"secretbinary":      true,
+	"xamzsecuritytoken": true,
+	"xamzsignature":     true,
+}
+
+// sensitiveElements matches the XML elements of the AWS query APIs that contain credentials.
+var sensitiveElements = regexp.MustCompile(
+	`<(SecretAccessKey|SessionToken|Password)>[^<]*</(SecretAccessKey|SessionToken|Password)>`)
+
+// privateKeys matches PEM encoded private keys, also when they are inside JSON strings.
+var privateKeys = regexp.MustCompile(
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "_", "")
+	name = strings.ReplaceAll(name, "-", "")
+	return sensitiveFields[name]
+}
+
+// redactHeaders returns the headers sorted by name, with the values of the sensitive ones redacted.
+func redactHeaders(header http.Header) []NameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := []NameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = Redacted
+			}
+			result = append(result, NameValue{Name: name, Value: value})
+		}
+	}
+	return result
+}
+
+// redactValues redacts the values of the sensitive fields of the query string or form.
+func redactValues(values url.Values) url.Values {
+	result := url.Values{}
+	for name, items := range values {
+		for _, item := range items {
+			if isSensitiveField(name) {
+				item = Redacted
+			}
+			result.Add(name, item)
+		}
+	}
+	return result
+}
+
+// redactQuery returns the query parameters of the URL, with the values of the sensitive ones
+// redacted, and the URL with the redacted query.
+func redactQuery(requestURL *url.URL) ([]NameValue, string) {
+	values := redactValues(requestURL.Query())
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := []NameValue{}
+	for _, name := range names {
+		for _, value := range values[name] {
+			result = append(result, NameValue{Name: name, Value: value})
+		}
+	}
+	copied := *requestURL
+	copied.User = nil
+	if len(values) > 0 {
+		copied.RawQuery = values.Encode()
+	}
+	return result, copied.String()
+}
+
+// RedactBody returns the body with the values of the sensitive fields redacted, according to its
+// content type.
+func RedactBody(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err == nil {
+			body = []byte(redactValues(form).Encode())
+		}
+	case "application/json", "application/x-amz-json-1.0", "application/x-amz-json-1.1":
+		body = redactJSON(body)
+	case "text/xml", "application/xml":
+		body = sensitiveElements.ReplaceAllFunc(body, func(element []byte) []byte {
+			name := element[1:bytes.IndexByte(element, '>')]
+			return []byte("<" + string(name) + ">" + Redacted + "</" + string(name) + ">")
+		})
+	}
+	return privateKeys.ReplaceAll(body, []byte(Redacted))
+}
+
+// redactJSON redacts the values of the sensitive fields of the JSON document, at any level. The
+// document is returned unchanged if it can't be parsed.
+func redactJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err != nil {
+		return body
+	}
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(redactValue(document))
+	if err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, item := range value {
+			if isSensitiveField(name) {
+				value[name] = Redacted
+			} else {
+				value[name] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return value
+}
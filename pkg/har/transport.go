@@ -0,0 +1,233 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the http.RoundTripper interface that records the
+// requests and the responses.
+
+package har
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+	"unicode/utf8"
+)
+
+// transport is a round tripper that records the requests sent and the responses received.
+type transport struct {
+	next http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &transport{}
+
+// Transport returns a round tripper that records the requests sent with the next round tripper,
+// and the responses received. It has the signature of the OCM SDK transport wrappers, so that it
+// can be used directly as one.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{
+		next: next,
+	}
+}
+
+// trace contains the times of the events of a request, used to calculate the timings.
+type trace struct {
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+func (t *trace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.dnsDone = time.Now() },
+		ConnectStart:         func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connectDone = time.Now() },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+		GotConn:              func(httptrace.GotConnInfo) { t.gotConn = time.Now() },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	}
+}
+
+// milliseconds returns the time between the two events in milliseconds, or -1 if any of them
+// didn't happen.
+func milliseconds(start time.Time, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return float64(end.Sub(start).Microseconds()) / 1000
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Read the complete body in memory, in order to record it, and replace it with a reader that
+	// reads it from memory:
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		err = request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
+	}
+
+	events := &trace{}
+	start := time.Now()
+	response, err := t.next.RoundTrip(
+		request.WithContext(httptrace.WithClientTrace(request.Context(), events.clientTrace())),
+	)
+	received := time.Now()
+
+	entry := &Entry{
+		StartedDateTime: start.UTC(),
+		Request:         newRequest(request, requestBody),
+	}
+	if err != nil {
+		entry.Response = &Response{
+			Cookies: []NameValue{},
+			Headers: []NameValue{},
+		}
+		entry.Error = err.Error()
+		entry.Time = milliseconds(start, received)
+		entry.Timings = newTimings(start, events, received, received)
+		add(entry)
+		return nil, err
+	}
+
+	// Read the complete response body in memory, in order to record it, and replace it with a
+	// reader that reads it from memory:
+	var responseBody []byte
+	if response.Body != nil {
+		responseBody, err = io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		err = response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = io.NopCloser(bytes.NewBuffer(responseBody))
+	}
+	end := time.Now()
+
+	entry.Response = newResponse(response, responseBody)
+	entry.Time = milliseconds(start, end)
+	entry.Timings = newTimings(start, events, received, end)
+	add(entry)
+	return response, nil
+}
+
+func newRequest(request *http.Request, body []byte) *Request {
+	queryString, requestURL := redactQuery(request.URL)
+	result := &Request{
+		Method:      request.Method,
+		URL:         requestURL,
+		HTTPVersion: request.Proto,
+		Cookies:     []NameValue{},
+		Headers:     redactHeaders(request.Header),
+		QueryString: queryString,
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if result.HTTPVersion == "" {
+		result.HTTPVersion = "HTTP/1.1"
+	}
+	if len(body) > 0 {
+		contentType := request.Header.Get("Content-Type")
+		text, _ := encodeBody(RedactBody(contentType, body))
+		result.PostData = &PostData{
+			MimeType: contentType,
+			Text:     text,
+		}
+	}
+	return result
+}
+
+func newResponse(response *http.Response, body []byte) *Response {
+	contentType := response.Header.Get("Content-Type")
+	text, encoding := encodeBody(RedactBody(contentType, body))
+	return &Response{
+		Status:      response.StatusCode,
+		StatusText:  http.StatusText(response.StatusCode),
+		HTTPVersion: response.Proto,
+		Cookies:     []NameValue{},
+		Headers:     redactHeaders(response.Header),
+		Content: Content{
+			Size:     len(body),
+			MimeType: contentType,
+			Text:     text,
+			Encoding: encoding,
+		},
+		RedirectURL: response.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// encodeBody returns the body as text, encoded with base64 when it isn't valid UTF-8, and the
+// encoding used.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// newTimings calculates the timings of the request from the events traced. When the next round
+// tripper doesn't send the request to the network, and therefore the events don't happen, all
+// the time is considered waiting time.
+func newTimings(start time.Time, events *trace, received time.Time, end time.Time) Timings {
+	timings := Timings{
+		Blocked: -1,
+		DNS:     milliseconds(events.dnsStart, events.dnsDone),
+		Connect: milliseconds(events.connectStart, events.connectDone),
+		SSL:     milliseconds(events.tlsStart, events.tlsDone),
+		Send:    milliseconds(events.gotConn, events.wroteRequest),
+		Wait:    milliseconds(events.wroteRequest, events.firstByte),
+		Receive: milliseconds(events.firstByte, end),
+	}
+	if timings.SSL >= 0 && timings.Connect >= 0 {
+		// The connection time includes the TLS handshake:
+		timings.Connect += timings.SSL
+	}
+	if timings.Send < 0 {
+		timings.Send = 0
+	}
+	if timings.Wait < 0 {
+		timings.Wait = milliseconds(start, received)
+	}
+	if timings.Receive < 0 {
+		timings.Receive = milliseconds(received, end)
+	}
+	return timings
+}
@@ -0,0 +1,173 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// Diagnosis is a known cause of installation failures, with the hint that explains how to fix it.
+type Diagnosis struct {
+	Name    string
+	Hint    string
+	pattern *regexp.Regexp
+	// commands returns the rosa commands that verify the cause for the cluster.
+	commands func(cluster *cmv1.Cluster) []string
+}
+
+// Catalogue contains the known causes of installation failures. The more specific causes go
+// first, as only the first cause that matches each line is reported.
+var Catalogue = []*Diagnosis{
+	{
+		Name: "Service control policy denial",
+		Hint: "A service control policy of the AWS organization denies an action that the installer " +
+			"needs. Ask the administrator of the organization to allow it for the account.",
+		pattern:  regexp.MustCompile(`(?i)explicit deny in a service control policy`),
+		commands: verifyPermissions,
+	},
+	{
+		Name: "Quota exceeded",
+		Hint: "The AWS account doesn't have enough quota for the resources of the cluster. Request a " +
+			"quota increase in the Service Quotas console, or use a different region.",
+		// The bare 'LimitExceeded' isn't matched because it is also the suffix of the
+		// 'RequestLimitExceeded' throttling error, which is transient:
+		pattern: regexp.MustCompile(`(?i)(VcpuLimitExceeded|InstanceLimitExceeded|AddressLimitExceeded|` +
+			`VpcLimitExceeded|NatGatewayLimitExceeded|quota exceeded|exceeded .*quota)`),
+		commands: func(cluster *cmv1.Cluster) []string {
+			return []string{fmt.Sprintf("rosa verify quota --region %s", cluster.Region().ID())}
+		},
+	},
+	{
+		Name: "OIDC trust",
+		Hint: "The operators can't assume their roles with the tokens of the cluster. Check that the " +
+			"OIDC provider exists and that the trust policies of the operator roles trust it.",
+		pattern: regexp.MustCompile(`(?i)(not authorized to perform:? sts:AssumeRoleWithWebIdentity|` +
+			`InvalidIdentityToken|No OpenIDConnect provider found)`),
+		commands: func(cluster *cmv1.Cluster) []string {
+			commands := []string{fmt.Sprintf("rosa verify operator-roles --cluster %s", cluster.ID())}
+			if cluster.AWS().STS().OidcConfig() != nil && cluster.AWS().STS().OidcConfig().ID() != "" {
+				commands = append(commands, fmt.Sprintf("rosa verify oidc-config --oidc-config-id %s",
+					cluster.AWS().STS().OidcConfig().ID()))
+			}
+			return commands
+		},
+	},
+	{
+		Name: "DNS resolution",
+		Hint: "The cluster names can't be resolved. Check that the VPC has the 'enableDnsHostnames' and " +
+			"'enableDnsSupport' attributes enabled, and that the DHCP options and private hosted zones " +
+			"of the VPC don't override the cluster domain.",
+		pattern: regexp.MustCompile(`(?i)(no such host|NoSuchHostedZone|HostedZoneNotFound|` +
+			`dns.*(resolution|lookup) failed|failed to resolve)`),
+	},
+	{
+		Name: "Subnets",
+		Hint: "The subnets of the cluster can't be used. Check that they exist in the region of the " +
+			"cluster, belong to the same VPC, have free IP addresses, and that the private subnets " +
+			"have a route to a NAT gateway.",
+		pattern: regexp.MustCompile(`(?i)(InvalidSubnetID|InsufficientFreeAddressesInSubnet|` +
+			`subnet .*(not found|does not exist)|no subnets? .*availability zone)`),
+	},
+	{
+		Name: "Permissions",
+		Hint: "The installer isn't allowed to perform an action. Check that the policies of the account " +
+			"roles haven't been modified, and that no permissions boundary denies the action.",
+		pattern:  regexp.MustCompile(`(?i)(UnauthorizedOperation|AccessDenied|not authorized to perform)`),
+		commands: verifyPermissions,
+	},
+}
+
+// verifyPermissions returns the commands that verify the permissions of the account roles of the
+// cluster, or of the AWS user for clusters that don't use STS.
+func verifyPermissions(cluster *cmv1.Cluster) []string {
+	roleARN := cluster.AWS().STS().RoleARN()
+	if roleARN == "" {
+		return []string{"rosa verify permissions"}
+	}
+	commands := []string{fmt.Sprintf("rosa verify permissions --role-arn %s", roleARN)}
+	prefix, err := aws.GetPrefixFromInstallerAccountRole(cluster)
+	if err == nil && prefix != "" {
+		command := fmt.Sprintf("rosa verify account-roles --prefix %s", prefix)
+		if cluster.Hypershift().Enabled() {
+			command += " --hosted-cp"
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// Finding is a known cause of the failure found in a line of the logs.
+type Finding struct {
+	Diagnosis *Diagnosis
+	Line      string
+	Commands  []string
+}
+
+// Diagnose returns the known causes of failure found in the lines, and in the provisioning error of
+// the cluster. Each cause is only reported once.
+func Diagnose(cluster *cmv1.Cluster, lines []string) []Finding {
+	if message := cluster.Status().ProvisionErrorMessage(); message != "" {
+		lines = append([]string{message}, lines...)
+	}
+	findings := []Finding{}
+	found := map[*Diagnosis]bool{}
+	for _, line := range lines {
+		for _, diagnosis := range Catalogue {
+			if !diagnosis.pattern.MatchString(line) {
+				continue
+			}
+			if !found[diagnosis] {
+				found[diagnosis] = true
+				finding := Finding{
+					Diagnosis: diagnosis,
+					Line:      line,
+				}
+				if diagnosis.commands != nil {
+					finding.Commands = diagnosis.commands(cluster)
+				}
+				findings = append(findings, finding)
+			}
+			break
+		}
+	}
+	return findings
+}
+
+// ReportProgress prints the phases of the installation of the cluster and, if it failed, the known
+// causes of the failure found in the errors of the logs and the commands that verify them.
+func ReportProgress(r *rosa.Runtime, cluster *cmv1.Cluster, progress *Progress) {
+	if cluster.State() == cmv1.ClusterStateError {
+		progress.Fail()
+	}
+	err := progress.Print(os.Stdout)
+	if err != nil {
+		r.Reporter.Errorf("Failed to print the install progress: %v", err)
+		return
+	}
+	// The installation may fail before the first phase starts, or the tail of the logs may not
+	// contain any phase, so the state of the cluster is checked too:
+	if !progress.Failed() && cluster.State() != cmv1.ClusterStateError {
+		return
+	}
+	findings := Diagnose(cluster, progress.Errors)
+	if len(findings) == 0 {
+		r.Reporter.Warnf("No known cause of the failure was found, run 'rosa logs install --cluster %s "+
+			"--level error' to see the errors", cluster.ID())
+		return
+	}
+	for _, finding := range findings {
+		message := fmt.Sprintf("Possible cause of the failure: %s\n%s\nFound in: %s",
+			finding.Diagnosis.Name, finding.Diagnosis.Hint, finding.Line)
+		if len(finding.Commands) > 0 {
+			message += fmt.Sprintf("\nTo verify it, run the following commands:\n\n%s\n",
+				strings.Join(finding.Commands, "\n"))
+		}
+		r.Reporter.Warnf("%s", message)
+	}
+}
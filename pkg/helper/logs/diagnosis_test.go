@@ -0,0 +1,74 @@
+package logs
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Diagnosis", func() {
+	var cluster *cmv1.Cluster
+
+	BeforeEach(func() {
+		var err error
+		cluster, err = cmv1.NewCluster().
+			ID("123").
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+				RoleARN("arn:aws:iam::123456789012:role/myprefix-Installer-Role").
+				OidcConfig(cmv1.NewOidcConfig().ID("456")))).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Matches the known causes once", func() {
+		findings := Diagnose(cluster, []string{
+			`level=error msg="creating EC2 Instance: VcpuLimitExceeded: You have requested more vCPU capacity"`,
+			`level=error msg="creating EC2 Instance: VcpuLimitExceeded: You have requested more vCPU capacity"`,
+			`level=error msg="WebIdentityErr: failed to retrieve credentials: InvalidIdentityToken"`,
+			`level=error msg="unrelated error"`,
+		})
+		Expect(findings).To(HaveLen(2))
+		Expect(findings[0].Diagnosis.Name).To(Equal("Quota exceeded"))
+		Expect(findings[0].Commands).To(Equal([]string{"rosa verify quota --region us-east-1"}))
+		Expect(findings[1].Diagnosis.Name).To(Equal("OIDC trust"))
+		Expect(findings[1].Commands).To(Equal([]string{
+			"rosa verify operator-roles --cluster 123",
+			"rosa verify oidc-config --oidc-config-id 456",
+		}))
+	})
+
+	It("Prefers service control policy denials to other permission errors", func() {
+		findings := Diagnose(cluster, []string{
+			`level=error msg="AccessDenied: User: arn:aws:sts::123456789012:assumed-role/myprefix-Installer-Role ` +
+				`is not authorized to perform: ec2:RunInstances with an explicit deny in a service control policy"`,
+		})
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Diagnosis.Name).To(Equal("Service control policy denial"))
+		Expect(findings[0].Commands).To(Equal([]string{
+			"rosa verify permissions --role-arn arn:aws:iam::123456789012:role/myprefix-Installer-Role",
+			"rosa verify account-roles --prefix myprefix",
+		}))
+	})
+
+	It("Doesn't report throttling as a quota problem", func() {
+		findings := Diagnose(cluster, []string{
+			`level=error msg="RequestLimitExceeded: Request limit exceeded."`,
+		})
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("Diagnoses the provisioning error of the cluster", func() {
+		var err error
+		cluster, err = cmv1.NewCluster().
+			ID("123").
+			Status(cmv1.NewClusterStatus().ProvisionErrorMessage("The subnet ID 'subnet-1' does not exist " +
+				"(InvalidSubnetID.NotFound)")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		findings := Diagnose(cluster, nil)
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Diagnosis.Name).To(Equal("Subnets"))
+		Expect(findings[0].Commands).To(BeEmpty())
+	})
+})
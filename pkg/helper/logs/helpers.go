@@ -0,0 +1,244 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Levels of the messages of the Hive logs, from the least to the most severe.
+var levels = []string{"trace", "debug", "info", "warning", "error", "fatal", "panic"}
+
+var timePattern = regexp.MustCompile(`\btime="([^"]+)"`)
+var levelPattern = regexp.MustCompile(`\blevel=([a-z]+)`)
+
+// DefaultTimeout is the time that the logs are watched by default.
+const DefaultTimeout = time.Hour
+
+// Options select the lines of the logs that are printed, and how.
+type Options struct {
+	Since      string
+	Grep       string
+	Level      string
+	Timeout    time.Duration
+	OutputFile string
+	Timestamps bool
+}
+
+// AddFlags adds the flags that set the options to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet, options *Options) {
+	flags.StringVar(
+		&options.Since,
+		"since",
+		"",
+		"Only show the lines logged after the given time, which can be a duration like '10m', "+
+			"or a timestamp like '2023-03-01T10:00:00Z'.",
+	)
+
+	flags.StringVar(
+		&options.Grep,
+		"grep",
+		"",
+		"Only show the lines that match the given regular expression.",
+	)
+
+	flags.StringVar(
+		&options.Level,
+		"level",
+		"",
+		fmt.Sprintf("Only show the lines logged with the given level or a more severe one. "+
+			"Allowed options are %s.", levels),
+	)
+
+	flags.DurationVar(
+		&options.Timeout,
+		"timeout",
+		DefaultTimeout,
+		"Maximum time to watch for changes.",
+	)
+
+	flags.StringVar(
+		&options.OutputFile,
+		"output-file",
+		"",
+		"Save the complete log, without filtering the lines, to the given file.",
+	)
+
+	flags.BoolVar(
+		&options.Timestamps,
+		"timestamps",
+		false,
+		"Show the time each line was logged at the beginning of the line.",
+	)
+}
+
+// Stream selects and formats the lines of consecutive responses of the logs API. Each response has
+// to contain the lines that follow the Offset of the stream, which is the number of complete lines
+// received so far, so that no line is received twice or lost between responses.
+type Stream struct {
+	started    bool
+	since      time.Time
+	grep       *regexp.Regexp
+	level      int
+	timestamps bool
+	output     *os.File
+	// lastTime and lastLevel are the time and level of the last line that had them, which are
+	// also the ones of the following lines that don't, like stack traces.
+	lastTime  time.Time
+	lastLevel int
+	// Offset is the number of complete lines received so far.
+	Offset int
+	// Tail, when positive, is the number of lines at the end of the first response that are
+	// returned. The rest of the lines are only saved to the output file.
+	Tail int
+	// Progress, when set, follows the phases of the installation in all the lines received,
+	// regardless of the filters, and Next returns the changes of the phases instead of the lines.
+	Progress *Progress
+}
+
+// NewStream checks the options and creates a stream that selects and formats the lines as they
+// say.
+func NewStream(options Options) (*Stream, error) {
+	stream := &Stream{
+		level:      -1,
+		lastLevel:  -1,
+		timestamps: options.Timestamps,
+	}
+	if options.Since != "" {
+		since, err := parseSince(options.Since, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		stream.since = since
+	}
+	if options.Grep != "" {
+		grep, err := regexp.Compile(options.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regular expression '%s': %v", options.Grep, err)
+		}
+		stream.grep = grep
+	}
+	if options.Level != "" {
+		stream.level = levelIndex(options.Level)
+		if stream.level < 0 {
+			return nil, fmt.Errorf("Invalid level '%s'. Allowed options are %s", options.Level, levels)
+		}
+	}
+	if options.Timeout <= 0 {
+		return nil, fmt.Errorf("Invalid timeout '%s', it must be positive", options.Timeout)
+	}
+	if options.OutputFile != "" {
+		output, err := os.OpenFile(options.OutputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create file '%s': %v", options.OutputFile, err)
+		}
+		stream.output = output
+	}
+	return stream, nil
+}
+
+// Close closes the file where the log is saved.
+func (s *Stream) Close() error {
+	if s.output == nil {
+		return nil
+	}
+	return s.output.Close()
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return now.Add(-duration), nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid value '%s' for '--since', it must be a duration like "+
+			"'10m' or a timestamp like '2023-03-01T10:00:00Z'", value)
+	}
+	return since, nil
+}
+
+func levelIndex(level string) int {
+	if level == "warn" {
+		level = "warning"
+	}
+	for i, value := range levels {
+		if value == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// Next returns the lines of the content that follows the offset, after saving them to the output
+// file, filtered and formatted as the options say. If the stream follows the progress of the
+// installation it returns the changes of the phases instead.
+func (s *Stream) Next(content string) ([]string, error) {
+	lines := strings.Split(content, "\n")
+	// The last element is either empty or a line that is still being written, which is received
+	// complete with the next response:
+	newLines := lines[:len(lines)-1]
+	s.Offset += len(newLines)
+	skip := 0
+	if !s.started && s.Tail > 0 && len(newLines) > s.Tail {
+		skip = len(newLines) - s.Tail
+	}
+	if len(newLines) > 0 {
+		s.started = true
+	}
+
+	if s.output != nil && len(newLines) > 0 {
+		_, err := fmt.Fprintf(s.output, "%s\n", strings.Join(newLines, "\n"))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to save the log: %v", err)
+		}
+	}
+
+	if s.Progress != nil {
+		return s.Progress.Add(newLines), nil
+	}
+
+	result := []string{}
+	for i, line := range newLines {
+		lineTime, lineLevel := s.parse(line)
+		if i < skip {
+			continue
+		}
+		if !s.since.IsZero() && !lineTime.IsZero() && lineTime.Before(s.since) {
+			continue
+		}
+		if s.level >= 0 && lineLevel >= 0 && lineLevel < s.level {
+			continue
+		}
+		if s.grep != nil && !s.grep.MatchString(line) {
+			continue
+		}
+		if s.timestamps {
+			if lineTime.IsZero() {
+				lineTime = time.Now()
+			}
+			line = lineTime.UTC().Format(time.RFC3339) + " " + line
+		}
+		result = append(result, line)
+	}
+	return result, nil
+}
+
+// parse returns the time and the level of the line, or the ones of the previous line if the line
+// doesn't have them.
+func (s *Stream) parse(line string) (time.Time, int) {
+	if match := timePattern.FindStringSubmatch(line); match != nil {
+		lineTime, err := time.Parse(time.RFC3339, match[1])
+		if err == nil {
+			s.lastTime = lineTime
+		}
+	}
+	if match := levelPattern.FindStringSubmatch(line); match != nil {
+		s.lastLevel = levelIndex(match[1])
+	}
+	return s.lastTime, s.lastLevel
+}
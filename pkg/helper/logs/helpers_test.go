@@ -0,0 +1,101 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logs", func() {
+	It("Keeps repeated lines and counts the complete lines received", func() {
+		stream, err := NewStream(Options{Timeout: DefaultTimeout})
+		Expect(err).NotTo(HaveOccurred())
+
+		lines, err := stream.Next("waiting\nwaiting\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"waiting", "waiting"}))
+		Expect(stream.Offset).To(Equal(2))
+
+		lines, err = stream.Next("waiting\ndo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"waiting"}))
+		Expect(stream.Offset).To(Equal(3))
+
+		lines, err = stream.Next("done\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"done"}))
+		Expect(stream.Offset).To(Equal(4))
+	})
+
+	It("Returns the tail of the first response and saves all of it", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "install.log")
+		stream, err := NewStream(Options{Timeout: DefaultTimeout, OutputFile: file})
+		Expect(err).NotTo(HaveOccurred())
+		stream.Tail = 2
+
+		lines, err := stream.Next("a\nb\nc\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"b", "c"}))
+
+		lines, err = stream.Next("d\ne\nf\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"d", "e", "f"}))
+		Expect(stream.Offset).To(Equal(6))
+		Expect(stream.Close()).To(Succeed())
+
+		saved, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(saved)).To(Equal("a\nb\nc\nd\ne\nf\n"))
+	})
+
+	It("Filters the lines and saves the complete log", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "install.log")
+		stream, err := NewStream(Options{
+			Since:      "2023-03-01T10:00:00Z",
+			Level:      "warning",
+			Grep:       "quota|stack",
+			Timeout:    DefaultTimeout,
+			OutputFile: file,
+			Timestamps: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		content := `time="2023-03-01T09:00:00Z" level=error msg="old quota error"
+time="2023-03-01T11:00:00Z" level=info msg="quota checked"
+time="2023-03-01T11:00:01Z" level=error msg="quota exceeded"
+  stack trace line
+`
+
+		lines, err := stream.Next(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stream.Close()).To(Succeed())
+
+		Expect(lines).To(Equal([]string{
+			`2023-03-01T11:00:01Z time="2023-03-01T11:00:01Z" level=error msg="quota exceeded"`,
+			`2023-03-01T11:00:01Z   stack trace line`,
+		}))
+		saved, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(saved)).To(Equal(content))
+	})
+
+	It("Accepts durations in '--since'", func() {
+		now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+		since, err := parseSince("30m", now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(since).To(Equal(now.Add(-30 * time.Minute)))
+	})
+
+	It("Rejects invalid options", func() {
+		_, err := NewStream(Options{Level: "loud", Timeout: DefaultTimeout})
+		Expect(err).To(MatchError(ContainSubstring("Invalid level 'loud'")))
+
+		_, err = NewStream(Options{Since: "yesterday", Timeout: DefaultTimeout})
+		Expect(err).To(MatchError(ContainSubstring("Invalid value 'yesterday' for '--since'")))
+
+		_, err = NewStream(Options{Grep: "(", Timeout: DefaultTimeout})
+		Expect(err).To(MatchError(ContainSubstring("Invalid regular expression")))
+	})
+})
@@ -0,0 +1,166 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"text/tabwriter"
+	"time"
+)
+
+// PhaseStatus is the status of a phase of the installation.
+type PhaseStatus string
+
+const (
+	PhasePending   PhaseStatus = "pending"
+	PhaseRunning   PhaseStatus = "running"
+	PhaseCompleted PhaseStatus = "completed"
+	PhaseFailed    PhaseStatus = "failed"
+)
+
+// Phase is a phase of the installation of a cluster.
+type Phase struct {
+	Name     string
+	Status   PhaseStatus
+	Started  time.Time
+	Finished time.Time
+}
+
+// phaseSignatures contains, in the order they happen, the phases of the installation and the
+// messages of the installer that tell that they started. A phase is completed when the next one
+// starts.
+var phaseSignatures = []struct {
+	name  string
+	start *regexp.Regexp
+}{
+	{"bootstrap", regexp.MustCompile(`Creating infrastructure resources|` +
+		`Waiting up to \S+ .*for the Kubernetes API`)},
+	{"control plane", regexp.MustCompile(`Waiting up to \S+ .*for bootstrapping to complete`)},
+	{"workers", regexp.MustCompile(`Destroying the bootstrap resources|` +
+		`It is now safe to remove the bootstrap resources|Waiting up to \S+ .*for the cluster .* to initialize`)},
+	{"operators", regexp.MustCompile(
+		`Waiting up to \S+ .*to ensure each cluster operator has finished progressing|` +
+			`Cluster operators? .* (are|is) still updating|Some cluster operators are still updating`)},
+}
+
+// completeSignature is the message of the installer that tells that the installation finished.
+var completeSignature = regexp.MustCompile(`Install complete!|Cluster is initialized`)
+
+var errorLevels = regexp.MustCompile(`\blevel=(error|fatal|panic)\b`)
+var fatalLevels = regexp.MustCompile(`\blevel=(fatal|panic)\b`)
+
+// Progress follows the phases of the installation of a cluster in the lines of the install logs.
+type Progress struct {
+	Phases []*Phase
+	// Errors contains the lines logged with error level, which are used to diagnose failures.
+	Errors   []string
+	lastTime time.Time
+}
+
+// NewProgress creates the progress of an installation that hasn't started yet.
+func NewProgress() *Progress {
+	progress := &Progress{}
+	for _, signature := range phaseSignatures {
+		progress.Phases = append(progress.Phases, &Phase{
+			Name:   signature.name,
+			Status: PhasePending,
+		})
+	}
+	return progress
+}
+
+// Add updates the phases with the lines of the install logs, and returns messages that describe
+// the changes.
+func (p *Progress) Add(lines []string) []string {
+	events := []string{}
+	for _, line := range lines {
+		if match := timePattern.FindStringSubmatch(line); match != nil {
+			lineTime, err := time.Parse(time.RFC3339, match[1])
+			if err == nil {
+				p.lastTime = lineTime
+			}
+		}
+		if errorLevels.MatchString(line) {
+			p.Errors = append(p.Errors, line)
+		}
+		if fatalLevels.MatchString(line) {
+			events = append(events, p.Fail()...)
+			continue
+		}
+		if completeSignature.MatchString(line) {
+			events = append(events, p.advance(len(p.Phases))...)
+			continue
+		}
+		for i := len(phaseSignatures) - 1; i >= 0; i-- {
+			if phaseSignatures[i].start.MatchString(line) {
+				events = append(events, p.advance(i)...)
+				break
+			}
+		}
+	}
+	return events
+}
+
+// advance completes the phases before the given one, and starts it unless it is past the last
+// phase.
+func (p *Progress) advance(index int) []string {
+	events := []string{}
+	for i, phase := range p.Phases {
+		switch {
+		case i < index && (phase.Status == PhasePending || phase.Status == PhaseRunning):
+			if phase.Started.IsZero() {
+				phase.Started = p.lastTime
+			}
+			phase.Status = PhaseCompleted
+			phase.Finished = p.lastTime
+			events = append(events, fmt.Sprintf("Phase '%s' completed", phase.Name))
+		case i == index && phase.Status == PhasePending:
+			phase.Status = PhaseRunning
+			phase.Started = p.lastTime
+			events = append(events, fmt.Sprintf("Phase '%s' started", phase.Name))
+		}
+	}
+	return events
+}
+
+// Fail marks the running phase as failed.
+func (p *Progress) Fail() []string {
+	for _, phase := range p.Phases {
+		if phase.Status == PhaseRunning {
+			phase.Status = PhaseFailed
+			phase.Finished = p.lastTime
+			return []string{fmt.Sprintf("Phase '%s' failed", phase.Name)}
+		}
+	}
+	return nil
+}
+
+// Failed returns true if any of the phases failed.
+func (p *Progress) Failed() bool {
+	for _, phase := range p.Phases {
+		if phase.Status == PhaseFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes a table with the status of the phases.
+func (p *Progress) Print(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "PHASE\tSTATUS\tSTARTED\tDURATION\n")
+	for _, phase := range p.Phases {
+		started := ""
+		duration := ""
+		if !phase.Started.IsZero() {
+			started = phase.Started.UTC().Format("2006-01-02 15:04:05")
+			finished := phase.Finished
+			if finished.IsZero() {
+				finished = p.lastTime
+			}
+			duration = finished.Sub(phase.Started).Round(time.Second).String()
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", phase.Name, phase.Status, started, duration)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,86 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var installLog = []string{
+	`time="2023-03-01T10:00:00Z" level=info msg="Creating infrastructure resources..."`,
+	`time="2023-03-01T10:05:00Z" level=info msg="Waiting up to 20m0s (until 10:25AM) for the Kubernetes API at ` +
+		`https://api.mycluster.example.com:6443..."`,
+	`time="2023-03-01T10:08:00Z" level=info msg="Waiting up to 30m0s (until 10:38AM) for bootstrapping to complete..."`,
+	`time="2023-03-01T10:20:00Z" level=info msg="Destroying the bootstrap resources..."`,
+	`time="2023-03-01T10:22:00Z" level=info msg="Waiting up to 40m0s (until 11:02AM) for the cluster at ` +
+		`https://api.mycluster.example.com:6443 to initialize..."`,
+	`time="2023-03-01T10:30:00Z" level=info msg="Waiting up to 30m0s (until 11:00AM) to ensure each cluster ` +
+		`operator has finished progressing..."`,
+}
+
+var _ = Describe("Progress", func() {
+	It("Follows the phases of the installation", func() {
+		progress := NewProgress()
+		events := progress.Add(installLog[:3])
+		Expect(events).To(Equal([]string{
+			"Phase 'bootstrap' started",
+			"Phase 'bootstrap' completed",
+			"Phase 'control plane' started",
+		}))
+		Expect(progress.Phases[0].Status).To(Equal(PhaseCompleted))
+		Expect(progress.Phases[0].Finished.Sub(progress.Phases[0].Started)).To(Equal(8 * time.Minute))
+		Expect(progress.Phases[1].Status).To(Equal(PhaseRunning))
+		Expect(progress.Phases[2].Status).To(Equal(PhasePending))
+
+		events = progress.Add(installLog[3:])
+		Expect(events).To(Equal([]string{
+			"Phase 'control plane' completed",
+			"Phase 'workers' started",
+			"Phase 'workers' completed",
+			"Phase 'operators' started",
+		}))
+
+		events = progress.Add([]string{`time="2023-03-01T10:45:00Z" level=info msg="Install complete!"`})
+		Expect(events).To(Equal([]string{"Phase 'operators' completed"}))
+		Expect(progress.Failed()).To(BeFalse())
+	})
+
+	It("Fails the running phase and keeps the errors", func() {
+		progress := NewProgress()
+		progress.Add(installLog[:3])
+		events := progress.Add([]string{
+			`time="2023-03-01T10:10:00Z" level=error msg="Error: creating EC2 Instance: VcpuLimitExceeded"`,
+			`time="2023-03-01T10:11:00Z" level=fatal msg="Bootstrap failed to complete"`,
+		})
+		Expect(events).To(Equal([]string{"Phase 'control plane' failed"}))
+		Expect(progress.Failed()).To(BeTrue())
+		Expect(progress.Errors).To(HaveLen(2))
+
+		buffer := &bytes.Buffer{}
+		Expect(progress.Print(buffer)).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		Expect(lines).To(HaveLen(5))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"bootstrap", "completed", "2023-03-01", "10:00:00",
+			"8m0s"}))
+		Expect(strings.Fields(lines[2])).To(Equal([]string{"control", "plane", "failed", "2023-03-01", "10:08:00",
+			"3m0s"}))
+		Expect(strings.Fields(lines[3])).To(Equal([]string{"workers", "pending"}))
+	})
+
+	It("Follows the progress in the lines of a stream", func() {
+		stream, err := NewStream(Options{Timeout: DefaultTimeout, Level: "error"})
+		Expect(err).NotTo(HaveOccurred())
+		stream.Progress = NewProgress()
+
+		events, err := stream.Next(strings.Join(installLog[:2], "\n") + "\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{"Phase 'bootstrap' started"}))
+
+		events, err = stream.Next(installLog[2] + "\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Equal([]string{"Phase 'bootstrap' completed", "Phase 'control plane' started"}))
+	})
+})
@@ -1,20 +1,29 @@
 package oidc_config
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1" //#nosec GSC-G505 -- Import blacklist: crypto/sha1
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"gopkg.in/square/go-jose.v2"
+	"io"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
 
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/cassette"
+	"github.com/openshift/rosa/pkg/har"
 	"github.com/openshift/rosa/pkg/helper"
 )
 
@@ -23,6 +32,9 @@ const (
 
 	prefixForPrivateKeySecret     = "rosa-private-key"
 	defaultPrefixForConfiguration = "oidc"
+
+	DiscoveryDocumentKey = ".well-known/openid-configuration"
+	JwksKey              = "keys.json"
 )
 
 type OidcConfigInput struct {
@@ -41,7 +53,7 @@ func BuildOidcConfigInput(userPrefix, region string) (OidcConfigInput, error) {
 		return OidcConfigInput{}, fmt.Errorf("There was a problem generating bucket name: %s", err)
 	}
 
-	privateKeySecretName := fmt.Sprintf("%s-%s", prefixForPrivateKeySecret, bucketName)
+	privateKeySecretName := PrivateKeySecretName(bucketName)
 	bucketUrl := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, region)
 	privateKey, publicKey, err := CreateKeyPair()
 	if err != nil {
@@ -64,6 +76,30 @@ func BuildOidcConfigInput(userPrefix, region string) (OidcConfigInput, error) {
 	}, nil
 }
 
+// PrivateKeySecretName returns the name of the secret that contains the private key of the OIDC
+// configuration stored in the given bucket.
+func PrivateKeySecretName(bucketName string) string {
+	return fmt.Sprintf("%s-%s", prefixForPrivateKeySecret, bucketName)
+}
+
+// BucketNameFromSecretArn returns the name of the bucket of an OIDC configuration created by rosa,
+// which is part of the name of the secret that contains its private key.
+func BucketNameFromSecretArn(secretArn string) (string, error) {
+	secretResourceName, err := aws.GetResourceIdFromSecretArn(secretArn)
+	if err != nil {
+		return "", err
+	}
+	// The secret when creating from ROSA options has the following format
+	// rosa-private-key-<prefix>-oidc-<random-hash-length-4>-<random-aws-created-hash>
+	// The bucket is expected to be <prefix>-oidc-<random-hash-length-4>
+	bucketName := strings.TrimPrefix(secretResourceName, prefixForPrivateKeySecret+"-")
+	index := strings.LastIndex(bucketName, "-")
+	if index != -1 {
+		bucketName = bucketName[:index]
+	}
+	return bucketName, nil
+}
+
 func GenerateBucketName(userPrefix string) (string, error) {
 	randomLabel := helper.RandomLabel(defaultLengthRandomLabel)
 	bucketName := fmt.Sprintf("%s-%s", defaultPrefixForConfiguration, randomLabel)
@@ -155,20 +191,277 @@ func GenerateDiscoveryDocument(bucketURL string) string {
 	return fmt.Sprintf(discoveryDocumentTemplate, bucketURL, bucketURL)
 }
 
+// DiscoveryDocument contains the fields of the discovery document generated by
+// GenerateDiscoveryDocument.
+type DiscoveryDocument struct {
+	Issuer                           string   `json:"issuer"`
+	JwksURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+// ValidateDiscoveryDocument checks that the discovery document published at the issuer URL
+// contains the values generated by GenerateDiscoveryDocument, and returns the ones that don't.
+func ValidateDiscoveryDocument(content []byte, issuerUrl string) ([]string, error) {
+	document := &DiscoveryDocument{}
+	err := json.Unmarshal(content, document)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse discovery document")
+	}
+
+	expected := &DiscoveryDocument{}
+	err = json.Unmarshal([]byte(GenerateDiscoveryDocument(issuerUrl)), expected)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []string{}
+	if document.Issuer != expected.Issuer {
+		problems = append(problems, fmt.Sprintf("'issuer' is '%s' instead of '%s'",
+			document.Issuer, expected.Issuer))
+	}
+	if document.JwksURI != expected.JwksURI {
+		problems = append(problems, fmt.Sprintf("'jwks_uri' is '%s' instead of '%s'",
+			document.JwksURI, expected.JwksURI))
+	}
+	problems = append(problems, missingValues("response_types_supported",
+		expected.ResponseTypesSupported, document.ResponseTypesSupported)...)
+	problems = append(problems, missingValues("subject_types_supported",
+		expected.SubjectTypesSupported, document.SubjectTypesSupported)...)
+	problems = append(problems, missingValues("id_token_signing_alg_values_supported",
+		expected.IDTokenSigningAlgValuesSupported, document.IDTokenSigningAlgValuesSupported)...)
+	problems = append(problems, missingValues("claims_supported",
+		expected.ClaimsSupported, document.ClaimsSupported)...)
+	return problems, nil
+}
+
+func missingValues(field string, expected []string, actual []string) []string {
+	present := map[string]bool{}
+	for _, value := range actual {
+		present[value] = true
+	}
+	problems := []string{}
+	for _, value := range expected {
+		if !present[value] {
+			problems = append(problems, fmt.Sprintf("'%s' doesn't contain '%s'", field, value))
+			// The generated document lists some claims twice:
+			present[value] = true
+		}
+	}
+	return problems
+}
+
+// GetThumbprint returns the SHA1 thumbprint of the certificate of the CA that signed the
+// certificate of the OIDC endpoint, as expected by the IAM OIDC provider.
+func GetThumbprint(oidcEndpointURL string) (string, error) {
+	connect, err := url.ParseRequestURI(oidcEndpointURL)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := httpClient().Get(fmt.Sprintf("https://%s:443", connect.Host))
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	// Replayed responses don't have the certificates of the connection:
+	if response.TLS == nil || len(response.TLS.PeerCertificates) == 0 {
+		return "", errors.Errorf("Failed to get the certificates of '%s'", connect.Host)
+	}
+	certChain := response.TLS.PeerCertificates
+
+	// Grab the CA in the chain
+	for _, cert := range certChain {
+		if cert.IsCA {
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+				return sha1Hash(cert.Raw), nil
+			}
+		}
+	}
+
+	// Fall back to using the last certficiate in the chain
+	cert := certChain[len(certChain)-1]
+	return sha1Hash(cert.Raw), nil
+}
+
+// httpClient returns the client used to connect to the OIDC endpoints, with the requests recorded
+// like the ones sent to OCM and AWS.
+func httpClient() *http.Client {
+	transport := http.DefaultTransport
+	if cassette.Enabled() {
+		transport = cassette.Transport(transport)
+	}
+	if har.Enabled() {
+		transport = har.Transport(transport)
+	}
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
+func sha1Hash(data []byte) string {
+	// nolint:gosec
+	hasher := sha1.New()
+	hasher.Write(data)
+	hashed := hasher.Sum(nil)
+	return hex.EncodeToString(hashed)
+}
+
+// FetchDocument downloads one of the documents, like the JSON web key set, that the OIDC
+// configuration publishes at the issuer URL.
+func FetchDocument(issuerUrl string, key string) ([]byte, error) {
+	documentUrl := fmt.Sprintf("%s/%s", strings.TrimSuffix(issuerUrl, "/"), key)
+	response, err := httpClient().Get(documentUrl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get '%s'", documentUrl)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Failed to get '%s': %s", documentUrl, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
 type JSONWebKeySet struct {
 	Keys []jose.JSONWebKey `json:"keys"`
 }
 
 // BuildJSONWebKeySet builds JSON web key set from the public key
 func BuildJSONWebKeySet(publicKeyContent []byte) ([]byte, error) {
+	key, err := buildJSONWebKey(publicKeyContent)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSONWebKeySet([]jose.JSONWebKey{key})
+}
+
+// AddKeyToJSONWebKeySet adds the public key to the JSON web key set, keeping the keys that it
+// already contains so that the tokens signed with them can still be verified.
+func AddKeyToJSONWebKeySet(jwks []byte, publicKeyContent []byte) ([]byte, error) {
+	keySet, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := buildJSONWebKey(publicKeyContent)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range keySet.Keys {
+		if existing.KeyID == key.KeyID {
+			return nil, errors.Errorf("JSON web key set already contains key '%s'", key.KeyID)
+		}
+	}
+
+	return marshalJSONWebKeySet(append(keySet.Keys, key))
+}
+
+// RemoveKeysFromJSONWebKeySet removes from the JSON web key set all the keys except the one with the
+// given key ID, and returns the new key set and the IDs of the removed keys.
+func RemoveKeysFromJSONWebKeySet(jwks []byte, keepKeyID string) ([]byte, []string, error) {
+	keySet, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []jose.JSONWebKey
+	removed := []string{}
+	for _, key := range keySet.Keys {
+		if key.KeyID == keepKeyID {
+			keys = append(keys, key)
+		} else {
+			removed = append(removed, key.KeyID)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil, errors.Errorf("JSON web key set doesn't contain key '%s'", keepKeyID)
+	}
+
+	result, err := marshalJSONWebKeySet(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, removed, nil
+}
+
+// ParseJSONWebKeySet parses a JSON web key set, like the one published by an OIDC configuration.
+func ParseJSONWebKeySet(jwks []byte) (*JSONWebKeySet, error) {
+	keySet := &JSONWebKeySet{}
+	err := json.Unmarshal(jwks, keySet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse JSON web key set")
+	}
+
+	return keySet, nil
+}
+
+// ValidateJSONWebKeySet checks that the keys of the JSON web key set can be used to verify the
+// tokens signed by the OIDC configuration, and returns the problems found.
+func ValidateJSONWebKeySet(jwks []byte) ([]string, error) {
+	keySet, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []string{}
+	if len(keySet.Keys) == 0 {
+		problems = append(problems, "it doesn't contain any key")
+	}
+	for _, key := range keySet.Keys {
+		publicKey, ok := key.Key.(*rsa.PublicKey)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("key '%s' isn't an RSA public key", key.KeyID))
+			continue
+		}
+		if key.Algorithm != string(jose.RS256) {
+			problems = append(problems, fmt.Sprintf("key '%s' has algorithm '%s' instead of '%s'",
+				key.KeyID, key.Algorithm, jose.RS256))
+		}
+		if key.Use != "sig" {
+			problems = append(problems, fmt.Sprintf("key '%s' has use '%s' instead of 'sig'", key.KeyID, key.Use))
+		}
+		kid, err := keyIDFromPublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		if kid != key.KeyID {
+			problems = append(problems, fmt.Sprintf("key '%s' should have ID '%s'", key.KeyID, kid))
+		}
+	}
+	return problems, nil
+}
+
+// KeyIDFromPrivateKey returns the ID, in the JSON web key set, of the public key that corresponds
+// to the private key.
+func KeyIDFromPrivateKey(privateKeyContent []byte) (string, error) {
+	block, _ := pem.Decode(privateKeyContent)
+	if block == nil {
+		return "", errors.Errorf("Failed to decode PEM file")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to parse private key content")
+	}
+
+	return keyIDFromPublicKey(&privateKey.PublicKey)
+}
+
+func buildJSONWebKey(publicKeyContent []byte) (jose.JSONWebKey, error) {
 	block, _ := pem.Decode(publicKeyContent)
 	if block == nil {
-		return nil, errors.Errorf("Failed to decode PEM file")
+		return jose.JSONWebKey{}, errors.Errorf("Failed to decode PEM file")
 	}
 
 	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to parse key content")
+		return jose.JSONWebKey{}, errors.Wrapf(err, "Failed to parse key content")
 	}
 
 	var alg jose.SignatureAlgorithm
@@ -176,22 +469,23 @@ func BuildJSONWebKeySet(publicKeyContent []byte) ([]byte, error) {
 	case *rsa.PublicKey:
 		alg = jose.RS256
 	default:
-		return nil, errors.Errorf("Public key is not of type RSA")
+		return jose.JSONWebKey{}, errors.Errorf("Public key is not of type RSA")
 	}
 
 	kid, err := keyIDFromPublicKey(publicKey)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to fetch key ID from public key")
+		return jose.JSONWebKey{}, errors.Wrapf(err, "Failed to fetch key ID from public key")
 	}
 
-	var keys []jose.JSONWebKey
-	keys = append(keys, jose.JSONWebKey{
+	return jose.JSONWebKey{
 		Key:       publicKey,
 		KeyID:     kid,
 		Algorithm: string(alg),
 		Use:       "sig",
-	})
+	}, nil
+}
 
+func marshalJSONWebKeySet(keys []jose.JSONWebKey) ([]byte, error) {
 	keySet, err := json.MarshalIndent(JSONWebKeySet{Keys: keys}, "", "    ")
 	if err != nil {
 		return nil, errors.Wrapf(err, "JSON encoding of web key set failed")
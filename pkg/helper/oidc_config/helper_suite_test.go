@@ -0,0 +1,13 @@
+package oidc_config
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOidcConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OIDC Config Suite")
+}
@@ -0,0 +1,156 @@
+package oidc_config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/cassette"
+)
+
+var _ = Describe("JSON web key set", func() {
+	var previousPrivateKey, previousPublicKey, privateKey, publicKey []byte
+
+	BeforeEach(func() {
+		var err error
+		previousPrivateKey, previousPublicKey, err = CreateKeyPair()
+		Expect(err).ToNot(HaveOccurred())
+		privateKey, publicKey, err = CreateKeyPair()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("keeps the previous key when adding a new one and removes it afterwards", func() {
+		jwks, err := BuildJSONWebKeySet(previousPublicKey)
+		Expect(err).ToNot(HaveOccurred())
+		previousKeyID, err := KeyIDFromPrivateKey(previousPrivateKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyID, err := KeyIDFromPrivateKey(privateKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		jwks, err = AddKeyToJSONWebKeySet(jwks, publicKey)
+		Expect(err).ToNot(HaveOccurred())
+		keySet, err := ParseJSONWebKeySet(jwks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keySet.Keys).To(HaveLen(2))
+		Expect(keySet.Keys[0].KeyID).To(Equal(previousKeyID))
+		Expect(keySet.Keys[1].KeyID).To(Equal(keyID))
+
+		_, err = AddKeyToJSONWebKeySet(jwks, publicKey)
+		Expect(err).To(MatchError(ContainSubstring(keyID)))
+
+		jwks, removed, err := RemoveKeysFromJSONWebKeySet(jwks, keyID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal([]string{previousKeyID}))
+		keySet, err = ParseJSONWebKeySet(jwks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keySet.Keys).To(HaveLen(1))
+		Expect(keySet.Keys[0].KeyID).To(Equal(keyID))
+	})
+
+	It("fails to remove the keys when the current key isn't published", func() {
+		jwks, err := BuildJSONWebKeySet(previousPublicKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyID, err := KeyIDFromPrivateKey(privateKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _, err = RemoveKeysFromJSONWebKeySet(jwks, keyID)
+		Expect(err).To(MatchError(ContainSubstring(keyID)))
+	})
+})
+
+var _ = Describe("BucketNameFromSecretArn", func() {
+	It("removes the prefix and the suffix of the secret name", func() {
+		bucketName, err := BucketNameFromSecretArn(
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:rosa-private-key-mine-oidc-a1b2-XyZ123")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bucketName).To(Equal("mine-oidc-a1b2"))
+	})
+})
+
+var _ = Describe("ValidateDiscoveryDocument", func() {
+	const issuerUrl = "https://mine-oidc-a1b2.s3.us-east-1.amazonaws.com"
+
+	It("accepts the generated document", func() {
+		problems, err := ValidateDiscoveryDocument([]byte(GenerateDiscoveryDocument(issuerUrl)), issuerUrl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+	})
+
+	It("reports the values that differ from the generated ones", func() {
+		document := `{
+			"issuer": "https://other.example.com",
+			"jwks_uri": "https://mine-oidc-a1b2.s3.us-east-1.amazonaws.com/keys.json",
+			"response_types_supported": ["id_token"],
+			"subject_types_supported": ["public"],
+			"id_token_signing_alg_values_supported": ["ES256"],
+			"claims_supported": ["aud", "exp", "sub", "iat"]
+		}`
+		problems, err := ValidateDiscoveryDocument([]byte(document), issuerUrl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(ConsistOf(
+			"'issuer' is 'https://other.example.com' instead of '"+issuerUrl+"'",
+			"'id_token_signing_alg_values_supported' doesn't contain 'RS256'",
+			"'claims_supported' doesn't contain 'iss'",
+		))
+	})
+})
+
+var _ = Describe("ValidateJSONWebKeySet", func() {
+	It("reports keys whose ID doesn't match the public key", func() {
+		_, publicKey, err := CreateKeyPair()
+		Expect(err).ToNot(HaveOccurred())
+		jwks, err := BuildJSONWebKeySet(publicKey)
+		Expect(err).ToNot(HaveOccurred())
+		problems, err := ValidateJSONWebKeySet(jwks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+
+		keySet, err := ParseJSONWebKeySet(jwks)
+		Expect(err).ToNot(HaveOccurred())
+		keySet.Keys[0].KeyID = "other"
+		jwks, err = json.Marshal(keySet)
+		Expect(err).ToNot(HaveOccurred())
+		problems, err = ValidateJSONWebKeySet(jwks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(ConsistOf(HavePrefix("key 'other' should have ID")))
+	})
+
+	It("reports an empty key set", func() {
+		problems, err := ValidateJSONWebKeySet([]byte(`{"keys":[]}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(Equal([]string{"it doesn't contain any key"}))
+	})
+})
+
+var _ = Describe("FetchDocument", func() {
+	AfterEach(func() {
+		os.Unsetenv(cassette.ModeEnv)
+		os.Unsetenv(cassette.DirEnv)
+	})
+
+	It("records the documents to the cassette and replays them", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		}))
+		args := []string{"create", "oidc-config", "--mode", "auto"}
+		os.Setenv(cassette.DirEnv, GinkgoT().TempDir())
+
+		os.Setenv(cassette.ModeEnv, cassette.ModeRecord)
+		Expect(cassette.Start("rosa create oidc-config", args)).To(Succeed())
+		document, err := FetchDocument(server.URL, JwksKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(document)).To(Equal(`{"keys":[]}`))
+		server.Close()
+
+		os.Setenv(cassette.ModeEnv, cassette.ModeReplay)
+		Expect(cassette.Start("rosa create oidc-config", args)).To(Succeed())
+		document, err = FetchDocument(server.URL, JwksKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(document)).To(Equal(`{"keys":[]}`))
+	})
+})
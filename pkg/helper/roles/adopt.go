@@ -0,0 +1,68 @@
+package roles
+
+import (
+	"fmt"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// AdoptedRole is a role created outside of rosa, together with the tags that rosa needs to
+// recognise it and its permission policy.
+type AdoptedRole struct {
+	Name string
+	Tags map[string]string
+	// PolicyARN is the customer managed permission policy of the role, empty when it uses AWS
+	// managed policies.
+	PolicyARN  string
+	PolicyTags map[string]string
+}
+
+// AdoptRoles adds the tags to the roles and to their permission policies. In manual mode it prints
+// the commands that add them instead.
+func AdoptRoles(r *rosa.Runtime, mode string, adoptedRoles []AdoptedRole) error {
+	switch mode {
+	case aws.ModeAuto:
+		for _, role := range adoptedRoles {
+			if !confirm.Confirm("add the ROSA tags to role '%s'", role.Name) {
+				continue
+			}
+			if role.PolicyARN != "" {
+				err := r.AWSClient.AddPolicyTags(role.PolicyARN, role.PolicyTags)
+				if err != nil {
+					return fmt.Errorf("Failed to tag policy '%s': %v", role.PolicyARN, err)
+				}
+			}
+			err := r.AWSClient.AddRoleTags(role.Name, role.Tags)
+			if err != nil {
+				return fmt.Errorf("Failed to tag role '%s': %v", role.Name, err)
+			}
+			r.Reporter.Infof("Role '%s' is now managed by ROSA", role.Name)
+		}
+	case aws.ModeManual:
+		commands := []string{}
+		for _, role := range adoptedRoles {
+			if role.PolicyARN != "" {
+				commands = append(commands, awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.TagPolicy).
+					AddTags(role.PolicyTags).
+					AddParam(awscb.PolicyArn, role.PolicyARN).
+					Build())
+			}
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.TagRole).
+				AddTags(role.Tags).
+				AddParam(awscb.RoleName, role.Name).
+				Build())
+		}
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Run the following commands to add the ROSA tags to the roles:")
+		}
+		fmt.Println(awscb.JoinCommands(commands))
+	default:
+		return fmt.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
 	awscbRoles "github.com/openshift/rosa/pkg/aws/commandbuilder/helper/roles"
+	"github.com/openshift/rosa/pkg/aws/policydiff"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
@@ -254,3 +255,18 @@ func upgradeMissingOperatorRole(missingRoles map[string]*cmv1.STSOperator, clust
 	}
 	return nil
 }
+
+// ReportPolicyDrift compares the policy document with the one expected by ROSA and reports the
+// differences. It returns true if the documents are different.
+func ReportPolicyDrift(r *rosa.Runtime, description string, expected string, actual string) (bool, error) {
+	differences, err := policydiff.Compare(expected, actual)
+	if err != nil {
+		return false, fmt.Errorf("Failed to compare %s: %v", description, err)
+	}
+	if len(differences) == 0 {
+		r.Reporter.Infof("The %s matches the expected policy", description)
+		return false, nil
+	}
+	r.Reporter.Warnf("The %s differs from the expected policy:\n%s", description, policydiff.Format(differences))
+	return true, nil
+}
@@ -0,0 +1,200 @@
+package roles
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// PolicyRollback is the restoration of a previous version of a permission policy of a role.
+type PolicyRollback struct {
+	RoleName  string
+	PolicyARN string
+	VersionID string
+	// TagRole is true when the role also has the OpenShift version tag, which is reverted too.
+	TagRole bool
+}
+
+// customerManagedPolicies returns the ARNs of the customer managed policies attached to the role,
+// which are the only ones whose versions can be changed.
+func customerManagedPolicies(r *rosa.Runtime, roleName string) ([]string, error) {
+	attached, err := r.AWSClient.GetAttachedPolicy(&roleName)
+	if err != nil {
+		return nil, err
+	}
+	policyARNs := []string{}
+	for _, policy := range attached {
+		if policy.PolicType != aws.Attached || aws.IsAWSManagedPolicy(policy.PolicyArn) {
+			continue
+		}
+		policyARNs = append(policyARNs, policy.PolicyArn)
+	}
+	return policyARNs, nil
+}
+
+// PrintPolicyHistory prints the versions of the permission policies of the roles, and the versions
+// of OpenShift and rosa that created them.
+func PrintPolicyHistory(r *rosa.Runtime, roleNames []string) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ROLE NAME\tPOLICY ARN\tVERSION\tDEFAULT\tCREATED\tOPENSHIFT VERSION\tROSA VERSION\n")
+	for _, roleName := range roleNames {
+		policyARNs, err := customerManagedPolicies(r, roleName)
+		if err != nil {
+			return fmt.Errorf("Failed to get the policies of role '%s': %v", roleName, err)
+		}
+		for _, policyARN := range policyARNs {
+			versions, err := r.AWSClient.ListPolicyVersions(policyARN)
+			if err != nil {
+				return fmt.Errorf("Failed to get the versions of policy '%s': %v", policyARN, err)
+			}
+			for _, version := range versions {
+				isDefault := ""
+				if version.IsDefault {
+					isDefault = "yes"
+				}
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", roleName, policyARN, version.ID, isDefault,
+					version.CreateDate.Format("2006-01-02 15:04"), valueOrUnknown(version.OpenShiftVersion),
+					valueOrUnknown(version.RosaVersion))
+			}
+		}
+	}
+	return writer.Flush()
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// PlanRollback returns the policy versions of the role that were created for the given OpenShift
+// version and have to be restored. It fails if any of the policies of the role doesn't have a
+// version for it, because rolling back only some of them would leave the role inconsistent.
+func PlanRollback(r *rosa.Runtime, roleName string, toVersion string) ([]PolicyRollback, error) {
+	roleTags, err := r.AWSClient.GetRoleTags(roleName)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			return nil, fmt.Errorf("Role '%s' doesn't exist", roleName)
+		}
+		return nil, err
+	}
+	_, tagRole := roleTags[tags.OpenShiftVersion]
+
+	policyARNs, err := customerManagedPolicies(r, roleName)
+	if err != nil {
+		return nil, err
+	}
+	rollbacks := []PolicyRollback{}
+	for _, policyARN := range policyARNs {
+		versions, err := r.AWSClient.ListPolicyVersions(policyARN)
+		if err != nil {
+			return nil, err
+		}
+		// The versions are sorted from the newest, so the last version created for the OpenShift
+		// version is restored:
+		var restore *aws.PolicyVersion
+		for i := range versions {
+			if versions[i].OpenShiftVersion == toVersion {
+				restore = &versions[i]
+				break
+			}
+		}
+		if restore == nil {
+			return nil, fmt.Errorf("Policy '%s' of role '%s' doesn't have a version for OpenShift '%s', "+
+				"use '--history' to see the versions that can be restored", policyARN, roleName, toVersion)
+		}
+		if restore.IsDefault && (!tagRole || roleTags[tags.OpenShiftVersion] == toVersion) {
+			continue
+		}
+		rollbacks = append(rollbacks, PolicyRollback{
+			RoleName:  roleName,
+			PolicyARN: policyARN,
+			VersionID: restore.ID,
+			TagRole:   tagRole,
+		})
+	}
+	return rollbacks, nil
+}
+
+// Rollback restores the policy versions and reverts the OpenShift version tags of the policies and
+// the roles. In manual mode it prints the commands that do it instead.
+func Rollback(r *rosa.Runtime, mode string, toVersion string, rollbacks []PolicyRollback) error {
+	versionTag := map[string]string{tags.OpenShiftVersion: toVersion}
+	switch mode {
+	case aws.ModeAuto:
+		// A role is only tagged with the OpenShift version when all its policies are restored:
+		roleNames := []string{}
+		declined := map[string]bool{}
+		for _, rollback := range rollbacks {
+			if rollback.TagRole && !helper.Contains(roleNames, rollback.RoleName) {
+				roleNames = append(roleNames, rollback.RoleName)
+			}
+			if !confirm.Confirm("restore version '%s' of policy '%s'", rollback.VersionID, rollback.PolicyARN) {
+				declined[rollback.RoleName] = true
+				continue
+			}
+			err := r.AWSClient.SetDefaultPolicyVersion(rollback.PolicyARN, rollback.VersionID)
+			if err != nil {
+				return fmt.Errorf("Failed to restore version '%s' of policy '%s': %v", rollback.VersionID,
+					rollback.PolicyARN, err)
+			}
+			err = r.AWSClient.AddPolicyTags(rollback.PolicyARN, versionTag)
+			if err != nil {
+				return fmt.Errorf("Failed to tag policy '%s': %v", rollback.PolicyARN, err)
+			}
+			r.Reporter.Infof("Restored version '%s' of policy '%s'", rollback.VersionID, rollback.PolicyARN)
+		}
+		for _, roleName := range roleNames {
+			if declined[roleName] {
+				r.Reporter.Warnf("Role '%s' keeps its OpenShift version tag because some of its policies "+
+					"weren't restored", roleName)
+				continue
+			}
+			err := r.AWSClient.AddRoleTags(roleName, versionTag)
+			if err != nil {
+				return fmt.Errorf("Failed to tag role '%s': %v", roleName, err)
+			}
+		}
+	case aws.ModeManual:
+		commands := []string{}
+		taggedRoles := map[string]bool{}
+		for _, rollback := range rollbacks {
+			commands = append(commands,
+				awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.SetDefaultPolicyVersion).
+					AddParam(awscb.PolicyArn, rollback.PolicyARN).
+					AddParam(awscb.VersionId, rollback.VersionID).
+					Build(),
+				awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.TagPolicy).
+					AddTags(versionTag).
+					AddParam(awscb.PolicyArn, rollback.PolicyARN).
+					Build())
+			if rollback.TagRole && !taggedRoles[rollback.RoleName] {
+				taggedRoles[rollback.RoleName] = true
+				commands = append(commands, awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.TagRole).
+					AddTags(versionTag).
+					AddParam(awscb.RoleName, rollback.RoleName).
+					Build())
+			}
+		}
+		if r.Reporter.IsTerminal() {
+			r.Reporter.Infof("Run the following commands to restore the policies:")
+		}
+		fmt.Println(awscb.JoinCommands(commands))
+	default:
+		return fmt.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+	return nil
+}
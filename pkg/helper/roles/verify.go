@@ -0,0 +1,293 @@
+package roles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/policydiff"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// RoleVerification is the result of comparing the policies of a role with the ones expected by ROSA.
+type RoleVerification struct {
+	// Exists is false when the role doesn't exist.
+	Exists bool
+	// Drift is true when the role doesn't exist or any of its policies has been modified.
+	Drift bool
+	// PolicyARN is the customer managed permission policy of the role, empty when it uses AWS
+	// managed policies or doesn't have a permission policy.
+	PolicyARN string
+	// ManagedPolicies is true when the role uses AWS managed policies.
+	ManagedPolicies bool
+}
+
+// VerifyAccountRole compares the trust policy and the permission policy of the account role with
+// the ones expected by ROSA, reporting the differences.
+func VerifyAccountRole(r *rosa.Runtime, env string, file string, roleName string,
+	policies map[string]*cmv1.AWSSTSPolicy) (*RoleVerification, error) {
+	trustPolicy, err := r.AWSClient.GetRoleTrustPolicy(roleName)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+			return &RoleVerification{Drift: true}, nil
+		}
+		return nil, err
+	}
+	result := &RoleVerification{Exists: true}
+	expectedTrustPolicy := aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_trust_policy", file)),
+		map[string]string{
+			"partition":      aws.GetPartition(),
+			"aws_account_id": aws.GetJumpAccount(env),
+		})
+	result.Drift, err = ReportPolicyDrift(r, fmt.Sprintf("trust policy of role '%s'", roleName),
+		expectedTrustPolicy, trustPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := r.AWSClient.GetAttachedPolicyDocuments(roleName)
+	if err != nil {
+		return nil, err
+	}
+	managed, customer := splitPolicies(documents)
+	if len(managed) > 0 {
+		result.ManagedPolicies = true
+		managedDrift := verifyManagedPolicies(r, roleName, managed, policies, [][]string{
+			aws.GetAccountRolePolicyKeys(file),
+			{fmt.Sprintf("sts_hcp_%s_permission_policy", file)},
+		})
+		unexpectedDrift := reportUnexpectedPolicies(r, roleName, customer, "")
+		result.Drift = result.Drift || managedDrift || unexpectedDrift
+		return result, nil
+	}
+	policyName := aws.GetPolicyName(roleName)
+	for _, policyARN := range customer {
+		name, err := aws.GetResourceIdFromARN(policyARN)
+		if err == nil && name == policyName {
+			result.PolicyARN = policyARN
+			break
+		}
+	}
+	if result.PolicyARN == "" {
+		r.Reporter.Warnf("Role '%s' doesn't have the permission policy '%s' attached", roleName, policyName)
+		reportUnexpectedPolicies(r, roleName, customer, "")
+		result.Drift = true
+		return result, nil
+	}
+	expectedPolicy := aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_permission_policy", file)),
+		map[string]string{
+			"partition": aws.GetPartition(),
+		})
+	policyDrift, err := ReportPolicyDrift(r, fmt.Sprintf("permission policy '%s'", result.PolicyARN),
+		expectedPolicy, documents[result.PolicyARN])
+	if err != nil {
+		return nil, err
+	}
+	unexpectedDrift := reportUnexpectedPolicies(r, roleName, customer, result.PolicyARN)
+	result.Drift = result.Drift || policyDrift || unexpectedDrift
+	return result, nil
+}
+
+// VerifyOperatorRole compares the trust policy and the permission policy of the operator role with
+// the ones expected by ROSA, reporting the differences. The trust policy is only compared when the
+// OIDC endpoint URL is known.
+func VerifyOperatorRole(r *rosa.Runtime, credRequest string, roleName string, operator *cmv1.STSOperator,
+	oidcEndpointURL string, policies map[string]*cmv1.AWSSTSPolicy) (*RoleVerification, error) {
+	trustPolicy, err := r.AWSClient.GetRoleTrustPolicy(roleName)
+	if err != nil {
+		if errors.GetType(err) == errors.NotFound {
+			r.Reporter.Warnf("Role '%s' doesn't exist", roleName)
+			return &RoleVerification{Drift: true}, nil
+		}
+		return nil, err
+	}
+	result := &RoleVerification{Exists: true}
+	if oidcEndpointURL != "" {
+		expectedTrustPolicy, err := aws.GenerateOperatorRolePolicyDocByOidcEndpointUrl(oidcEndpointURL,
+			r.Creator.AccountID, operator, aws.GetPolicyDetails(policies, "operator_iam_role_policy"))
+		if err != nil {
+			return nil, err
+		}
+		result.Drift, err = ReportPolicyDrift(r, fmt.Sprintf("trust policy of role '%s'", roleName),
+			expectedTrustPolicy, trustPolicy)
+		if err != nil {
+			return nil, err
+		}
+		trustDrift, err := verifyOperatorTrustPolicy(r, roleName, operator, oidcEndpointURL, trustPolicy,
+			expectedTrustPolicy)
+		if err != nil {
+			return nil, err
+		}
+		result.Drift = result.Drift || trustDrift
+	}
+
+	documents, err := r.AWSClient.GetAttachedPolicyDocuments(roleName)
+	if err != nil {
+		return nil, err
+	}
+	managed, customer := splitPolicies(documents)
+	if len(managed) > 0 {
+		result.ManagedPolicies = true
+		managedDrift := verifyManagedPolicies(r, roleName, managed, policies, [][]string{
+			{aws.GetOperatorPolicyKey(credRequest, false)},
+			{aws.GetOperatorPolicyKey(credRequest, true)},
+		})
+		unexpectedDrift := reportUnexpectedPolicies(r, roleName, customer, "")
+		result.Drift = result.Drift || managedDrift || unexpectedDrift
+		return result, nil
+	}
+	expectedPolicy := aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, aws.GetOperatorPolicyKey(credRequest, false)),
+		map[string]string{
+			"partition": aws.GetPartition(),
+		})
+	// The name of the permission policy depends on the prefix used to create it, which may not be
+	// the prefix of the role, so the attached policy that is closest to the expected one is used:
+	policyARN := ""
+	var differences []policydiff.Difference
+	for _, arn := range customer {
+		documentDifferences, err := policydiff.Compare(expectedPolicy, documents[arn])
+		if err != nil {
+			return nil, err
+		}
+		if policyARN == "" || len(documentDifferences) < len(differences) ||
+			(len(documentDifferences) == len(differences) && arn < policyARN) {
+			policyARN = arn
+			differences = documentDifferences
+		}
+	}
+	if policyARN == "" {
+		r.Reporter.Warnf("Role '%s' doesn't have a permission policy attached", roleName)
+		result.Drift = true
+		return result, nil
+	}
+	policyDrift, err := ReportPolicyDrift(r, fmt.Sprintf("permission policy '%s'", policyARN),
+		expectedPolicy, documents[policyARN])
+	if err != nil {
+		return nil, err
+	}
+	unexpectedDrift := reportUnexpectedPolicies(r, roleName, customer, policyARN)
+	result.Drift = result.Drift || policyDrift || unexpectedDrift
+	result.PolicyARN = policyARN
+	return result, nil
+}
+
+// splitPolicies returns the ARNs of the AWS managed policies and of the customer managed policies
+// of the given documents, sorted so that the results don't depend on the order of the map.
+func splitPolicies(documents map[string]string) (managed []string, customer []string) {
+	for arn := range documents {
+		if aws.IsAWSManagedPolicy(arn) {
+			managed = append(managed, arn)
+		} else {
+			customer = append(customer, arn)
+		}
+	}
+	sort.Strings(managed)
+	sort.Strings(customer)
+	return
+}
+
+// verifyManagedPolicies checks that the AWS managed policies attached to the role are exactly the
+// ones that ROSA attaches to it. The keys are the alternative sets of policies that ROSA can attach,
+// for example the ones of classic and hosted control plane clusters. It reports the policies that
+// aren't expected or that are missing, and returns true if there is any.
+func verifyManagedPolicies(r *rosa.Runtime, roleName string, attached []string,
+	policies map[string]*cmv1.AWSSTSPolicy, keys [][]string) bool {
+	// The expected set is the one that contains the most of the attached policies:
+	var expected []string
+	matches := -1
+	for _, set := range keys {
+		arns := []string{}
+		for _, key := range set {
+			arn, err := aws.GetManagedPolicyARN(policies, key)
+			if err == nil {
+				arns = append(arns, arn)
+			}
+		}
+		count := 0
+		for _, arn := range attached {
+			if helper.Contains(arns, arn) {
+				count++
+			}
+		}
+		if count > matches {
+			expected = arns
+			matches = count
+		}
+	}
+	drift := false
+	for _, arn := range attached {
+		if helper.Contains(expected, arn) {
+			r.Reporter.Infof("Role '%s' uses the managed policy '%s', which can't be modified", roleName, arn)
+			continue
+		}
+		r.Reporter.Warnf("Role '%s' has the policy '%s' attached, which isn't a ROSA managed policy",
+			roleName, arn)
+		drift = true
+	}
+	for _, arn := range expected {
+		if !helper.Contains(attached, arn) {
+			r.Reporter.Warnf("Role '%s' doesn't have the managed policy '%s' attached", roleName, arn)
+			drift = true
+		}
+	}
+	return drift
+}
+
+// reportUnexpectedPolicies reports the customer managed policies attached to the role other than
+// the expected one, and returns true if there is any.
+func reportUnexpectedPolicies(r *rosa.Runtime, roleName string, customer []string, expected string) bool {
+	drift := false
+	for _, arn := range customer {
+		if arn == expected {
+			continue
+		}
+		r.Reporter.Warnf("Role '%s' has the policy '%s' attached, which isn't expected by ROSA", roleName, arn)
+		drift = true
+	}
+	return drift
+}
+
+// verifyOperatorTrustPolicy checks that the trust policy of the role allows the service accounts of
+// the operator to assume it with the web identities issued by the OIDC endpoint, and prints the
+// command that replaces the trust policy when it doesn't.
+func verifyOperatorTrustPolicy(r *rosa.Runtime, roleName string, operator *cmv1.STSOperator,
+	oidcEndpointURL string, trustPolicy string, expectedTrustPolicy string) (bool, error) {
+	mismatches, err := aws.ValidateOperatorRoleTrustPolicy(trustPolicy, oidcEndpointURL, r.Creator.AccountID,
+		operator)
+	if err != nil {
+		return false, fmt.Errorf("Failed to validate the trust policy: %v", err)
+	}
+	if len(mismatches) == 0 {
+		return false, nil
+	}
+	compacted := &bytes.Buffer{}
+	err = json.Compact(compacted, []byte(expectedTrustPolicy))
+	if err != nil {
+		return false, err
+	}
+	command := awscb.NewIAMCommandBuilder().
+		SetCommand(awscb.UpdateAssumeRolePolicy).
+		AddParam(awscb.RoleName, roleName).
+		AddParam(awscb.PolicyDocument, fmt.Sprintf("'%s'", compacted.String())).
+		Build()
+	lines := make([]string, len(mismatches))
+	for i, mismatch := range mismatches {
+		lines[i] = "  - " + mismatch
+	}
+	r.Reporter.Warnf("The trust policy of role '%s' doesn't allow operator '%s/%s' to assume it:\n%s\n"+
+		"To fix it, run the following command:\n\n%s\n", roleName, operator.Namespace(),
+		operator.Name(), strings.Join(lines, "\n"), command)
+	return true, nil
+}
@@ -0,0 +1,77 @@
+package roles
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var _ = Describe("Verify", func() {
+	const (
+		coreARN        = "arn:aws:iam::aws:policy/service-role/ROSAInstallerCorePolicy"
+		vpcARN         = "arn:aws:iam::aws:policy/service-role/ROSAInstallerVPCPolicy"
+		privateLinkARN = "arn:aws:iam::aws:policy/service-role/ROSAInstallerPrivateLinkPolicy"
+		hcpARN         = "arn:aws:iam::aws:policy/service-role/ROSAInstallerPolicy"
+		adminARN       = "arn:aws:iam::aws:policy/AdministratorAccess"
+	)
+
+	var r *rosa.Runtime
+	var policies map[string]*cmv1.AWSSTSPolicy
+
+	BeforeEach(func() {
+		r = rosa.NewRuntime()
+		policies = map[string]*cmv1.AWSSTSPolicy{}
+		for key, arn := range map[string]string{
+			"sts_installer_core_permission_policy":        coreARN,
+			"sts_installer_vpc_permission_policy":         vpcARN,
+			"sts_installer_privatelink_permission_policy": privateLinkARN,
+			"sts_hcp_installer_permission_policy":         hcpARN,
+		} {
+			policy, err := cmv1.NewAWSSTSPolicy().ARN(arn).Build()
+			Expect(err).ToNot(HaveOccurred())
+			policies[key] = policy
+		}
+	})
+
+	keys := [][]string{
+		{
+			"sts_installer_core_permission_policy",
+			"sts_installer_vpc_permission_policy",
+			"sts_installer_privatelink_permission_policy",
+		},
+		{"sts_hcp_installer_permission_policy"},
+	}
+
+	It("Accepts the managed policies that ROSA attaches", func() {
+		Expect(verifyManagedPolicies(r, "Installer", []string{coreARN, privateLinkARN, vpcARN}, policies,
+			keys)).To(BeFalse())
+		Expect(verifyManagedPolicies(r, "Installer", []string{hcpARN}, policies, keys)).To(BeFalse())
+	})
+
+	It("Reports other AWS managed policies", func() {
+		Expect(verifyManagedPolicies(r, "Installer", []string{adminARN}, policies, keys)).To(BeTrue())
+		Expect(verifyManagedPolicies(r, "Installer", []string{adminARN, hcpARN}, policies, keys)).To(BeTrue())
+	})
+
+	It("Reports the missing managed policies", func() {
+		Expect(verifyManagedPolicies(r, "Installer", []string{coreARN, vpcARN}, policies, keys)).To(BeTrue())
+	})
+
+	It("Splits and sorts the attached policies", func() {
+		managed, customer := splitPolicies(map[string]string{
+			vpcARN:                               "{}",
+			coreARN:                              "{}",
+			"arn:aws:iam::123456789012:policy/b": "{}",
+			"arn:aws:iam::123456789012:policy/a": "{}",
+		})
+		Expect(managed).To(Equal([]string{coreARN, vpcARN}))
+		Expect(customer).To(Equal([]string{
+			"arn:aws:iam::123456789012:policy/a",
+			"arn:aws:iam::123456789012:policy/b",
+		}))
+		Expect(reportUnexpectedPolicies(r, "Installer", customer, customer[0])).To(BeTrue())
+		Expect(reportUnexpectedPolicies(r, "Installer", customer[:1], customer[0])).To(BeFalse())
+	})
+})
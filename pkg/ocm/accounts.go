@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/properties"
+)
+
+// LinkedAWSAccount contains the roles that link an AWS account to the OCM organization and to the
+// current OCM user.
+type LinkedAWSAccount struct {
+	ID        string
+	OCMRoles  []string
+	UserRoles []string
+}
+
+// GetLinkedAWSAccounts returns the AWS accounts that have OCM roles linked to the organization of
+// the current user, or user roles linked to the current user, indexed by AWS account ID.
+func (c *Client) GetLinkedAWSAccounts() (map[string]*LinkedAWSAccount, error) {
+	account, err := c.GetCurrentAccount()
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("Failed to get current OCM account")
+	}
+	ocmRoles, err := c.GetOrganizationLinkedOCMRoles(account.Organization().ID())
+	if err != nil {
+		return nil, err
+	}
+	userRoles, err := c.GetAccountLinkedUserRoles(account.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := map[string]*LinkedAWSAccount{}
+	linked := func(roleARN string) *LinkedAWSAccount {
+		accountID := GetAWSAccountID(roleARN)
+		if accountID == "" {
+			return nil
+		}
+		if _, ok := accounts[accountID]; !ok {
+			accounts[accountID] = &LinkedAWSAccount{ID: accountID}
+		}
+		return accounts[accountID]
+	}
+	for _, roleARN := range ocmRoles {
+		if account := linked(roleARN); account != nil {
+			account.OCMRoles = append(account.OCMRoles, strings.TrimSpace(roleARN))
+		}
+	}
+	for _, roleARN := range userRoles {
+		if account := linked(roleARN); account != nil {
+			account.UserRoles = append(account.UserRoles, strings.TrimSpace(roleARN))
+		}
+	}
+	return accounts, nil
+}
+
+// GetAWSAccountID returns the AWS account ID of the given ARN, or an empty string if it isn't a
+// valid ARN.
+func GetAWSAccountID(value string) string {
+	parsed, err := arn.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return ""
+	}
+	return parsed.AccountID
+}
+
+// GetClusterAWSAccountID returns the ID of the AWS account where the cluster runs, calculated from
+// the installer role of STS clusters or from the ARN of the creator of other clusters.
+func GetClusterAWSAccountID(cluster *cmv1.Cluster) string {
+	if accountID := GetAWSAccountID(cluster.AWS().STS().RoleARN()); accountID != "" {
+		return accountID
+	}
+	return GetAWSAccountID(cluster.Properties()[properties.CreatorARN])
+}
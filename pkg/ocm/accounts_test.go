@@ -0,0 +1,33 @@
+package ocm
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/properties"
+)
+
+var _ = Describe("Accounts", func() {
+	It("Calculates the AWS account of STS clusters from the installer role", func() {
+		cluster, err := cmv1.NewCluster().
+			AWS(cmv1.NewAWS().STS(cmv1.NewSTS().RoleARN("arn:aws:iam::111111111111:role/Installer"))).
+			Properties(map[string]string{properties.CreatorARN: "arn:aws:iam::222222222222:user/me"}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(GetClusterAWSAccountID(cluster)).To(Equal("111111111111"))
+	})
+
+	It("Calculates the AWS account of other clusters from the creator", func() {
+		cluster, err := cmv1.NewCluster().
+			Properties(map[string]string{properties.CreatorARN: "arn:aws:iam::222222222222:user/me"}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(GetClusterAWSAccountID(cluster)).To(Equal("222222222222"))
+	})
+
+	It("Ignores values that aren't ARNs", func() {
+		Expect(GetAWSAccountID("")).To(BeEmpty())
+		Expect(GetAWSAccountID("not-an-arn")).To(BeEmpty())
+	})
+})
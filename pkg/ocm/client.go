@@ -25,8 +25,10 @@ import (
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/rosa/pkg/cassette"
 	"github.com/openshift/rosa/pkg/config"
 	"github.com/openshift/rosa/pkg/fedramp"
+	"github.com/openshift/rosa/pkg/har"
 	"github.com/openshift/rosa/pkg/info"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/reporter"
@@ -48,12 +50,12 @@ func NewClient() *ClientBuilder {
 	return &ClientBuilder{}
 }
 
-func CreateNewClientOrExit(logger *logrus.Logger, reporter *reporter.Object) *Client {
+func CreateNewClientOrExit(logger *logrus.Logger, r *reporter.Object) *Client {
 	client, err := NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
+		r.WithCode(reporter.CodeOCMConnection).Errorf("Failed to create OCM connection: %v", err)
 		os.Exit(1)
 	}
 
@@ -82,6 +84,13 @@ func (b *ClientBuilder) Build() (result *Client, err error) {
 			err = fmt.Errorf("Failed to load config file: %v", err)
 			return nil, err
 		}
+		// The replayed requests aren't sent to the network, so they don't need a real token and
+		// the cassettes can be replayed without logging in:
+		if b.cfg == nil && cassette.Mode() == cassette.ModeReplay {
+			b.cfg = &config.Config{
+				AccessToken: cassette.PlaceholderToken("Bearer"),
+			}
+		}
 		if b.cfg == nil {
 			err = fmt.Errorf("Not logged in, run the 'rosa login' command")
 			return nil, err
@@ -135,6 +144,14 @@ func (b *ClientBuilder) Build() (result *Client, err error) {
 		builder.Tokens(tokens...)
 	}
 	builder.Insecure(b.cfg.Insecure)
+	if har.Enabled() {
+		builder.TransportWrapper(har.Transport)
+	}
+	// The transport wrappers are called in the order they are added, so the cassette is added
+	// last in order to record the requests as they are sent to the network:
+	if cassette.Enabled() {
+		builder.TransportWrapper(cassette.Transport)
+	}
 
 	// Create the connection:
 	conn, err := builder.Build()
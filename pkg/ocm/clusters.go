@@ -21,6 +21,8 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
@@ -110,8 +112,9 @@ type Spec struct {
 	AdditionalTrustBundle     *string
 
 	// HyperShift options:
-	Hypershift     Hypershift
-	BillingAccount string
+	Hypershift      Hypershift
+	BillingAccount  string
+	AuditLogRoleARN string
 }
 
 type OperatorIAMRole struct {
@@ -185,30 +188,47 @@ func (c *Client) CreateCluster(config Spec) (*cmv1.Cluster, error) {
 	return clusterObject, nil
 }
 
-// Pass 0 to get all clusters
+// clustersPageSize is the number of clusters requested in each page when listing clusters.
+const clustersPageSize = 100
+
+// GetClusters returns up to count clusters created by the given AWS account. A count of zero means
+// all of them.
 func (c *Client) GetClusters(creator *aws.Creator, count int) (clusters []*cmv1.Cluster, err error) {
+	return c.GetFilteredClusters(creator, ClusterFilter{}, count)
+}
+
+// GetFilteredClusters returns up to count clusters created by the given AWS account that match the
+// filter. A count of zero means all of them. Pages are requested until the server doesn't return more
+// clusters.
+func (c *Client) GetFilteredClusters(creator *aws.Creator, filter ClusterFilter,
+	count int) (clusters []*cmv1.Cluster, err error) {
 	if count < 0 {
 		err = errors.Errorf("Invalid Cluster count")
 		return
 	}
-	query := getClusterFilter(creator)
-	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query)
+	query, err := filter.search(creator)
+	if err != nil {
+		return
+	}
+	pageSize := clustersPageSize
+	if count > 0 && count < pageSize {
+		pageSize = count
+	}
+	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query).Size(pageSize)
 	page := 1
 	for {
-		clusterRequestList := request.Page(page)
-		if count > 0 {
-			clusterRequestList = clusterRequestList.Size(count)
-		}
-		response, err := clusterRequestList.Send()
+		response, err := request.Page(page).Send()
 		if err != nil {
-			return clusters, err
+			return clusters, handleErr(response.Error(), err)
 		}
-
 		response.Items().Each(func(cluster *cmv1.Cluster) bool {
 			clusters = append(clusters, cluster)
-			return true
+			return count == 0 || len(clusters) < count
 		})
-		if response.Size() != count {
+		if count > 0 && len(clusters) >= count {
+			break
+		}
+		if response.Size() < pageSize || page*pageSize >= response.Total() {
 			break
 		}
 		page++
@@ -216,6 +236,93 @@ func (c *Client) GetClusters(creator *aws.Creator, count int) (clusters []*cmv1.
 	return clusters, nil
 }
 
+// ClusterFilter contains the conditions that restrict the clusters returned by GetFilteredClusters.
+// They are added to the search query sent to OCM, so that the filtering happens in the server.
+type ClusterFilter struct {
+	States     []string
+	Regions    []string
+	Versions   []string
+	Topologies []string
+
+	// Search is an additional raw OCM search query, for example "multi_az = 'true'".
+	Search string
+
+	// AllAccounts selects the clusters of all the AWS accounts of the organization instead of only
+	// the ones of the AWS account of the creator.
+	AllAccounts bool
+}
+
+// Cluster topologies, as shown by 'rosa list clusters':
+const (
+	TopologyClassic    = "classic"
+	TopologyClassicSTS = "classic-sts"
+	TopologyHostedCP   = "hosted-cp"
+)
+
+var Topologies = []string{TopologyClassic, TopologyClassicSTS, TopologyHostedCP}
+
+var topologyQueries = map[string]string{
+	TopologyClassic:    "hypershift.enabled = 'false' AND aws.sts.enabled = 'false'",
+	TopologyClassicSTS: "hypershift.enabled = 'false' AND aws.sts.enabled = 'true'",
+	TopologyHostedCP:   "hypershift.enabled = 'true'",
+}
+
+var filterValueRE = regexp.MustCompile(`^[\w.-]+$`)
+
+func (f ClusterFilter) search(creator *aws.Creator) (string, error) {
+	clauses := []string{"product.id = 'rosa'"}
+	if !f.AllAccounts {
+		clauses = []string{getClusterFilter(creator)}
+	}
+	for _, values := range [][]string{f.States, f.Regions, f.Versions} {
+		for _, value := range values {
+			if !filterValueRE.MatchString(value) {
+				return "", errors.Errorf("Invalid filter value '%s': it must contain only letters, "+
+					"digits, dots, dashes and underscores", value)
+			}
+		}
+	}
+	if len(f.States) > 0 {
+		clauses = append(clauses, fmt.Sprintf("state IN (%s)", quoteList(f.States)))
+	}
+	if len(f.Regions) > 0 {
+		clauses = append(clauses, fmt.Sprintf("region.id IN (%s)", quoteList(f.Regions)))
+	}
+	if len(f.Versions) > 0 {
+		// A version matches itself and all the versions it is a prefix of, so that '4.12' matches
+		// '4.12.5' but not '4.1':
+		versions := []string{}
+		for _, version := range f.Versions {
+			versions = append(versions, fmt.Sprintf("version.raw_id = '%s' OR version.raw_id LIKE '%s.%%'",
+				version, version))
+		}
+		clauses = append(clauses, fmt.Sprintf("(%s)", strings.Join(versions, " OR ")))
+	}
+	if len(f.Topologies) > 0 {
+		topologies := []string{}
+		for _, topology := range f.Topologies {
+			query, ok := topologyQueries[topology]
+			if !ok {
+				return "", errors.Errorf("Invalid topology '%s', allowed values are %s", topology, Topologies)
+			}
+			topologies = append(topologies, fmt.Sprintf("(%s)", query))
+		}
+		clauses = append(clauses, fmt.Sprintf("(%s)", strings.Join(topologies, " OR ")))
+	}
+	if f.Search != "" {
+		clauses = append(clauses, fmt.Sprintf("(%s)", f.Search))
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("'%s'", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
 func (c *Client) GetAllClusters(creator *aws.Creator) (clusters []*cmv1.Cluster, err error) {
 	query := getClusterFilter(creator)
 	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query)
@@ -449,8 +556,11 @@ func (c *Client) GetClusterState(clusterID string) (cmv1.ClusterState, error) {
 		Status().
 		Get().
 		Send()
-	if err != nil || response.Body() == nil {
-		return cmv1.ClusterState(""), err
+	if err != nil {
+		return cmv1.ClusterState(""), handleErr(response.Error(), err)
+	}
+	if response.Body() == nil {
+		return cmv1.ClusterState(""), nil
 	}
 	return response.Body().State(), nil
 }
@@ -777,6 +887,10 @@ func (c *Client) createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Clu
 		awsBuilder = awsBuilder.BillingAccountID(config.BillingAccount)
 	}
 
+	if config.AuditLogRoleARN != "" {
+		awsBuilder = awsBuilder.AuditLog(cmv1.NewAuditLog().RoleArn(config.AuditLogRoleARN))
+	}
+
 	if config.RoleARN != "" {
 		stsBuilder := cmv1.NewSTS().RoleARN(config.RoleARN)
 		if config.ExternalID != "" {
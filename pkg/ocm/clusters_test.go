@@ -0,0 +1,125 @@
+package ocm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	. "github.com/openshift-online/ocm-sdk-go/testing"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("Clusters", func() {
+	creator := &aws.Creator{AccountID: "123456789012"}
+
+	Context("ClusterFilter", func() {
+		It("Adds the filters to the search query", func() {
+			query, err := ClusterFilter{
+				States:     []string{"ready", "error"},
+				Regions:    []string{"us-east-1"},
+				Versions:   []string{"4.12"},
+				Topologies: []string{TopologyHostedCP},
+				Search:     "multi_az = 'true'",
+			}.search(creator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query).To(HavePrefix(getClusterFilter(creator) + " AND "))
+			Expect(query).To(ContainSubstring("state IN ('ready', 'error')"))
+			Expect(query).To(ContainSubstring("region.id IN ('us-east-1')"))
+			Expect(query).To(ContainSubstring("(version.raw_id = '4.12' OR version.raw_id LIKE '4.12.%')"))
+			Expect(query).To(ContainSubstring("((hypershift.enabled = 'true'))"))
+			Expect(query).To(HaveSuffix("AND (multi_az = 'true')"))
+		})
+
+		It("Doesn't restrict the AWS account when listing all accounts", func() {
+			query, err := ClusterFilter{AllAccounts: true, States: []string{"ready"}}.search(creator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query).To(Equal("product.id = 'rosa' AND state IN ('ready')"))
+		})
+
+		It("Rejects values that could change the query", func() {
+			_, err := ClusterFilter{States: []string{"ready' OR 1 = '1"}}.search(creator)
+			Expect(err).To(HaveOccurred())
+			_, err = ClusterFilter{Topologies: []string{"managed"}}.search(creator)
+			Expect(err).To(MatchError(ContainSubstring("Invalid topology 'managed'")))
+		})
+	})
+
+	Context("GetFilteredClusters", func() {
+		var ssoServer, apiServer *ghttp.Server
+		var ocmClient *Client
+
+		BeforeEach(func() {
+			ssoServer = MakeTCPServer()
+			apiServer = MakeTCPServer()
+			apiServer.SetAllowUnhandledRequests(true)
+			apiServer.SetUnhandledRequestStatusCode(http.StatusInternalServerError)
+			accessToken := MakeTokenString("Bearer", 15*time.Minute)
+			ssoServer.AppendHandlers(
+				RespondWithAccessToken(accessToken),
+			)
+			logger, err := logging.NewGoLoggerBuilder().
+				Debug(true).
+				Build()
+			Expect(err).To(BeNil())
+			connection, err := sdk.NewConnectionBuilder().
+				Logger(logger).
+				Tokens(accessToken).
+				URL(apiServer.URL()).
+				Build()
+			Expect(err).To(BeNil())
+			ocmClient = &Client{ocm: connection}
+		})
+
+		AfterEach(func() {
+			ssoServer.Close()
+			apiServer.Close()
+			Expect(ocmClient.Close()).To(Succeed())
+		})
+
+		clusterList := func(page, size, total int) string {
+			items := []string{}
+			for i := 0; i < size; i++ {
+				items = append(items, fmt.Sprintf(`{"kind": "Cluster", "id": "cluster-%d-%d"}`, page, i))
+			}
+			return fmt.Sprintf(`{"kind": "ClusterList", "page": %d, "size": %d, "total": %d, "items": [%s]}`,
+				page, size, total, strings.Join(items, ","))
+		}
+
+		It("Requests all the pages", func() {
+			apiServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("page", "1"),
+					ghttp.VerifyFormKV("size", "100"),
+					RespondWithJSON(http.StatusOK, clusterList(1, 100, 101)),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("page", "2"),
+					RespondWithJSON(http.StatusOK, clusterList(2, 1, 101)),
+				),
+			)
+			clusters, err := ocmClient.GetFilteredClusters(creator, ClusterFilter{}, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusters).To(HaveLen(101))
+			Expect(clusters[100].ID()).To(Equal("cluster-2-0"))
+		})
+
+		It("Stops at the requested count", func() {
+			apiServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("size", "10"),
+					RespondWithJSON(http.StatusOK, clusterList(1, 10, 50)),
+				),
+			)
+			clusters, err := ocmClient.GetClusters(creator, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusters).To(HaveLen(10))
+		})
+	})
+})
@@ -17,7 +17,6 @@ limitations under the License.
 package ocm
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -26,7 +25,9 @@ import (
 	errors "github.com/zgalor/weberr"
 )
 
-const interval = 15 * time.Second
+// interval is the time between the requests that poll the logs. It is a variable so that tests can
+// make it shorter.
+var interval = 15 * time.Second
 
 func (c *Client) GetInstallLogs(clusterID string, tail int) (logs *cmv1.Log, err error) {
 	logsClient := c.ocm.ClustersMgmt().V1().Clusters().
@@ -66,55 +67,76 @@ func (c *Client) GetUninstallLogs(clusterID string, tail int) (logs *cmv1.Log, e
 	return response.Body(), nil
 }
 
-func (c *Client) PollInstallLogs(clusterID string, cb func(*cmv1.LogGetResponse) bool) (logs *cmv1.Log, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
-	defer func() {
-		cancel()
-	}()
+// GetInstallLogsFrom returns the lines of the install logs of the cluster that follow the first offset
+// lines. An offset of zero returns the complete log.
+func (c *Client) GetInstallLogsFrom(clusterID string, offset int) (*cmv1.Log, error) {
+	return getLogsFrom(c.ocm.ClustersMgmt().V1().Clusters().Cluster(clusterID).Logs().Install(),
+		clusterID, offset)
+}
 
-	logsClient := c.ocm.ClustersMgmt().V1().Clusters().
-		Cluster(clusterID).
-		Logs().
-		Install()
-	response, err := logsClient.Poll().
-		Parameter("tail", 100).
-		Interval(interval).
-		Predicate(cb).
-		StartContext(ctx)
+// GetUninstallLogsFrom returns the lines of the uninstall logs of the cluster that follow the first
+// offset lines. An offset of zero returns the complete log.
+func (c *Client) GetUninstallLogsFrom(clusterID string, offset int) (*cmv1.Log, error) {
+	return getLogsFrom(c.ocm.ClustersMgmt().V1().Clusters().Cluster(clusterID).Logs().Uninstall(),
+		clusterID, offset)
+}
+
+func getLogsFrom(logsClient *cmv1.LogClient, clusterID string, offset int) (*cmv1.Log, error) {
+	request := logsClient.Get()
+	if offset > 0 {
+		request = request.Offset(offset)
+	}
+	response, err := request.Send()
 	if err != nil {
-		err = fmt.Errorf("Failed to poll logs for cluster '%s': %v", clusterID, err)
+		err = handleErr(response.Error(), err)
 		if response.Status() == http.StatusNotFound {
-			err = errors.NotFound.UserErrorf("Failed to poll logs for cluster '%s'", clusterID)
+			err = errors.NotFound.UserErrorf("Failed to get logs for cluster '%s'", clusterID)
 		}
-		return
+		return nil, err
 	}
-
 	return response.Body(), nil
 }
 
-func (c *Client) PollUninstallLogs(clusterID string,
-	cb func(*cmv1.LogGetResponse) bool) (logs *cmv1.Log, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
-	defer func() {
-		cancel()
-	}()
+// PollInstallLogs gets the install logs of the cluster periodically, until the callback returns true
+// or the timeout expires. Each request only returns the lines that follow the offset returned by the
+// offset function, so that no line is received twice or lost between requests. When the logs aren't
+// found the callback receives the NotFound error instead of the logs.
+func (c *Client) PollInstallLogs(clusterID string, offset func() int, timeout time.Duration,
+	cb func(*cmv1.Log, error) bool) error {
+	return pollLogs(func(offset int) (*cmv1.Log, error) {
+		return c.GetInstallLogsFrom(clusterID, offset)
+	}, clusterID, offset, timeout, cb)
+}
+
+// PollUninstallLogs gets the uninstall logs of the cluster periodically, until the callback returns
+// true or the timeout expires. Each request only returns the lines that follow the offset returned by
+// the offset function, so that no line is received twice or lost between requests. When the logs
+// aren't found, which also happens once the cluster has been deleted, the callback receives the
+// NotFound error instead of the logs.
+func (c *Client) PollUninstallLogs(clusterID string, offset func() int, timeout time.Duration,
+	cb func(*cmv1.Log, error) bool) error {
+	return pollLogs(func(offset int) (*cmv1.Log, error) {
+		return c.GetUninstallLogsFrom(clusterID, offset)
+	}, clusterID, offset, timeout, cb)
+}
 
-	logsClient := c.ocm.ClustersMgmt().V1().Clusters().
-		Cluster(clusterID).
-		Logs().
-		Uninstall()
-	response, err := logsClient.Poll().
-		Parameter("tail", 100).
-		Interval(interval).
-		Predicate(cb).
-		StartContext(ctx)
-	if err != nil {
-		err = fmt.Errorf("Failed to poll logs for cluster '%s': %v", clusterID, err)
-		if response.Status() == http.StatusNotFound {
-			err = errors.NotFound.UserErrorf("Failed to poll logs for cluster '%s'", clusterID)
+// pollLogs can't use the poll requests of the SDK, as they send the same parameters in every request.
+// Logs that aren't found are passed to the callback as the NotFound error, as only the caller knows
+// whether that means that they aren't available yet or that the cluster is gone.
+func pollLogs(get func(offset int) (*cmv1.Log, error), clusterID string, offset func() int,
+	timeout time.Duration, cb func(*cmv1.Log, error) bool) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		logs, err := get(offset())
+		if err != nil && errors.GetType(err) != errors.NotFound {
+			return fmt.Errorf("Failed to poll logs for cluster '%s': %v", clusterID, err)
 		}
-		return
+		if cb(logs, err) {
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("Failed to poll logs for cluster '%s': timed out after %s", clusterID, timeout)
+		}
+		time.Sleep(interval)
 	}
-
-	return response.Body(), nil
 }
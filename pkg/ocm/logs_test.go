@@ -0,0 +1,97 @@
+package ocm
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	. "github.com/openshift-online/ocm-sdk-go/testing"
+	errors "github.com/zgalor/weberr"
+)
+
+var _ = Describe("Logs", func() {
+	var apiServer *ghttp.Server
+	var ocmClient *Client
+	var savedInterval time.Duration
+
+	BeforeEach(func() {
+		savedInterval = interval
+		interval = time.Millisecond
+		apiServer = MakeTCPServer()
+		logger, err := logging.NewGoLoggerBuilder().
+			Debug(true).
+			Build()
+		Expect(err).To(BeNil())
+		connection, err := sdk.NewConnectionBuilder().
+			Logger(logger).
+			Tokens(MakeTokenString("Bearer", 15*time.Minute)).
+			URL(apiServer.URL()).
+			Build()
+		Expect(err).To(BeNil())
+		ocmClient = &Client{ocm: connection}
+	})
+
+	AfterEach(func() {
+		interval = savedInterval
+		apiServer.Close()
+		Expect(ocmClient.Close()).To(Succeed())
+	})
+
+	const logsPath = "/api/clusters_mgmt/v1/clusters/123/logs/uninstall"
+
+	It("Passes the logs that aren't found to the callback", func() {
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, logsPath),
+				RespondWithJSON(http.StatusOK, `{"kind": "Log", "content": "Deleting cluster"}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, logsPath),
+				RespondWithJSON(http.StatusNotFound, `{"kind": "Error", "reason": "Not found"}`),
+			),
+		)
+		contents := []string{}
+		err := ocmClient.PollUninstallLogs("123", func() int { return 0 }, time.Minute,
+			func(log *cmv1.Log, err error) bool {
+				// The uninstall logs aren't found once the cluster is deleted:
+				if err != nil {
+					Expect(errors.GetType(err)).To(Equal(errors.NotFound))
+					return true
+				}
+				contents = append(contents, log.Content())
+				return false
+			})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(Equal([]string{"Deleting cluster"}))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Keeps polling while the callback ignores the logs that aren't found", func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusNotFound, `{"kind": "Error", "reason": "Not found"}`),
+			RespondWithJSON(http.StatusOK, `{"kind": "Log", "content": "Installing cluster"}`),
+		)
+		err := ocmClient.PollInstallLogs("123", func() int { return 0 }, time.Minute,
+			func(log *cmv1.Log, err error) bool {
+				return err == nil
+			})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Fails on other errors", func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusForbidden, `{"kind": "Error", "reason": "Forbidden"}`),
+		)
+		err := ocmClient.PollUninstallLogs("123", func() int { return 0 }, time.Minute,
+			func(log *cmv1.Log, err error) bool {
+				return true
+			})
+		Expect(err).To(MatchError(ContainSubstring("Failed to poll logs for cluster '123'")))
+	})
+})
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that calculate and execute the deletion of orphaned resources.
+
+package orphans
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+)
+
+const (
+	// S3Bucket and PrivateKeySecret are the kinds of the resources of unmanaged OIDC
+	// configurations, which are deleted together with their OIDC providers.
+	S3Bucket         Kind = "s3-bucket"
+	PrivateKeySecret Kind = "secret"
+)
+
+// Deletion is the deletion of a single AWS resource.
+type Deletion struct {
+	Kind Kind
+
+	// Name is the name of the role, bucket or secret, or the ARN of the policy or OIDC provider.
+	Name string
+
+	// Region is the region of buckets and secrets, empty for global IAM resources.
+	Region string
+
+	// Resource is the orphaned resource that caused the deletion.
+	Resource *Resource
+}
+
+func (d *Deletion) String() string {
+	return fmt.Sprintf("%s '%s'", d.Kind, d.Name)
+}
+
+// Deleter is the subset of the AWS client used to delete the resources.
+type Deleter interface {
+	DeleteOperatorRole(roleName string, managedPolicies bool) error
+	DeletePolicy(policyARN string) error
+	DeleteOpenIDConnectProvider(providerARN string) error
+	DeleteS3Bucket(bucketName string) error
+	DeleteSecretInSecretsManager(secretID string) error
+}
+
+// deletionOrder is the order in which the kinds of resources are deleted, so that no resource is
+// deleted while another one still depends on it: roles before the policies attached to them, and
+// OIDC providers before the bucket that hosts their documents and the secret with their key.
+var deletionOrder = []Kind{
+	OperatorRole,
+	OperatorPolicy,
+	OIDCProvider,
+	S3Bucket,
+	PrivateKeySecret,
+}
+
+// Plan returns the deletions needed to remove the given orphaned resources, in dependency order.
+// When olderThan isn't zero resources created more recently than that, or with an unknown
+// creation date, are kept.
+func Plan(resources []*Resource, olderThan time.Duration, now time.Time) []*Deletion {
+	byKind := map[Kind][]*Deletion{}
+	for _, resource := range resources {
+		if olderThan > 0 && (resource.CreatedAt.IsZero() || now.Sub(resource.CreatedAt) < olderThan) {
+			continue
+		}
+		switch resource.Kind {
+		case OperatorRole:
+			byKind[OperatorRole] = append(byKind[OperatorRole], &Deletion{
+				Kind:     OperatorRole,
+				Name:     resource.Name,
+				Resource: resource,
+			})
+		case OperatorPolicy, OIDCProvider:
+			byKind[resource.Kind] = append(byKind[resource.Kind], &Deletion{
+				Kind:     resource.Kind,
+				Name:     resource.ARN,
+				Resource: resource,
+			})
+			if resource.Bucket == "" {
+				continue
+			}
+			byKind[S3Bucket] = append(byKind[S3Bucket], &Deletion{
+				Kind:     S3Bucket,
+				Name:     resource.Bucket,
+				Region:   resource.Region,
+				Resource: resource,
+			})
+			byKind[PrivateKeySecret] = append(byKind[PrivateKeySecret], &Deletion{
+				Kind:     PrivateKeySecret,
+				Name:     oidc_config.PrivateKeySecretName(resource.Bucket),
+				Region:   resource.Region,
+				Resource: resource,
+			})
+		}
+	}
+	var result []*Deletion
+	for _, kind := range deletionOrder {
+		result = append(result, byKind[kind]...)
+	}
+	return result
+}
+
+// Delete executes the given deletion. Buckets and secrets that don't exist anymore aren't
+// considered an error.
+func Delete(client Deleter, deletion *Deletion) error {
+	switch deletion.Kind {
+	case OperatorRole:
+		return client.DeleteOperatorRole(deletion.Name, deletion.Resource.ManagedPolicies)
+	case OperatorPolicy:
+		return client.DeletePolicy(deletion.Name)
+	case OIDCProvider:
+		return client.DeleteOpenIDConnectProvider(deletion.Name)
+	case S3Bucket:
+		return client.DeleteS3Bucket(deletion.Name)
+	case PrivateKeySecret:
+		return client.DeleteSecretInSecretsManager(deletion.Name)
+	default:
+		return fmt.Errorf("Unknown kind of resource '%s'", deletion.Kind)
+	}
+}
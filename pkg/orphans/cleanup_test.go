@@ -0,0 +1,85 @@
+package orphans
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeDeleter struct {
+	deleted []string
+}
+
+func (f *fakeDeleter) DeleteOperatorRole(roleName string, managedPolicies bool) error {
+	f.deleted = append(f.deleted, "role:"+roleName)
+	return nil
+}
+
+func (f *fakeDeleter) DeletePolicy(policyARN string) error {
+	f.deleted = append(f.deleted, "policy:"+policyARN)
+	return nil
+}
+
+func (f *fakeDeleter) DeleteOpenIDConnectProvider(providerARN string) error {
+	f.deleted = append(f.deleted, "provider:"+providerARN)
+	return nil
+}
+
+func (f *fakeDeleter) DeleteS3Bucket(bucketName string) error {
+	f.deleted = append(f.deleted, "bucket:"+bucketName)
+	return nil
+}
+
+func (f *fakeDeleter) DeleteSecretInSecretsManager(secretID string) error {
+	f.deleted = append(f.deleted, "secret:"+secretID)
+	return nil
+}
+
+var _ = Describe("Plan", func() {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+
+	resources := []*Resource{
+		{Kind: OIDCProvider, Name: "gone-oidc-a1b2.s3.us-east-1.amazonaws.com", ARN: "provider-arn",
+			Bucket: "gone-oidc-a1b2", Region: "us-east-1", CreatedAt: old},
+		{Kind: OperatorPolicy, Name: "policy", ARN: "policy-arn", CreatedAt: old},
+		{Kind: OperatorRole, Name: "gone-openshift-ingress-operator-cloud-credentials", ARN: "role-arn",
+			CreatedAt: old},
+		{Kind: OperatorRole, Name: "new-openshift-ingress-operator-cloud-credentials", CreatedAt: recent},
+		{Kind: OperatorRole, Name: "unknown-openshift-ingress-operator-cloud-credentials"},
+	}
+
+	It("Deletes the resources in dependency order", func() {
+		deleter := &fakeDeleter{}
+		for _, deletion := range Plan(resources, 0, now) {
+			Expect(Delete(deleter, deletion)).To(Succeed())
+		}
+		Expect(deleter.deleted).To(Equal([]string{
+			"role:gone-openshift-ingress-operator-cloud-credentials",
+			"role:new-openshift-ingress-operator-cloud-credentials",
+			"role:unknown-openshift-ingress-operator-cloud-credentials",
+			"policy:policy-arn",
+			"provider:provider-arn",
+			"bucket:gone-oidc-a1b2",
+			"secret:rosa-private-key-gone-oidc-a1b2",
+		}))
+	})
+
+	It("Keeps the resources that are newer than the threshold or have an unknown age", func() {
+		deletions := Plan(resources, 30*24*time.Hour, now)
+		var names []string
+		for _, deletion := range deletions {
+			names = append(names, deletion.String())
+		}
+		Expect(names).To(Equal([]string{
+			"operator-role 'gone-openshift-ingress-operator-cloud-credentials'",
+			"operator-policy 'policy-arn'",
+			"oidc-provider 'provider-arn'",
+			"s3-bucket 'gone-oidc-a1b2'",
+			"secret 'rosa-private-key-gone-oidc-a1b2'",
+		}))
+		Expect(deletions[3].Region).To(Equal("us-east-1"))
+	})
+})
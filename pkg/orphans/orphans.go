@@ -0,0 +1,317 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orphans finds the operator roles, operator policies and OIDC providers that ROSA created
+// in an AWS account and that no longer belong to a live cluster.
+package orphans
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+)
+
+// Kind is the type of an orphaned resource.
+type Kind string
+
+const (
+	OperatorRole   Kind = "operator-role"
+	OperatorPolicy Kind = "operator-policy"
+	OIDCProvider   Kind = "oidc-provider"
+)
+
+// Resource is an AWS resource that was created for a cluster that no longer exists.
+type Resource struct {
+	Kind      Kind      `json:"kind"`
+	Name      string    `json:"name"`
+	ARN       string    `json:"arn"`
+	ClusterID string    `json:"cluster_id,omitempty"`
+	Prefix    string    `json:"prefix,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Reason    string    `json:"reason"`
+
+	// ManagedPolicies indicates that an operator role uses policies managed by AWS, which must
+	// not be deleted with the role.
+	ManagedPolicies bool `json:"managed_policies,omitempty"`
+
+	// Bucket is the S3 bucket that hosts the discovery documents of an OIDC provider created for
+	// an unmanaged OIDC configuration, and Region is the region of that bucket. They are empty for
+	// other resources.
+	Bucket string `json:"bucket,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+// AWSClient is the subset of the AWS client used to list the resources.
+type AWSClient interface {
+	ListOperatorRoleResources() ([]aws.OperatorRoleResource, error)
+	ListOperatorPolicyResources() ([]aws.OperatorPolicyResource, error)
+	ListOpenIDConnectProviderResources() ([]aws.OIDCProviderResource, error)
+	ListAccountRoles(version string) ([]aws.Role, error)
+}
+
+// OCMClient is the subset of the OCM client used to check if the resources are in use.
+type OCMClient interface {
+	HasAClusterUsingOperatorRolesPrefix(prefix string) (bool, error)
+	HasAClusterUsingOidcEndpointUrl(issuerURL string) (bool, error)
+	ListOidcConfigs(awsAccountID string) ([]*cmv1.OidcConfig, error)
+}
+
+// operatorRolePrefixRE extracts the prefix from the name of an operator role. It is the same
+// expression used to group the operator roles in 'rosa list operator-roles'.
+var operatorRolePrefixRE = regexp.MustCompile(`(?i)(?P<Prefix>[\w+=,.@-]+)-(openshift|kube-system)`)
+
+// bucketHostRE matches the host of the issuer URL of an unmanaged OIDC configuration, which is
+// the S3 bucket that contains the discovery documents.
+var bucketHostRE = regexp.MustCompile(`^(?P<Bucket>[a-z0-9.-]+)\.s3\.(?P<Region>[a-z0-9-]+)\.amazonaws\.com$`)
+
+// Find returns the operator roles, operator policies and OIDC providers of the AWS account that
+// don't belong to any cluster, sorted by kind and name.
+func Find(awsClient AWSClient, ocmClient OCMClient, accountID string) ([]*Resource, error) {
+	finder := &finder{
+		aws:      awsClient,
+		ocm:      ocmClient,
+		prefixes: map[string]bool{},
+	}
+	var result []*Resource
+	roles, err := finder.findOperatorRoles()
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, roles...)
+	policies, err := finder.findOperatorPolicies()
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, policies...)
+	providers, err := finder.findOIDCProviders(accountID)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, providers...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return kindOrder(result[i].Kind) < kindOrder(result[j].Kind)
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// kindOrder returns the position of the kind in the order in which the resources can be deleted.
+func kindOrder(kind Kind) int {
+	switch kind {
+	case OperatorRole:
+		return 0
+	case OperatorPolicy:
+		return 1
+	default:
+		return 2
+	}
+}
+
+type finder struct {
+	aws AWSClient
+	ocm OCMClient
+
+	// prefixes caches whether there is a cluster using each operator role prefix, as all the
+	// roles of a cluster share the same one.
+	prefixes map[string]bool
+}
+
+func (f *finder) findOperatorRoles() ([]*Resource, error) {
+	roles, err := f.aws.ListOperatorRoleResources()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list operator roles: %v", err)
+	}
+	var result []*Resource
+	for _, role := range roles {
+		// Roles without the identifier of a cluster may have been created in advance with
+		// 'rosa create operator-roles --prefix' for a cluster that doesn't exist yet:
+		clusterID := role.Tags[tags.ClusterID]
+		prefix := OperatorRolePrefix(role.Name)
+		if clusterID == "" || prefix == "" {
+			continue
+		}
+		inUse, err := f.prefixInUse(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			continue
+		}
+		reason := fmt.Sprintf("Created for cluster '%s', and no cluster uses operator roles with prefix '%s'",
+			clusterID, prefix)
+		result = append(result, &Resource{
+			Kind:            OperatorRole,
+			Name:            role.Name,
+			ARN:             role.ARN,
+			ClusterID:       clusterID,
+			Prefix:          prefix,
+			CreatedAt:       role.CreatedAt,
+			Reason:          reason,
+			ManagedPolicies: role.Tags[tags.ManagedPolicies] == tags.True,
+		})
+	}
+	return result, nil
+}
+
+func (f *finder) prefixInUse(prefix string) (bool, error) {
+	if inUse, ok := f.prefixes[prefix]; ok {
+		return inUse, nil
+	}
+	inUse, err := f.ocm.HasAClusterUsingOperatorRolesPrefix(prefix)
+	if err != nil {
+		return false, fmt.Errorf("Failed to check if operator roles with prefix '%s' are in use: %v",
+			prefix, err)
+	}
+	f.prefixes[prefix] = inUse
+	return inUse, nil
+}
+
+func (f *finder) findOperatorPolicies() ([]*Resource, error) {
+	policies, err := f.aws.ListOperatorPolicyResources()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list operator policies: %v", err)
+	}
+	accountRolePrefixes, err := f.accountRolePrefixes()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Resource
+	for _, policy := range policies {
+		// Operator policies are shared by the operator roles of all the clusters created with the
+		// same account roles, so they are only orphaned once no role uses them.
+		if policy.AttachmentCount > 0 {
+			continue
+		}
+		// 'rosa create account-roles' creates the operator policies before any cluster creates
+		// operator roles, so they are only orphaned once the account roles that share their
+		// prefix are gone too. Without a prefix it isn't possible to know it.
+		prefix := policy.Tags[tags.RolePrefix]
+		if prefix == "" || accountRolePrefixes[prefix] {
+			continue
+		}
+		result = append(result, &Resource{
+			Kind:      OperatorPolicy,
+			Name:      policy.Name,
+			ARN:       policy.ARN,
+			ClusterID: policy.Tags[tags.ClusterID],
+			Prefix:    prefix,
+			CreatedAt: policy.CreatedAt,
+			Reason: fmt.Sprintf("Not attached to any role, and there are no account roles with prefix '%s'",
+				prefix),
+		})
+	}
+	return result, nil
+}
+
+// accountRolePrefixes returns the prefixes of the account roles of the account. The prefix is
+// taken from the tag of the role, or from its name if it has the standard one.
+func (f *finder) accountRolePrefixes() (map[string]bool, error) {
+	accountRoles, err := f.aws.ListAccountRoles("")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list account roles: %v", err)
+	}
+	prefixes := map[string]bool{}
+	for _, accountRole := range accountRoles {
+		if accountRole.RolePrefix != "" {
+			prefixes[accountRole.RolePrefix] = true
+			continue
+		}
+		for _, role := range aws.AccountRoles {
+			if standard, prefix := aws.IsStandardNamedAccountRole(accountRole.RoleName, role.Name); standard {
+				prefixes[prefix] = true
+				break
+			}
+		}
+	}
+	return prefixes, nil
+}
+
+func (f *finder) findOIDCProviders(accountID string) ([]*Resource, error) {
+	providers, err := f.aws.ListOpenIDConnectProviderResources()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list OIDC providers: %v", err)
+	}
+	oidcConfigs, err := f.ocm.ListOidcConfigs(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list OIDC configurations: %v", err)
+	}
+	issuers := map[string]bool{}
+	for _, oidcConfig := range oidcConfigs {
+		issuers[strings.TrimSuffix(oidcConfig.IssuerUrl(), "/")] = true
+	}
+	var result []*Resource
+	for _, provider := range providers {
+		// Only the providers created by ROSA are tagged as managed by Red Hat, the rest of them
+		// belong to other applications:
+		if provider.Tags[tags.RedHatManaged] != tags.True {
+			continue
+		}
+		issuerURL := "https://" + strings.TrimSuffix(provider.URL, "/")
+		if issuers[issuerURL] {
+			continue
+		}
+		inUse, err := f.ocm.HasAClusterUsingOidcEndpointUrl(issuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check if OIDC provider '%s' is in use: %v", provider.URL, err)
+		}
+		if inUse {
+			continue
+		}
+		bucket, region := OIDCBucket(provider.URL)
+		result = append(result, &Resource{
+			Kind:      OIDCProvider,
+			Name:      provider.URL,
+			ARN:       provider.ARN,
+			ClusterID: provider.Tags[tags.ClusterID],
+			CreatedAt: provider.CreatedAt,
+			Reason:    "No cluster or OIDC configuration uses the issuer URL",
+			Bucket:    bucket,
+			Region:    region,
+		})
+	}
+	return result, nil
+}
+
+// OperatorRolePrefix returns the prefix of the given operator role name, or an empty string if
+// the name doesn't look like the name of an operator role.
+func OperatorRolePrefix(roleName string) string {
+	matches := operatorRolePrefixRE.FindStringSubmatch(roleName)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[operatorRolePrefixRE.SubexpIndex("Prefix")]
+}
+
+// OIDCBucket returns the name and the region of the S3 bucket that hosts the discovery documents
+// of the given OIDC provider URL, or empty strings if the provider isn't hosted in a bucket of the
+// account. The issuer URLs of managed configurations have a path, and their bucket belongs to Red
+// Hat.
+func OIDCBucket(providerURL string) (bucket string, region string) {
+	matches := bucketHostRE.FindStringSubmatch(strings.TrimPrefix(providerURL, "https://"))
+	if len(matches) == 0 {
+		return "", ""
+	}
+	return matches[bucketHostRE.SubexpIndex("Bucket")], matches[bucketHostRE.SubexpIndex("Region")]
+}
@@ -0,0 +1,157 @@
+package orphans
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+)
+
+type fakeAWS struct {
+	roles        []aws.OperatorRoleResource
+	policies     []aws.OperatorPolicyResource
+	providers    []aws.OIDCProviderResource
+	accountRoles []aws.Role
+}
+
+func (f *fakeAWS) ListOperatorRoleResources() ([]aws.OperatorRoleResource, error) {
+	return f.roles, nil
+}
+
+func (f *fakeAWS) ListOperatorPolicyResources() ([]aws.OperatorPolicyResource, error) {
+	return f.policies, nil
+}
+
+func (f *fakeAWS) ListOpenIDConnectProviderResources() ([]aws.OIDCProviderResource, error) {
+	return f.providers, nil
+}
+
+func (f *fakeAWS) ListAccountRoles(version string) ([]aws.Role, error) {
+	return f.accountRoles, nil
+}
+
+type fakeOCM struct {
+	prefixes    map[string]bool
+	issuers     map[string]bool
+	oidcConfigs []*cmv1.OidcConfig
+	queries     []string
+}
+
+func (f *fakeOCM) HasAClusterUsingOperatorRolesPrefix(prefix string) (bool, error) {
+	f.queries = append(f.queries, prefix)
+	return f.prefixes[prefix], nil
+}
+
+func (f *fakeOCM) HasAClusterUsingOidcEndpointUrl(issuerURL string) (bool, error) {
+	return f.issuers[issuerURL], nil
+}
+
+func (f *fakeOCM) ListOidcConfigs(awsAccountID string) ([]*cmv1.OidcConfig, error) {
+	return f.oidcConfigs, nil
+}
+
+var _ = Describe("Find", func() {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	managed := map[string]string{tags.RedHatManaged: tags.True}
+
+	It("Reports the operator roles of clusters whose prefix isn't used by any cluster", func() {
+		cluster := map[string]string{tags.ClusterID: "123"}
+		awsClient := &fakeAWS{
+			roles: []aws.OperatorRoleResource{
+				{Name: "gone-a1b2-openshift-ingress-operator-cloud-credentials", CreatedAt: created,
+					Tags: cluster},
+				{Name: "gone-a1b2-kube-system-kube-controller-manager", CreatedAt: created, Tags: cluster},
+				{Name: "live-c3d4-openshift-ingress-operator-cloud-credentials", CreatedAt: created,
+					Tags: map[string]string{tags.ClusterID: "456"}},
+			},
+		}
+		ocmClient := &fakeOCM{prefixes: map[string]bool{"live-c3d4": true}}
+		resources, err := Find(awsClient, ocmClient, "123456789012")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0].Kind).To(Equal(OperatorRole))
+		Expect(resources[0].Name).To(Equal("gone-a1b2-kube-system-kube-controller-manager"))
+		Expect(resources[0].Prefix).To(Equal("gone-a1b2"))
+		Expect(resources[1].ClusterID).To(Equal("123"))
+		Expect(ocmClient.queries).To(ConsistOf("gone-a1b2", "live-c3d4"))
+	})
+
+	It("Doesn't report the operator roles created in advance for a cluster", func() {
+		awsClient := &fakeAWS{
+			roles: []aws.OperatorRoleResource{
+				{Name: "next-e5f6-openshift-ingress-operator-cloud-credentials", CreatedAt: created},
+			},
+		}
+		ocmClient := &fakeOCM{prefixes: map[string]bool{}}
+		resources, err := Find(awsClient, ocmClient, "123456789012")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(BeEmpty())
+		Expect(ocmClient.queries).To(BeEmpty())
+	})
+
+	It("Reports the operator policies that aren't attached to any role", func() {
+		awsClient := &fakeAWS{
+			policies: []aws.OperatorPolicyResource{
+				{Name: "ManagedOpenShift-openshift-ingress-operator-cloud-credentials", AttachmentCount: 0,
+					Tags: map[string]string{tags.RolePrefix: "ManagedOpenShift"}},
+				{Name: "Other-openshift-ingress-operator-cloud-credentials", AttachmentCount: 2},
+			},
+		}
+		resources, err := Find(awsClient, &fakeOCM{}, "123456789012")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(HaveLen(1))
+		Expect(resources[0].Kind).To(Equal(OperatorPolicy))
+		Expect(resources[0].Prefix).To(Equal("ManagedOpenShift"))
+	})
+
+	It("Doesn't report the operator policies of account roles that no cluster uses yet", func() {
+		awsClient := &fakeAWS{
+			policies: []aws.OperatorPolicyResource{
+				{Name: "ManagedOpenShift-openshift-ingress-operator-cloud-credentials", AttachmentCount: 0,
+					Tags: map[string]string{tags.RolePrefix: "ManagedOpenShift"}},
+				{Name: "Custom-openshift-ingress-operator-cloud-credentials", AttachmentCount: 0,
+					Tags: map[string]string{tags.RolePrefix: "Custom"}},
+				{Name: "Untagged-openshift-ingress-operator-cloud-credentials", AttachmentCount: 0},
+			},
+			accountRoles: []aws.Role{
+				{RoleName: "ManagedOpenShift-Installer-Role"},
+				{RoleName: "my-installer", RolePrefix: "Custom"},
+			},
+		}
+		resources, err := Find(awsClient, &fakeOCM{}, "123456789012")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(BeEmpty())
+	})
+
+	It("Reports the OIDC providers created by ROSA that no cluster or configuration uses", func() {
+		oidcConfig, err := cmv1.NewOidcConfig().
+			IssuerUrl("https://config-oidc-e5f6.s3.us-east-1.amazonaws.com").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		awsClient := &fakeAWS{
+			providers: []aws.OIDCProviderResource{
+				{URL: "rh-oidc.s3.us-east-1.amazonaws.com/live", Tags: managed},
+				{URL: "rh-oidc.s3.us-east-1.amazonaws.com/gone", Tags: managed},
+				{URL: "config-oidc-e5f6.s3.us-east-1.amazonaws.com", Tags: managed},
+				{URL: "gone-oidc-a1b2.s3.us-east-1.amazonaws.com", Tags: managed},
+				{URL: "token.actions.githubusercontent.com"},
+			},
+		}
+		ocmClient := &fakeOCM{
+			issuers:     map[string]bool{"https://rh-oidc.s3.us-east-1.amazonaws.com/live": true},
+			oidcConfigs: []*cmv1.OidcConfig{oidcConfig},
+		}
+		resources, err := Find(awsClient, ocmClient, "123456789012")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0].Name).To(Equal("gone-oidc-a1b2.s3.us-east-1.amazonaws.com"))
+		Expect(resources[0].Bucket).To(Equal("gone-oidc-a1b2"))
+		Expect(resources[0].Region).To(Equal("us-east-1"))
+		Expect(resources[1].Name).To(Equal("rh-oidc.s3.us-east-1.amazonaws.com/gone"))
+		Expect(resources[1].Bucket).To(BeEmpty())
+	})
+})
@@ -26,27 +26,49 @@ import (
 
 var o string
 
-var formats = []string{"json", "yaml"}
+var formats = []string{
+	"table",
+	"json",
+	"yaml",
+	"csv",
+	"jsonpath=",
+	"go-template=",
+	"custom-columns=",
+}
+
+// formatsWithArgument are the formats that require an argument, like 'jsonpath={.id}'.
+var formatsWithArgument = map[string]bool{
+	"jsonpath":       true,
+	"go-template":    true,
+	"custom-columns": true,
+}
 
-// AddFlag adds the interactive flag to the given set of command line flags.
-func AddFlag(cmd *cobra.Command) {
+// AddFlag adds the output flag to the given command. Commands that handle additional formats
+// themselves can pass them so that they are included in the help and the completions.
+func AddFlag(cmd *cobra.Command, extraFormats ...string) {
+	allowed := append(append([]string{}, formats...), extraFormats...)
 	cmd.Flags().StringVarP(
 		&o,
 		"output",
 		"o",
 		"",
-		fmt.Sprintf("Output format. Allowed formats are %s", formats),
+		fmt.Sprintf("Output format. Allowed formats are %s", allowed),
 	)
 
-	cmd.RegisterFlagCompletionFunc("output", completion)
+	cmd.RegisterFlagCompletionFunc("output", completion(allowed))
 }
 
-func completion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	return formats, cobra.ShellCompDirectiveDefault
+func completion(allowed []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return allowed, cobra.ShellCompDirectiveDefault
+	}
 }
 
+// HasFlag returns true if a format other than the default one has been selected with the
+// '--output' flag. The table format is the default, so commands that print a description of a
+// resource instead of a table print that description for '-o table' as well.
 func HasFlag() bool {
-	return o != ""
+	return o != "" && o != "table"
 }
 
 // Enabled retursn a boolean flag that indicates if the interactive mode is enabled.
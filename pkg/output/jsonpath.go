@@ -0,0 +1,322 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a JSONPath template engine that supports the subset of the syntax used by
+// 'kubectl': text, '{.field.field}', '{.list[0]}', '{.list[*].field}', '{.map.*}',
+// '{.field['key.with.dots']}', string literals like '{"\n"}' and '{range .list[*]}...{end}'.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+type step struct {
+	kind  stepKind
+	field string
+	index int
+}
+
+// path is a parsed JSONPath expression like '.items[*].id'.
+type path struct {
+	root  bool
+	steps []step
+}
+
+// node is an element of a parsed JSONPath template.
+type node struct {
+	text    string
+	path    *path
+	isRange bool
+	body    []*node
+}
+
+func parseTemplate(template string) ([]*node, error) {
+	root := &node{}
+	stack := []*node{root}
+	for len(template) > 0 {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			start = len(template)
+		}
+		current := stack[len(stack)-1]
+		if start > 0 {
+			current.body = append(current.body, &node{text: template[:start]})
+			template = template[start:]
+			continue
+		}
+		end := closingBrace(template)
+		if end < 0 {
+			return nil, fmt.Errorf("Unclosed action in template '%s'", template)
+		}
+		action := strings.TrimSpace(template[1:end])
+		template = template[end+1:]
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("Unexpected '{end}' without '{range}'")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(action, "range "):
+			expression, err := parsePath(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, err
+			}
+			child := &node{path: expression, isRange: true}
+			current.body = append(current.body, child)
+			stack = append(stack, child)
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, `'`):
+			text, err := unquote(action)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid string literal %s: %v", action, err)
+			}
+			current.body = append(current.body, &node{text: text})
+		default:
+			expression, err := parsePath(action)
+			if err != nil {
+				return nil, err
+			}
+			current.body = append(current.body, &node{path: expression})
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("Missing '{end}' for '{range}'")
+	}
+	return root.body, nil
+}
+
+// closingBrace returns the position of the brace that closes the action that starts at the
+// beginning of the given text, ignoring braces inside string literals.
+func closingBrace(text string) int {
+	var quote byte
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote != 0 && text[i] == '\\':
+			i++
+		case quote != 0 && text[i] == quote:
+			quote = 0
+		case quote != 0:
+		case text[i] == '"' || text[i] == '\'':
+			quote = text[i]
+		case text[i] == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(literal string) (string, error) {
+	if strings.HasPrefix(literal, "'") {
+		if len(literal) < 2 || !strings.HasSuffix(literal, "'") {
+			return "", fmt.Errorf("missing closing quote")
+		}
+		literal = `"` + strings.ReplaceAll(literal[1:len(literal)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(literal)
+}
+
+// parsePath parses an expression like '.items[*].id'. Expressions starting with '$' are
+// evaluated against the root of the document, other expressions against the current element.
+func parsePath(expression string) (*path, error) {
+	expression = strings.TrimSpace(expression)
+	result := &path{}
+	text := expression
+	switch {
+	case strings.HasPrefix(text, "$"):
+		result.root = true
+		text = text[1:]
+	case strings.HasPrefix(text, "@"):
+		text = text[1:]
+	}
+	if text != "" && text[0] != '.' && text[0] != '[' {
+		text = "." + text
+	}
+	for len(text) > 0 {
+		switch text[0] {
+		case '.':
+			text = text[1:]
+			if strings.HasPrefix(text, ".") {
+				return nil, fmt.Errorf("Recursive descent isn't supported in expression '%s'", expression)
+			}
+			end := strings.IndexAny(text, ".[")
+			if end < 0 {
+				end = len(text)
+			}
+			name := text[:end]
+			text = text[end:]
+			switch name {
+			case "":
+			case "*":
+				result.steps = append(result.steps, step{kind: stepWildcard})
+			default:
+				result.steps = append(result.steps, step{kind: stepField, field: name})
+			}
+		case '[':
+			end := strings.Index(text, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("Missing ']' in expression '%s'", expression)
+			}
+			subscript := strings.TrimSpace(text[1:end])
+			text = text[end+1:]
+			if subscript == "*" {
+				result.steps = append(result.steps, step{kind: stepWildcard})
+				continue
+			}
+			if strings.HasPrefix(subscript, "'") || strings.HasPrefix(subscript, `"`) {
+				field, err := unquote(subscript)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid key %s in expression '%s': %v", subscript, expression, err)
+				}
+				result.steps = append(result.steps, step{kind: stepField, field: field})
+				continue
+			}
+			index, err := strconv.Atoi(subscript)
+			if err != nil {
+				return nil, fmt.Errorf("Unsupported subscript '[%s]' in expression '%s'", subscript, expression)
+			}
+			result.steps = append(result.steps, step{kind: stepIndex, index: index})
+		default:
+			return nil, fmt.Errorf("Unexpected character '%c' in expression '%s'", text[0], expression)
+		}
+	}
+	return result, nil
+}
+
+// evaluate returns the values selected by the path. A field or an index that selects nothing is an
+// error, like in 'kubectl', unless allowMissing is true.
+func (p *path) evaluate(root, current interface{}, allowMissing bool) ([]interface{}, error) {
+	values := []interface{}{current}
+	if p.root {
+		values = []interface{}{root}
+	}
+	for _, s := range p.steps {
+		var next []interface{}
+		for _, value := range values {
+			switch typed := value.(type) {
+			case map[string]interface{}:
+				switch s.kind {
+				case stepField:
+					if child, ok := typed[s.field]; ok {
+						next = append(next, child)
+					}
+				case stepWildcard:
+					keys := make([]string, 0, len(typed))
+					for key := range typed {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, typed[key])
+					}
+				}
+			case []interface{}:
+				switch s.kind {
+				case stepIndex:
+					index := s.index
+					if index < 0 {
+						index += len(typed)
+					}
+					if index >= 0 && index < len(typed) {
+						next = append(next, typed[index])
+					}
+				case stepWildcard:
+					next = append(next, typed...)
+				}
+			}
+		}
+		if len(values) > 0 && len(next) == 0 && !allowMissing {
+			switch s.kind {
+			case stepField:
+				return nil, fmt.Errorf("Key '%s' is not found", s.field)
+			case stepIndex:
+				return nil, fmt.Errorf("Index %d is out of range", s.index)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// executeTemplate writes the result of evaluating the template against the given data.
+func executeTemplate(w io.Writer, nodes []*node, root, current interface{}) error {
+	for _, n := range nodes {
+		switch {
+		case n.isRange:
+			items, err := n.path.evaluate(root, current, false)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				err := executeTemplate(w, n.body, root, item)
+				if err != nil {
+					return err
+				}
+			}
+		case n.path != nil:
+			values, err := n.path.evaluate(root, current, false)
+			if err != nil {
+				return err
+			}
+			texts := make([]string, len(values))
+			for i, value := range values {
+				texts[i] = format(value)
+			}
+			_, err = io.WriteString(w, strings.Join(texts, " "))
+			if err != nil {
+				return err
+			}
+		default:
+			_, err := io.WriteString(w, n.text)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// format converts a value selected by a path to text. Objects and arrays are converted to JSON.
+func format(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		data, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprintf("%v", typed)
+		}
+		return string(data)
+	}
+}
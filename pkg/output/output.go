@@ -23,10 +23,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"reflect"
+	"os"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	msv1 "github.com/openshift-online/ocm-sdk-go/servicemgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
 	"gitlab.com/c0b/go-ordered-json"
 )
@@ -37,112 +39,147 @@ import (
 // that the output can be shown correctly.
 var emptyBuffer = []byte{91, 10, 32, 32, 10, 93}
 
-func Print(resource interface{}) error {
-	var b bytes.Buffer
-	switch reflect.TypeOf(resource).String() {
-	case "[]*v1.CloudRegion":
-		if cloudRegions, ok := resource.([]*cmv1.CloudRegion); ok {
-			cmv1.MarshalCloudRegionList(cloudRegions, &b)
-		}
-	case "*v1.Cluster":
-		if cluster, ok := resource.(*cmv1.Cluster); ok {
-			cmv1.MarshalCluster(cluster, &b)
-		}
-	case "[]*v1.Cluster":
-		if clusters, ok := resource.([]*cmv1.Cluster); ok {
-			cmv1.MarshalClusterList(clusters, &b)
-		}
-	case "[]*v1.IdentityProvider":
-		if idps, ok := resource.([]*cmv1.IdentityProvider); ok {
-			cmv1.MarshalIdentityProviderList(idps, &b)
-		}
-	case "[]*v1.Ingress":
-		if ingresses, ok := resource.([]*cmv1.Ingress); ok {
-			cmv1.MarshalIngressList(ingresses, &b)
-		}
-	case "[]*v1.MachinePool":
-		if machinePools, ok := resource.([]*cmv1.MachinePool); ok {
-			cmv1.MarshalMachinePoolList(machinePools, &b)
-		}
-	case "*v1.MachinePool":
-		if machinePool, ok := resource.(*cmv1.MachinePool); ok {
-			cmv1.MarshalMachinePool(machinePool, &b)
-		}
-	case "[]*v1.MachineType":
-		if machineTypes, ok := resource.([]*cmv1.MachineType); ok {
-			cmv1.MarshalMachineTypeList(machineTypes, &b)
-		}
-	case "*v1.NodePool":
-		if nodePool, ok := resource.(*cmv1.NodePool); ok {
-			cmv1.MarshalNodePool(nodePool, &b)
-		}
-	case "[]*v1.Version":
-		if versions, ok := resource.([]*cmv1.Version); ok {
-			cmv1.MarshalVersionList(versions, &b)
-		}
-	case "[]*v1.VersionGate":
-		if versionGate, ok := resource.(*cmv1.VersionGate); ok {
-			cmv1.MarshalVersionGate(versionGate, &b)
-		}
-	case "[]*v1.OidcConfig":
-		if oidcConfigs, ok := resource.([]*cmv1.OidcConfig); ok {
-			cmv1.MarshalOidcConfigList(oidcConfigs, &b)
-		}
-	case "*v1.OidcConfig":
-		if oidcConfig, ok := resource.(*cmv1.OidcConfig); ok {
-			cmv1.MarshalOidcConfig(oidcConfig, &b)
-		}
-	case "[]*v1.TuningConfig":
-		if tuningConfigs, ok := resource.([]*cmv1.TuningConfig); ok {
-			cmv1.MarshalTuningConfigList(tuningConfigs, &b)
-		}
-	case "*v1.TuningConfig":
-		if tuningConfig, ok := resource.(*cmv1.TuningConfig); ok {
-			cmv1.MarshalTuningConfig(tuningConfig, &b)
-		}
-	case "[]aws.Role":
-		{
-			if roles, ok := resource.([]aws.Role); ok {
-				err := aws.MarshalRoles(roles, &b)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	case "map[string][]aws.Role":
-		{
-			for _, operatorRoles := range resource.(map[string][]aws.Role) {
-				err := Print(operatorRoles)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	case "object.Object", "map[string]interface {}":
-		{
-			reqBodyBytes := new(bytes.Buffer)
-			json.NewEncoder(reqBodyBytes).Encode(resource)
-			err := json.Indent(&b, reqBodyBytes.Bytes(), "", "  ")
+func init() {
+	Register(&cmv1.AddOn{}, Type{
+		Marshal:     cmv1.MarshalAddOn,
+		MarshalList: cmv1.MarshalAddOnList,
+	})
+	Register(&cmv1.AddOnInstallation{}, Type{
+		Marshal:     cmv1.MarshalAddOnInstallation,
+		MarshalList: cmv1.MarshalAddOnInstallationList,
+	})
+	Register(&cmv1.CloudRegion{}, Type{
+		Marshal:     cmv1.MarshalCloudRegion,
+		MarshalList: cmv1.MarshalCloudRegionList,
+	})
+	Register(&cmv1.Cluster{}, Type{
+		Marshal:     cmv1.MarshalCluster,
+		MarshalList: cmv1.MarshalClusterList,
+	})
+	Register(&cmv1.ControlPlaneUpgradePolicy{}, Type{
+		Marshal:     cmv1.MarshalControlPlaneUpgradePolicy,
+		MarshalList: cmv1.MarshalControlPlaneUpgradePolicyList,
+	})
+	Register(&cmv1.IdentityProvider{}, Type{
+		Marshal:     cmv1.MarshalIdentityProvider,
+		MarshalList: cmv1.MarshalIdentityProviderList,
+	})
+	Register(&cmv1.Ingress{}, Type{
+		Marshal:     cmv1.MarshalIngress,
+		MarshalList: cmv1.MarshalIngressList,
+	})
+	Register(&cmv1.MachinePool{}, Type{
+		Marshal:     cmv1.MarshalMachinePool,
+		MarshalList: cmv1.MarshalMachinePoolList,
+	})
+	Register(&cmv1.MachineType{}, Type{
+		Marshal:     cmv1.MarshalMachineType,
+		MarshalList: cmv1.MarshalMachineTypeList,
+	})
+	Register(&cmv1.NodePool{}, Type{
+		Marshal:     cmv1.MarshalNodePool,
+		MarshalList: cmv1.MarshalNodePoolList,
+	})
+	Register(&cmv1.OidcConfig{}, Type{
+		Marshal:     cmv1.MarshalOidcConfig,
+		MarshalList: cmv1.MarshalOidcConfigList,
+	})
+	Register(&cmv1.TuningConfig{}, Type{
+		Marshal:     cmv1.MarshalTuningConfig,
+		MarshalList: cmv1.MarshalTuningConfigList,
+	})
+	Register(&cmv1.UpgradePolicy{}, Type{
+		Marshal:     cmv1.MarshalUpgradePolicy,
+		MarshalList: cmv1.MarshalUpgradePolicyList,
+	})
+	Register(&cmv1.User{}, Type{
+		Marshal:     cmv1.MarshalUser,
+		MarshalList: cmv1.MarshalUserList,
+	})
+	Register(&cmv1.Version{}, Type{
+		Marshal:     cmv1.MarshalVersion,
+		MarshalList: cmv1.MarshalVersionList,
+	})
+	Register(&cmv1.VersionGate{}, Type{
+		Marshal:     cmv1.MarshalVersionGate,
+		MarshalList: cmv1.MarshalVersionGateList,
+	})
+	Register(&msv1.ManagedService{}, Type{
+		Marshal:     msv1.MarshalManagedService,
+		MarshalList: msv1.MarshalManagedServiceList,
+	})
+	Register(aws.Role{}, Type{
+		MarshalList: aws.MarshalRoles,
+	})
+}
+
+// Print writes the given resource, or list of resources, to the standard output using the
+// format selected with the '--output' flag. The table format is used when no format has been
+// selected. The columns of the table and CSV formats are the given ones, or the ones registered
+// for the type of the resource if none are given, which is useful for types like 'aws.Role' that
+// are listed with different columns by different commands.
+func Print(resource interface{}, columns ...Column) error {
+	return Fprint(os.Stdout, resource, columns...)
+}
+
+// Fprint writes the given resource, or list of resources, to the given writer using the format
+// selected with the '--output' flag.
+func Fprint(w io.Writer, resource interface{}, columns ...Column) error {
+	// Operator roles are grouped by prefix, and each group is printed separately:
+	if groups, ok := resource.(map[string][]aws.Role); ok {
+		for _, operatorRoles := range groups {
+			err := Fprint(w, operatorRoles, columns...)
 			if err != nil {
 				return err
 			}
 		}
+		return nil
 	}
-	// Verify if the resource is an empty string and ensure that the JSON
-	// representation looks correct for STDOUT.
-	if b.String() == string(emptyBuffer) {
-		b = *bytes.NewBufferString("[]")
-	}
-	str, err := parseResource(b)
-	if err != nil {
+
+	format, argument := parseFormat(o)
+	switch format {
+	case "json", "yaml":
+		body, err := marshal(resource)
+		if err != nil {
+			return err
+		}
+		// Verify if the resource is an empty string and ensure that the JSON
+		// representation looks correct for STDOUT.
+		if bytes.Equal(body, emptyBuffer) {
+			body = []byte("[]")
+		}
+		str, err := parseResource(format, *bytes.NewBuffer(body))
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, str)
 		return err
+	case "", "table":
+		return printTable(w, resource, columns)
+	case "csv":
+		return printCSV(w, resource, columns)
+	case "custom-columns":
+		return printCustomColumns(w, resource, argument)
+	case "jsonpath":
+		return printJSONPath(w, resource, argument)
+	case "go-template":
+		return printGoTemplate(w, resource, argument)
+	default:
+		return fmt.Errorf("Unknown format '%s'. Valid formats are %s", o, formats)
+	}
+}
+
+// parseFormat splits a format like 'jsonpath={.id}' into the name of the format and its argument.
+func parseFormat(value string) (string, string) {
+	name, argument, found := strings.Cut(value, "=")
+	if !found || !formatsWithArgument[name] {
+		return value, ""
 	}
-	fmt.Print(str)
-	return nil
+	return name, argument
 }
 
-func parseResource(body bytes.Buffer) (string, error) {
-	switch o {
+func parseResource(format string, body bytes.Buffer) (string, error) {
+	switch format {
 	case "json":
 		var out bytes.Buffer
 		prettifyJSON(&out, body.Bytes())
@@ -154,7 +191,7 @@ func parseResource(body bytes.Buffer) (string, error) {
 		}
 		return string(out), nil
 	default:
-		return "", fmt.Errorf("Unknown format '%s'. Valid formats are %s", o, formats)
+		return "", fmt.Errorf("Unknown format '%s'. Valid formats are %s", format, formats)
 	}
 }
 
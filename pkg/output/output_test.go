@@ -0,0 +1,137 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+type fruit struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Count int    `json:"count"`
+}
+
+var _ = Describe("Print", func() {
+	fruits := []fruit{
+		{Name: "apple", Color: "red", Count: 3},
+		{Name: "banana", Color: "yellow", Count: 12},
+	}
+
+	BeforeEach(func() {
+		Register(fruit{}, Type{
+			Columns: []Column{
+				{Header: "NAME", Value: func(f fruit) string { return f.Name }},
+				{Header: "COLOR", Value: func(f fruit) string { return f.Color }},
+			},
+		})
+	})
+
+	AfterEach(func() {
+		o = ""
+	})
+
+	printAs := func(format string, resource interface{}, columns ...Column) (string, error) {
+		o = format
+		var b bytes.Buffer
+		err := Fprint(&b, resource, columns...)
+		return b.String(), err
+	}
+
+	It("Prints the registered columns as a table by default", func() {
+		text, err := printAs("", fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("NAME    COLOR\napple   red\nbanana  yellow\n"))
+	})
+
+	It("Prints the registered columns as CSV", func() {
+		text, err := printAs("csv", fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("NAME,COLOR\napple,red\nbanana,yellow\n"))
+	})
+
+	It("Prints the given columns instead of the registered ones", func() {
+		text, err := printAs("csv", fruits, Column{Header: "COUNT", Value: func(f fruit) string {
+			return fmt.Sprintf("%d", f.Count)
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("COUNT\n3\n12\n"))
+	})
+
+	It("Prints the fields of single resources without columns", func() {
+		text, err := printAs("csv", map[string]interface{}{
+			"name": "apple",
+			"tree": map[string]interface{}{"age": 7},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("FIELD,VALUE\nname,apple\ntree.age,7\n"))
+	})
+
+	It("Fails to print tables of lists without columns", func() {
+		_, err := printAs("table", []map[string]interface{}{{"name": "apple"}})
+		Expect(err).To(MatchError("Format 'table' isn't supported for this resource"))
+	})
+
+	It("Prints custom columns", func() {
+		text, err := printAs("custom-columns=FRUIT:.name,COUNT:{.count},SIZE:.size", fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("FRUIT   COUNT  SIZE\napple   3      <none>\nbanana  12     <none>\n"))
+	})
+
+	It("Prints JSONPath templates", func() {
+		text, err := printAs(`jsonpath={range .items[*]}{.name}{"\t"}{.count}{"\n"}{end}`, fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("apple\t3\nbanana\t12\n"))
+
+		text, err = printAs("jsonpath={.items[*].name} {.items[-1].color}", fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("apple banana yellow"))
+	})
+
+	It("Rejects invalid JSONPath templates", func() {
+		_, err := printAs("jsonpath={range .items[*]}{.name}", fruits)
+		Expect(err).To(MatchError("Missing '{end}' for '{range}'"))
+
+		_, err = printAs("jsonpath={..name}", fruits)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Fails when a JSONPath template selects a missing key", func() {
+		_, err := printAs("jsonpath={.items[*].size}", fruits)
+		Expect(err).To(MatchError("Key 'size' is not found"))
+
+		_, err = printAs("jsonpath={.items[5].name}", fruits)
+		Expect(err).To(MatchError("Index 5 is out of range"))
+	})
+
+	It("Prints Go templates", func() {
+		text, err := printAs(`go-template={{range .items}}{{.name}}={{.count}};{{end}}`, fruits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("apple=3;banana=12;"))
+	})
+
+	It("Uses the registered marshallers", func() {
+		cluster, err := cmv1.NewCluster().ID("123").Name("mycluster").Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		text, err := printAs("jsonpath={.id}/{.name}", cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("123/mycluster"))
+
+		text, err = printAs("yaml", []*cmv1.Cluster{cluster})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(ContainSubstring("- id: \"123\"\n"))
+
+		text, err = printAs("json", []*cmv1.Cluster{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(text).To(Equal("[]\n"))
+	})
+
+	It("Rejects unknown formats", func() {
+		_, err := printAs("xml", fruits)
+		Expect(err).To(MatchError(ContainSubstring("Unknown format 'xml'")))
+	})
+})
@@ -0,0 +1,236 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the printers of the formats other than JSON and YAML.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+func printTable(w io.Writer, resource interface{}, columns []Column) error {
+	headers, rows, err := tabulate(resource, columns, "table")
+	if err != nil {
+		return err
+	}
+	return writeTable(w, headers, rows)
+}
+
+func printCSV(w io.Writer, resource interface{}, columns []Column) error {
+	headers, rows, err := tabulate(resource, columns, "csv")
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	err = writer.Write(headers)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// tabulate calculates the headers and the rows of the table representation of the resource using
+// the given columns, or the columns registered for its type if none are given. A single resource
+// without columns, like the ones printed by the 'describe' commands, is tabulated as the fields of
+// its JSON representation and their values.
+func tabulate(resource interface{}, columns []Column, format string) ([]string, [][]string, error) {
+	key, values, isList := items(resource)
+	for _, column := range columns {
+		checkColumn(column, key)
+	}
+	if len(columns) == 0 {
+		columns = lookup(key).Columns
+	}
+	if len(columns) == 0 && !isList {
+		return tabulateFields(resource)
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("Format '%s' isn't supported for this resource", format)
+	}
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	rows := make([][]string, len(values))
+	for i, value := range values {
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = columnValue(column, value)
+		}
+	}
+	return headers, rows, nil
+}
+
+func tabulateFields(resource interface{}) ([]string, [][]string, error) {
+	data, err := genericData(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rows [][]string
+	flatten("", data, &rows)
+	return []string{"FIELD", "VALUE"}, rows, nil
+}
+
+// flatten adds a row for each value of the given data that isn't an object, named with the path of
+// fields that leads to it, like 'aws.sts.role_arn'.
+func flatten(name string, data interface{}, rows *[][]string) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		*rows = append(*rows, []string{name, format(data)})
+		return
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if name != "" {
+			flatten(name+"."+key, object[key], rows)
+		} else {
+			flatten(key, object[key], rows)
+		}
+	}
+}
+
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+// printCustomColumns prints a table with the columns given in a specification like
+// 'ID:.id,NAME:.name'. Each expression is evaluated against each item of the resource.
+func printCustomColumns(w io.Writer, resource interface{}, specification string) error {
+	if specification == "" {
+		return fmt.Errorf("Format 'custom-columns' requires a specification like 'ID:.id,NAME:.name'")
+	}
+	var headers []string
+	var paths []*path
+	for _, column := range strings.Split(specification, ",") {
+		header, expression, found := strings.Cut(column, ":")
+		if !found || header == "" {
+			return fmt.Errorf("Invalid custom column '%s', expected 'HEADER:EXPRESSION'", column)
+		}
+		expression = strings.TrimSuffix(strings.TrimPrefix(expression, "{"), "}")
+		parsed, err := parsePath(expression)
+		if err != nil {
+			return err
+		}
+		headers = append(headers, header)
+		paths = append(paths, parsed)
+	}
+	data, err := genericData(resource)
+	if err != nil {
+		return err
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+	rows := make([][]string, len(list))
+	for i, item := range list {
+		rows[i] = make([]string, len(paths))
+		for j, expression := range paths {
+			// Like in 'kubectl', missing values are shown as '<none>' instead of failing:
+			values, err := expression.evaluate(item, item, true)
+			if err != nil {
+				return err
+			}
+			texts := make([]string, len(values))
+			for k, value := range values {
+				texts[k] = format(value)
+			}
+			rows[i][j] = strings.Join(texts, ",")
+			if rows[i][j] == "" {
+				rows[i][j] = "<none>"
+			}
+		}
+	}
+	return writeTable(w, headers, rows)
+}
+
+// printJSONPath prints the result of a JSONPath template. Lists are available to the template in
+// the 'items' field, so that the same templates can be used as with 'kubectl'.
+func printJSONPath(w io.Writer, resource interface{}, text string) error {
+	nodes, err := parseTemplate(text)
+	if err != nil {
+		return err
+	}
+	data, err := templateData(resource)
+	if err != nil {
+		return err
+	}
+	return executeTemplate(w, nodes, data, data)
+}
+
+// printGoTemplate prints the result of a Go template. Lists are available to the template in the
+// 'items' field.
+func printGoTemplate(w io.Writer, resource interface{}, text string) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("Failed to parse template: %v", err)
+	}
+	data, err := templateData(resource)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+func templateData(resource interface{}) (interface{}, error) {
+	data, err := genericData(resource)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := data.([]interface{}); ok {
+		return map[string]interface{}{"items": list}, nil
+	}
+	return data, nil
+}
+
+// genericData converts the resource to the maps, slices and basic types that result from
+// decoding its JSON representation.
+func genericData(resource interface{}) (interface{}, error) {
+	body, err := marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	err = decoder.Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
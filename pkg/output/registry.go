@@ -0,0 +1,199 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the registry of the types of resources that can be printed.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Column describes a column of the table and CSV representations of a type of resource.
+type Column struct {
+	// Header is the text printed in the first row of the column.
+	Header string
+
+	// Value is a function with signature 'func(T) string' that calculates the value of the
+	// column for a resource of type T.
+	Value interface{}
+}
+
+// Type describes how the resources of a type are printed.
+type Type struct {
+	// Marshal is an optional function with signature 'func(T, io.Writer) error' that writes the
+	// JSON representation of a resource, like the ones generated in the SDK. When not set the
+	// resource is marshalled with the 'encoding/json' package.
+	Marshal interface{}
+
+	// MarshalList is an optional function with signature 'func([]T, io.Writer) error' that writes
+	// the JSON representation of a list of resources. When not set the list is marshalled as an
+	// array of the representations of its items.
+	MarshalList interface{}
+
+	// Columns are the columns of the table and CSV representations. Formats that need them fail
+	// for types that don't have columns.
+	Columns []Column
+}
+
+var (
+	typesLock sync.Mutex
+	types     = map[reflect.Type]*Type{}
+)
+
+var bufferType = reflect.TypeOf(&bytes.Buffer{})
+
+// Register registers how resources of the same type as the given example are printed. Fields
+// that aren't set in the description keep the values of previous registrations, so that the
+// marshallers and the columns of a type can be registered separately. It panics if any of the
+// functions doesn't have the expected signature.
+func Register(example interface{}, description Type) {
+	key := reflect.TypeOf(example)
+	checkFunc("Marshal", description.Marshal, key)
+	checkFunc("MarshalList", description.MarshalList, reflect.SliceOf(key))
+	for _, column := range description.Columns {
+		checkColumn(column, key)
+	}
+
+	typesLock.Lock()
+	defer typesLock.Unlock()
+	current, ok := types[key]
+	if !ok {
+		current = &Type{}
+		types[key] = current
+	}
+	if description.Marshal != nil {
+		current.Marshal = description.Marshal
+	}
+	if description.MarshalList != nil {
+		current.MarshalList = description.MarshalList
+	}
+	if description.Columns != nil {
+		current.Columns = description.Columns
+	}
+}
+
+func checkFunc(name string, function interface{}, key reflect.Type) {
+	if function == nil {
+		return
+	}
+	t := reflect.TypeOf(function)
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 1 ||
+		t.In(0) != key || !bufferType.AssignableTo(t.In(1)) ||
+		t.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+		panic(fmt.Sprintf("%s function for type '%s' has unexpected signature '%s'", name, key, t))
+	}
+}
+
+func checkColumn(column Column, key reflect.Type) {
+	t := reflect.TypeOf(column.Value)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 1 ||
+		t.In(0) != key || t.Out(0).Kind() != reflect.String {
+		panic(fmt.Sprintf("Value function of column '%s' for type '%s' has unexpected signature '%s'",
+			column.Header, key, t))
+	}
+}
+
+// lookup returns the description registered for the given type, or an empty description if
+// there is none.
+func lookup(key reflect.Type) Type {
+	typesLock.Lock()
+	defer typesLock.Unlock()
+	if description, ok := types[key]; ok {
+		return *description
+	}
+	return Type{}
+}
+
+// items returns the element type and the items of the given resource. A resource that isn't a
+// slice is returned as a list containing only itself.
+func items(resource interface{}) (reflect.Type, []reflect.Value, bool) {
+	value := reflect.ValueOf(resource)
+	if value.Kind() != reflect.Slice {
+		return value.Type(), []reflect.Value{value}, false
+	}
+	result := make([]reflect.Value, value.Len())
+	for i := range result {
+		result[i] = value.Index(i)
+	}
+	return value.Type().Elem(), result, true
+}
+
+// marshal writes the JSON representation of the given resource, using the marshallers registered
+// for its type when there are any.
+func marshal(resource interface{}) ([]byte, error) {
+	key, values, isList := items(resource)
+	description := lookup(key)
+	var b bytes.Buffer
+	switch {
+	case isList && description.MarshalList != nil:
+		list := reflect.ValueOf(resource)
+		if list.IsNil() {
+			list = reflect.MakeSlice(list.Type(), 0, 0)
+		}
+		err := call(description.MarshalList, list, &b)
+		if err != nil {
+			return nil, err
+		}
+	case isList && description.Marshal != nil:
+		b.WriteString("[")
+		for i, value := range values {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			err := call(description.Marshal, value, &b)
+			if err != nil {
+				return nil, err
+			}
+		}
+		b.WriteString("]")
+	case !isList && description.Marshal != nil:
+		err := call(description.Marshal, values[0], &b)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if isList && reflect.ValueOf(resource).IsNil() {
+			return []byte("[]"), nil
+		}
+		data, err := json.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Indent(&b, data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func call(function interface{}, value reflect.Value, b *bytes.Buffer) error {
+	result := reflect.ValueOf(function).Call([]reflect.Value{value, reflect.ValueOf(b)})
+	if err, ok := result[0].Interface().(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// columnValue calculates the value of a column for the given item.
+func columnValue(column Column, item reflect.Value) string {
+	return reflect.ValueOf(column.Value).Call([]reflect.Value{item})[0].String()
+}
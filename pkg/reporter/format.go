@@ -0,0 +1,191 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--log-format' command line option.
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	errors "github.com/zgalor/weberr"
+)
+
+// LogFormatEnv is the environment variable that sets the log format when the '--log-format' flag
+// isn't used.
+const LogFormatEnv = "ROSA_LOG_FORMAT"
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+var logFormat string
+
+var logFormats = []string{TextFormat, JSONFormat}
+
+// commandPath and clusterKey are added to the messages in JSON format, so that automation can
+// tell which command and cluster they are about.
+var commandPath string
+var clusterKey string
+
+// AddFlag adds the log format flag to the given command and its subcommands.
+func AddFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&logFormat,
+		"log-format",
+		"",
+		fmt.Sprintf("Format of the messages printed by the tool. Allowed options are %s. "+
+			"Defaults to the value of the %s environment variable, or '%s'", logFormats, LogFormatEnv,
+			TextFormat),
+	)
+
+	cmd.RegisterFlagCompletionFunc("log-format", logFormatCompletion)
+}
+
+func logFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string,
+	cobra.ShellCompDirective) {
+	return logFormats, cobra.ShellCompDirectiveDefault
+}
+
+// LogFormat returns the format of the messages, either text or JSON.
+func LogFormat() string {
+	format := logFormat
+	if format == "" {
+		format = os.Getenv(LogFormatEnv)
+	}
+	if format == JSONFormat {
+		return JSONFormat
+	}
+	return TextFormat
+}
+
+// ValidateLogFormat returns an error if the log format given in the flag or the environment
+// variable isn't supported.
+func ValidateLogFormat() error {
+	format := logFormat
+	if format == "" {
+		format = os.Getenv(LogFormatEnv)
+	}
+	switch format {
+	case "", TextFormat, JSONFormat:
+		return nil
+	}
+	return fmt.Errorf("Invalid log format '%s'. Allowed options are %s", format, logFormats)
+}
+
+// SetCommandPath sets the command that is running, for example 'rosa create cluster'.
+func SetCommandPath(path string) {
+	commandPath = path
+}
+
+// SetClusterKey sets the name, identifier or external identifier of the cluster that the command
+// is running for.
+func SetClusterKey(key string) {
+	clusterKey = key
+}
+
+// entry is a message in JSON format.
+type entry struct {
+	Level     string    `json:"level"`
+	Timestamp time.Time `json:"timestamp"`
+	Command   string    `json:"command,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	// Code is the stable code set by the caller with Object.WithCode, if any.
+	Code string `json:"code,omitempty"`
+	// MessageID identifies the message independently of its arguments. It is derived from the
+	// text of the message, so it isn't stable, see messageID.
+	MessageID string `json:"message_id"`
+	Message   string `json:"message"`
+	// ErrorType is the type of the error that is reported, which is the HTTP status code that
+	// corresponds to it, or zero if the error doesn't have a type.
+	ErrorType *errors.ErrorType `json:"error_type,omitempty"`
+}
+
+// Codes of the messages that automation is most likely to act on. They are set explicitly with
+// Object.WithCode and are the only values of the 'code' field of the JSON messages, so unlike the
+// message identifiers they are guaranteed not to change when the messages are reworded.
+const (
+	CodeInvalidClusterKey = "invalid-cluster-key"
+	CodeGetCluster        = "get-cluster-failed"
+	CodeCreateCluster     = "create-cluster-failed"
+	CodeDeleteCluster     = "delete-cluster-failed"
+	CodeScheduleUpgrade   = "schedule-upgrade-failed"
+	CodeOCMConnection     = "ocm-connection-failed"
+	CodeAWSClient         = "aws-client-failed"
+	CodeAWSCreator        = "aws-creator-failed"
+)
+
+// idPrefixLength is the maximum length of the beginning of an expanded message that is used to
+// compute its identifier.
+const idPrefixLength = 64
+
+// messageID returns the identifier of the message with the given level, format and expanded text.
+// The identifier is a hash of the format, so it doesn't depend on the arguments of the message.
+// Formats that start with a verb, like the '%s' used to report errors as they are, carry no text
+// of their own, so for them the hash is computed from the beginning of the expanded message
+// instead, up to the first colon or quote, which usually start the parts of the message that vary.
+// Identifiers aren't stable: they change whenever the text they are computed from changes, so
+// they aren't part of the 'code' field, and messages that automation acts on must be given one of
+// the codes above with Object.WithCode.
+func messageID(level string, format string, message string) string {
+	text := format
+	if strings.HasPrefix(format, "%") {
+		text = message
+		if i := strings.IndexAny(text, ":'\""); i >= 0 {
+			text = text[:i]
+		}
+		if len(text) > idPrefixLength {
+			text = text[:idPrefixLength]
+		}
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(text))
+	return fmt.Sprintf("%s-%08x", level, hash.Sum32())
+}
+
+// writeJSON writes the message to the writer as a JSON object in a single line. The code is
+// omitted when it is empty.
+func writeJSON(writer io.Writer, level string, code string, format string, args []interface{}) {
+	message := entry{
+		Level:     level,
+		Timestamp: time.Now().UTC(),
+		Command:   commandPath,
+		Cluster:   clusterKey,
+		Code:      code,
+		Message:   fmt.Sprintf(format, args...),
+	}
+	message.MessageID = messageID(level, format, message.Message)
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			errorType := errors.GetType(err)
+			message.ErrorType = &errorType
+			break
+		}
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(writer, "%s\n", data)
+}
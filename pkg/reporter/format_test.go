@@ -0,0 +1,120 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	errors "github.com/zgalor/weberr"
+)
+
+var _ = Describe("Log format", func() {
+	AfterEach(func() {
+		logFormat = ""
+		commandPath = ""
+		clusterKey = ""
+		os.Unsetenv(LogFormatEnv)
+	})
+
+	Context("LogFormat", func() {
+		It("Uses the environment variable when the flag isn't set", func() {
+			os.Setenv(LogFormatEnv, JSONFormat)
+			Expect(LogFormat()).To(Equal(JSONFormat))
+			Expect(ValidateLogFormat()).To(Succeed())
+		})
+
+		It("Prefers the flag over the environment variable", func() {
+			os.Setenv(LogFormatEnv, JSONFormat)
+			logFormat = TextFormat
+			Expect(LogFormat()).To(Equal(TextFormat))
+		})
+
+		It("Rejects unknown formats", func() {
+			logFormat = "xml"
+			Expect(ValidateLogFormat()).To(MatchError("Invalid log format 'xml'. Allowed options are [text json]"))
+		})
+	})
+
+	Context("writeJSON", func() {
+		It("Writes the message with the command, the cluster and the error type", func() {
+			SetCommandPath("rosa describe cluster")
+			SetClusterKey("mycluster")
+			buffer := &bytes.Buffer{}
+
+			writeJSON(buffer, "error", "", "Failed to get cluster '%s': %v",
+				[]interface{}{"mycluster", errors.NotFound.Errorf("Cluster not found")})
+
+			Expect(buffer.String()).To(HaveSuffix("}\n"))
+			var message map[string]interface{}
+			Expect(json.Unmarshal(buffer.Bytes(), &message)).To(Succeed())
+			Expect(message).To(HaveKeyWithValue("level", "error"))
+			Expect(message).To(HaveKey("timestamp"))
+			Expect(message).To(HaveKeyWithValue("command", "rosa describe cluster"))
+			Expect(message).To(HaveKeyWithValue("cluster", "mycluster"))
+			Expect(message).To(HaveKeyWithValue("message", "Failed to get cluster 'mycluster': Cluster not found"))
+			Expect(message).To(HaveKeyWithValue("error_type", float64(404)))
+		})
+
+		It("Doesn't add an error type to messages without errors", func() {
+			buffer := &bytes.Buffer{}
+
+			writeJSON(buffer, "info", "", "Cluster '%s' is ready", []interface{}{"mycluster"})
+
+			var message map[string]interface{}
+			Expect(json.Unmarshal(buffer.Bytes(), &message)).To(Succeed())
+			Expect(message).NotTo(HaveKey("error_type"))
+			Expect(message).NotTo(HaveKey("cluster"))
+		})
+
+		It("Uses the code given by the caller", func() {
+			buffer := &bytes.Buffer{}
+
+			writeJSON(buffer, "error", CodeGetCluster, "%s", []interface{}{"Cluster not found"})
+
+			var message map[string]interface{}
+			Expect(json.Unmarshal(buffer.Bytes(), &message)).To(Succeed())
+			Expect(message).To(HaveKeyWithValue("code", CodeGetCluster))
+			Expect(message).To(HaveKey("message_id"))
+		})
+
+		It("Doesn't derive the code from the message", func() {
+			buffer := &bytes.Buffer{}
+
+			writeJSON(buffer, "error", "", "Failed to get cluster '%s'", []interface{}{"mycluster"})
+
+			var message map[string]interface{}
+			Expect(json.Unmarshal(buffer.Bytes(), &message)).To(Succeed())
+			Expect(message).NotTo(HaveKey("code"))
+			Expect(message).To(HaveKeyWithValue("message_id", MatchRegexp(`^error-[0-9a-f]{8}$`)))
+		})
+	})
+
+	Context("messageID", func() {
+		It("Doesn't depend on the arguments of the message", func() {
+			format := "Cluster '%s' is ready"
+			Expect(messageID("info", format, fmt.Sprintf(format, "a"))).To(
+				Equal(messageID("info", format, fmt.Sprintf(format, "b"))))
+			Expect(messageID("info", format, "")).To(MatchRegexp(`^info-[0-9a-f]{8}$`))
+			Expect(messageID("info", format, "")).NotTo(Equal(messageID("warning", format, "")))
+		})
+
+		It("Uses the beginning of the message when the format starts with a verb", func() {
+			Expect(messageID("error", "%s", "Failed to get cluster 'a': not found")).To(
+				Equal(messageID("error", "%s", "Failed to get cluster 'b': forbidden")))
+			Expect(messageID("error", "%s", "Failed to get cluster 'a'")).NotTo(
+				Equal(messageID("error", "%s", "Invalid cluster key 'a'")))
+		})
+	})
+
+	Context("WithCode", func() {
+		It("Counts the errors in the reporter that it was created from", func() {
+			r := &Object{}
+			os.Setenv(LogFormatEnv, JSONFormat)
+			r.WithCode(CodeGetCluster).WithCode(CodeCreateCluster).Errorf("Failed")
+			Expect(r.Errors()).To(Equal(1))
+		})
+	})
+})
@@ -34,6 +34,14 @@ type Builder struct {
 // error streams.
 type Object struct {
 	errors int
+
+	// code is the stable code of the messages printed in JSON format, or empty if they don't have
+	// one.
+	code string
+
+	// parent is the reporter that this one was created from with WithCode, and that counts the
+	// errors reported via this one.
+	parent *Object
 }
 
 // New creates a builder that can then be used to configure and build a reporter.
@@ -49,16 +57,37 @@ func (b *Builder) Build() (result *Object, err error) {
 	return
 }
 
+// WithCode returns a reporter that prints the messages with the given stable code when the log
+// format is JSON. Errors reported via the returned reporter are also counted by this one.
+func (r *Object) WithCode(code string) *Object {
+	root := r
+	if r.parent != nil {
+		root = r.parent
+	}
+	return &Object{
+		code:   code,
+		parent: root,
+	}
+}
+
 // Debugf prints a debug message with the given format and arguments.
 func (r *Object) Debugf(format string, args ...interface{}) {
 	if !debug.Enabled() {
 		return
 	}
+	if LogFormat() == JSONFormat {
+		writeJSON(os.Stdout, "debug", r.code, format, args)
+		return
+	}
 	r.Infof(format, args...)
 }
 
 // Infof prints an informative message with the given format and arguments.
 func (r *Object) Infof(format string, args ...interface{}) {
+	if LogFormat() == JSONFormat {
+		writeJSON(os.Stdout, "info", r.code, format, args)
+		return
+	}
 	message := fmt.Sprintf(format, args...)
 	if color.UseColor() {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", infoPrefix, message)
@@ -69,6 +98,10 @@ func (r *Object) Infof(format string, args ...interface{}) {
 
 // Warnf prints an warning message with the given format and arguments.
 func (r *Object) Warnf(format string, args ...interface{}) {
+	if LogFormat() == JSONFormat {
+		writeJSON(os.Stderr, "warning", r.code, format, args)
+		return
+	}
 	message := fmt.Sprintf(format, args...)
 	if color.UseColor() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", warnPrefix, message)
@@ -82,17 +115,28 @@ func (r *Object) Warnf(format string, args ...interface{}) {
 // report the error and also return it.
 func (r *Object) Errorf(format string, args ...interface{}) error {
 	message := fmt.Sprintf(format, args...)
+	if r.parent != nil {
+		r.parent.errors++
+	} else {
+		r.errors++
+	}
+	if LogFormat() == JSONFormat {
+		writeJSON(os.Stderr, "error", r.code, format, args)
+		return errors.New(message)
+	}
 	if color.UseColor() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", errorPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", "ERR: ", message)
 	}
-	r.errors++
 	return errors.New(message)
 }
 
 // Errors returns the number of errors that have been reported via this reporter.
 func (r *Object) Errors() int {
+	if r.parent != nil {
+		return r.parent.Errors()
+	}
 	return r.errors
 }
 
@@ -44,7 +44,7 @@ func (r *Runtime) WithAWS() *Runtime {
 		var err error
 		r.Creator, err = r.AWSClient.GetCreator()
 		if err != nil {
-			r.Reporter.Errorf("Failed to get AWS creator: %v", err)
+			r.Reporter.WithCode(reporter.CodeAWSCreator).Errorf("Failed to get AWS creator: %v", err)
 			os.Exit(1)
 		}
 	}
@@ -63,10 +63,11 @@ func (r *Runtime) Cleanup() {
 func (r *Runtime) GetClusterKey() string {
 	clusterKey, err := ocm.GetClusterKey()
 	if err != nil {
-		r.Reporter.Errorf("%s", err)
+		r.Reporter.WithCode(reporter.CodeInvalidClusterKey).Errorf("%s", err)
 		os.Exit(1)
 	}
 	r.ClusterKey = clusterKey
+	reporter.SetClusterKey(clusterKey)
 	return clusterKey
 }
 
@@ -90,7 +91,7 @@ func (r *Runtime) FetchCluster() *cmv1.Cluster {
 	r.Reporter.Debugf("Loading cluster '%s'", r.ClusterKey)
 	cluster, err := r.OCMClient.GetCluster(r.ClusterKey, r.Creator)
 	if err != nil {
-		r.Reporter.Errorf("Failed to get cluster '%s': %v", r.ClusterKey, err)
+		r.Reporter.WithCode(reporter.CodeGetCluster).Errorf("Failed to get cluster '%s': %v", r.ClusterKey, err)
 		os.Exit(1)
 	}
 	r.Cluster = cluster
@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the command line flags and exit codes shared by the 'rosa wait' commands.
+
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/reporter"
+)
+
+const (
+	// ExitTimeout is the exit code used when the timeout expires before the condition is met.
+	ExitTimeout = 2
+
+	// ExitFailed is the exit code used when the resource reaches a state from which the condition
+	// can't be met, like a cluster in error state.
+	ExitFailed = 3
+)
+
+// condition and timeout are the values of the flags of the command that runs. They are loaded from
+// the flags before running the command, as the flags of the several commands can't share variables:
+// each command would overwrite them with its own default when it is created.
+var condition string
+var timeout time.Duration
+
+// AddFlags adds the '--for' and '--timeout' flags to the given command. The first of the conditions
+// is the default.
+func AddFlags(cmd *cobra.Command, conditions []string) {
+	cmd.Flags().String(
+		"for",
+		conditions[0],
+		fmt.Sprintf("Condition to wait for. Allowed values are %s", conditions),
+	)
+	cmd.RegisterFlagCompletionFunc("for", func(*cobra.Command, []string, string) ([]string,
+		cobra.ShellCompDirective) {
+		return conditions, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().Duration(
+		"timeout",
+		time.Hour,
+		"Maximum time to wait, for example '30m' or '2h'. Use '0' to wait forever.",
+	)
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		var err error
+		condition, err = cmd.Flags().GetString("for")
+		if err != nil {
+			return err
+		}
+		timeout, err = cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+		if !helper.Contains(conditions, condition) {
+			return fmt.Errorf("Invalid condition '%s', allowed values are %s", condition, conditions)
+		}
+		return nil
+	}
+}
+
+// Condition returns the condition given with the '--for' flag.
+func Condition() string {
+	return condition
+}
+
+// NewPoller returns a poller that uses the timeout given in the command line and reports each change
+// of the state of the resource, and each error that is retried.
+func NewPoller(r *reporter.Object) *Poller {
+	last := ""
+	return &Poller{
+		Timeout: timeout,
+		Backoff: DefaultBackoff,
+		Retries: DefaultRetries,
+		OnError: func(err error) {
+			r.Warnf("Failed to check the state, retrying: %v", err)
+		},
+		OnWaiting: func(message string) {
+			if message != last {
+				r.Infof("Waiting for %s: %s", condition, message)
+				last = message
+			}
+		},
+	}
+}
+
+// Exit reports the result of Poll and exits with the code that corresponds to it. The description
+// identifies the resource, for example "Cluster 'mycluster'".
+func Exit(r *reporter.Object, err error, description string) {
+	if err == nil {
+		r.Infof("%s is %s", description, condition)
+		os.Exit(0)
+	}
+	var failed *FailedError
+	switch {
+	case errors.Is(err, ErrTimeout):
+		r.Errorf("%s didn't become %s: %v", description, condition, err)
+		os.Exit(ExitTimeout)
+	case errors.As(err, &failed):
+		r.Errorf("%s can't become %s: %v", description, condition, err)
+		os.Exit(ExitFailed)
+	default:
+		r.Errorf("Failed to wait for %s: %v", description, err)
+		os.Exit(1)
+	}
+}
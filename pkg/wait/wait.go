@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wait polls the state of a resource until a condition is met, the resource reaches a state
+// from which the condition can't be met anymore, or a timeout expires.
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Result is the outcome of a single check of a condition.
+type Result int
+
+const (
+	// Waiting means that the condition isn't met yet, but may be met later.
+	Waiting Result = iota
+
+	// Met means that the condition is met.
+	Met
+
+	// Failed means that the resource reached a state from which the condition will never be met.
+	Failed
+)
+
+// Check evaluates a condition once. The returned message describes the current state of the resource
+// and is used for progress reports and errors. Returning an error stops the polling once the poller
+// runs out of retries.
+type Check func() (result Result, message string, err error)
+
+// ErrTimeout is returned by Poll when the condition wasn't met before the timeout expired.
+var ErrTimeout = errors.New("timed out")
+
+// FailedError is returned by Poll when the check reports that the condition can't be met anymore.
+type FailedError struct {
+	Message string
+}
+
+func (e *FailedError) Error() string {
+	return e.Message
+}
+
+// Backoff controls the time between checks. The first wait is Initial, and each following one is
+// multiplied by Factor up to Max.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+// DefaultBackoff is suitable for resources that take minutes to change state, like clusters.
+var DefaultBackoff = Backoff{
+	Initial: 5 * time.Second,
+	Max:     time.Minute,
+	Factor:  1.5,
+}
+
+func (b Backoff) next(current time.Duration) time.Duration {
+	if current == 0 {
+		return b.Initial
+	}
+	next := time.Duration(float64(current) * b.Factor)
+	if next > b.Max {
+		return b.Max
+	}
+	return next
+}
+
+// DefaultRetries is the number of consecutive failed checks tolerated by default, so that a transient
+// error, like a network glitch, doesn't end a wait that may last hours.
+const DefaultRetries = 5
+
+// Poller runs a check repeatedly until the condition is met, it fails or the timeout expires.
+type Poller struct {
+	Timeout time.Duration
+	Backoff Backoff
+
+	// Retries is the number of consecutive checks that can return an error before polling stops.
+	Retries int
+
+	// OnError, when set, is called with each error of a check that will be retried.
+	OnError func(err error)
+
+	// OnWaiting, when set, is called with the message of each check whose condition isn't met yet.
+	OnWaiting func(message string)
+
+	// Used by the tests to avoid waiting:
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// Poll runs the check until it returns Met, in which case it returns nil. It returns a *FailedError
+// if the check returns Failed, the error of the check if it fails more than Retries consecutive
+// times, and an error wrapping ErrTimeout if the timeout expires first. A zero timeout means waiting
+// forever.
+func (p *Poller) Poll(check Check) error {
+	now := p.now
+	if now == nil {
+		now = time.Now
+	}
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	var deadline time.Time
+	if p.Timeout > 0 {
+		deadline = now().Add(p.Timeout)
+	}
+	var delay time.Duration
+	errorCount := 0
+	for {
+		result, message, err := check()
+		if err != nil {
+			errorCount++
+			if errorCount > p.Retries {
+				return err
+			}
+			if p.OnError != nil {
+				p.OnError(err)
+			}
+			result = Waiting
+		} else {
+			errorCount = 0
+		}
+		switch result {
+		case Met:
+			return nil
+		case Failed:
+			return &FailedError{Message: message}
+		}
+		if p.OnWaiting != nil && err == nil {
+			p.OnWaiting(message)
+		}
+		delay = p.Backoff.next(delay)
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(now())
+			if remaining <= 0 {
+				return fmt.Errorf("%w after %s, last state: %s", ErrTimeout, p.Timeout, message)
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+		sleep(delay)
+	}
+}
@@ -0,0 +1,86 @@
+package wait
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Poller", func() {
+	var clock time.Time
+	var delays []time.Duration
+	var poller *Poller
+
+	BeforeEach(func() {
+		clock = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		delays = nil
+		poller = &Poller{
+			Timeout: time.Minute,
+			Backoff: Backoff{Initial: 10 * time.Second, Max: 20 * time.Second, Factor: 2},
+			now: func() time.Time {
+				return clock
+			},
+			sleep: func(delay time.Duration) {
+				delays = append(delays, delay)
+				clock = clock.Add(delay)
+			},
+		}
+	})
+
+	It("Waits with backoff until the condition is met", func() {
+		checks := 0
+		err := poller.Poll(func() (Result, string, error) {
+			checks++
+			if checks == 4 {
+				return Met, "ready", nil
+			}
+			return Waiting, "installing", nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(delays).To(Equal([]time.Duration{10 * time.Second, 20 * time.Second, 20 * time.Second}))
+	})
+
+	It("Stops when the condition can't be met", func() {
+		err := poller.Poll(func() (Result, string, error) {
+			return Failed, "cluster is in error state", nil
+		})
+		var failed *FailedError
+		Expect(errors.As(err, &failed)).To(BeTrue())
+		Expect(failed.Message).To(Equal("cluster is in error state"))
+	})
+
+	It("Retries a bounded number of consecutive errors", func() {
+		poller.Retries = 2
+		checks := 0
+		err := poller.Poll(func() (Result, string, error) {
+			checks++
+			if checks == 2 || checks == 3 {
+				return Waiting, "", errors.New("connection reset")
+			}
+			if checks == 4 {
+				return Met, "ready", nil
+			}
+			return Waiting, "installing", nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		checks = 0
+		err = poller.Poll(func() (Result, string, error) {
+			checks++
+			return Waiting, "", errors.New("unauthorized")
+		})
+		Expect(err).To(MatchError("unauthorized"))
+		Expect(checks).To(Equal(3))
+	})
+
+	It("Times out", func() {
+		err := poller.Poll(func() (Result, string, error) {
+			return Waiting, "installing", nil
+		})
+		Expect(errors.Is(err, ErrTimeout)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("last state: installing"))
+		Expect(clock).To(Equal(time.Date(2023, 1, 1, 0, 1, 0, 0, time.UTC)))
+	})
+})